@@ -4,26 +4,41 @@ import (
 	_ "app/api/docs"
 	"app/api/handler"
 	"app/config"
+	"app/pkg/jwtkey"
 	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/pkg/tracing"
 	"app/storage"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewApi(r *gin.Engine, cfg *config.Config, store storage.StorageI, cache storage.CacheStorageI, logger logger.LoggerI) {
+func NewApi(r *gin.Engine, cfg *config.Config, store storage.StorageI, cache storage.CacheStorageI, keys *jwtkey.KeySet, logger logger.LoggerI) {
 
-	handler := handler.NewHandler(cfg, store, cache, logger)
+	handler := handler.NewHandler(cfg, store, cache, keys, logger)
 
 	// @securityDefinitions.apikey ApiKeyAuth
 	// @in header
 	// @name Authorization
 
-	r.Use(customMiddleware())
+	// @securityDefinitions.apikey IntegrationKeyAuth
+	// @in header
+	// @name X-API-Key
+
+	r.Use(handler.RequestLogMiddleware(), tracing.Middleware(), customMiddleware(), metrics.Middleware(), handler.TimeoutMiddleware(), handler.APIKeyMiddleware(), handler.RateLimitMiddleware("api", cfg.RateLimitAPI))
+	//HEALTH
+	r.GET("/health", handler.Health)
+	r.GET("/health/live", handler.Liveness)
+	r.GET("/health/ready", handler.Readiness)
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	//AUTH
-	r.POST("/register", handler.Register)
-	r.POST("/login", handler.Login)
+	r.POST("/register", handler.RateLimitMiddleware("auth", cfg.RateLimitAuth), handler.Register)
+	r.POST("/login", handler.RateLimitMiddleware("auth", cfg.RateLimitAuth), handler.Login)
+	r.GET("/.well-known/jwks.json", handler.JWKS)
 
 	//USER
 	r.POST("/user", handler.CreateUser)
@@ -55,6 +70,7 @@ func NewApi(r *gin.Engine, cfg *config.Config, store storage.StorageI, cache sto
 	r.PUT("/product/:id", handler.UpdateProduct)
 	r.PATCH("/product/:id", handler.UpdatePatchProduct)
 	r.DELETE("/product/:id", handler.DeleteProduct)
+	r.GET("/product/:id/availability", handler.GetProductAvailability)
 
 	//CUSTOMER
 	r.POST("/customer", handler.CreateCustomer)
@@ -63,6 +79,8 @@ func NewApi(r *gin.Engine, cfg *config.Config, store storage.StorageI, cache sto
 	r.PUT("/customer/:id", handler.UpdateCustomer)
 	r.PATCH("/customer/:id", handler.UpdatePatchCustomer)
 	r.DELETE("/customer/:id", handler.DeleteCustomer)
+	r.GET("/customer/:id/orders", handler.GetCustomerOrders)
+	r.GET("/customer/:id/summary", handler.GetCustomerSummary)
 
 	//STORE
 	r.POST("/store", handler.CreateStore)
@@ -71,6 +89,9 @@ func NewApi(r *gin.Engine, cfg *config.Config, store storage.StorageI, cache sto
 	r.PUT("/store/:id", handler.UpdateStore)
 	r.PATCH("/store/:id", handler.UpdatePatchStore)
 	r.DELETE("/store/:id", handler.DeleteStore)
+	r.GET("/store/:id/stock", handler.GetStoreStock)
+	r.GET("/store/:id/staff", handler.GetStoreStaff)
+	r.GET("/store/:id/performance", handler.StorePerformance)
 
 	//STAFF
 	r.POST("/staff", handler.CreateStaff)
@@ -79,24 +100,58 @@ func NewApi(r *gin.Engine, cfg *config.Config, store storage.StorageI, cache sto
 	r.PUT("/staff/:id", handler.UpdateStaff)
 	r.PATCH("/staff/:id", handler.UpdatePatchStaff)
 	r.DELETE("/staff/:id", handler.DeleteStaff)
+	r.GET("/staff/:id/managers", handler.GetStaffManagers)
+	r.GET("/staff/:id/reports", handler.GetStaffReports)
+	r.PUT("/staff/:id/store", handler.ReassignStaff)
+	r.GET("/staff/:id/performance", handler.StaffPerformance)
 
 	//ORDER
-	r.POST("/order", handler.CreateOrder)
+	r.POST("/order", handler.IdempotencyMiddleware(), handler.CreateOrder)
 	r.GET("/order/:id", handler.GetByIdOrder)
 	r.GET("/order", handler.GetListOrder)
 	r.PUT("/order/:id", handler.UpdateOrder)
 	r.PATCH("/order/:id", handler.UpdatePatchOrder)
 	r.DELETE("/order/:id", handler.DeleteOrder)
-	r.POST("/order_item", handler.CreateOrderItem)
+	r.POST("/order_item", handler.IdempotencyMiddleware(), handler.CreateOrderItem)
 	r.DELETE("/order_item/:id", handler.DeleteOrderItem)
+	r.POST("/order_return", handler.IdempotencyMiddleware(), handler.CreateOrderReturn)
+	r.GET("/order_return/:id", handler.GetByIdOrderReturn)
+	r.GET("/order_return", handler.GetListOrderReturn)
 
 	//STOCK
-	r.POST("/stock", handler.CreateStock)
-	r.GET("/stock/:id", handler.GetByIdStock)
+	r.POST("/stock", handler.IdempotencyMiddleware(), handler.CreateStock)
+	r.GET("/stock/:store_id/:product_id", handler.GetByIdStock)
 	r.GET("/stock", handler.GetListStock)
-	r.PUT("/stock/:id", handler.UpdateStock)
-	r.PATCH("/stock/:id", handler.UpdatePatchStock)
-	r.DELETE("/stock/:id", handler.DeleteStock)
+	r.PUT("/stock/:store_id/:product_id", handler.UpdateStock)
+	r.PATCH("/stock/:store_id/:product_id", handler.UpdatePatchStock)
+	r.DELETE("/stock/:store_id/:product_id", handler.DeleteStock)
+	r.POST("/stock/transfer", handler.IdempotencyMiddleware(), handler.TransferStock)
+
+	//WEBHOOK
+	r.POST("/webhook", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.CreateWebhook)
+	r.GET("/webhook/:id", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.GetByIdWebhook)
+	r.GET("/webhook", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.GetListWebhook)
+	r.PUT("/webhook/:id", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.UpdateWebhook)
+	r.DELETE("/webhook/:id", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.DeleteWebhook)
+	r.GET("/webhook/:id/deliveries", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.GetWebhookDeliveries)
+
+	//API KEY
+	r.POST("/api_key", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.CreateAPIKey)
+	r.GET("/api_key/:id", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.GetByIdAPIKey)
+	r.GET("/api_key", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.GetListAPIKey)
+	r.PUT("/api_key/:id", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.UpdateAPIKey)
+	r.DELETE("/api_key/:id", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.RevokeAPIKey)
+
+	//ADMIN
+	r.GET("/admin/integrity", handler.AuthMiddleware(), handler.AdminMiddleware(), handler.IntegrityCheck)
+
+	//REPORT
+	r.GET("/report/sales", handler.SalesReport)
+	r.GET("/report/overdue", handler.OverdueReport)
+
+	//STOCK MOVEMENT
+	r.GET("/stock_movement", handler.GetListStockMovement)
+	r.GET("/stock_movement/reconcile", handler.ReconcileStock)
 
 	url := ginSwagger.URL("swagger/doc.json") // The url pointing to API definition
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
@@ -107,7 +162,7 @@ func customMiddleware() gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE, HEAD")
-		c.Header("Access-Control-Allow-Headers", "Platform-Id, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Platform-Id, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key")
 		c.Header("Access-Control-Max-Age", "3600")
 
 		if c.Request.Method == "OPTIONS" {
@@ -16,14 +16,35 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/brand": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "The public keys verifying the access tokens by kid, the HS256 keys are not published",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Login"
+                ],
+                "summary": "Token Keys",
+                "operationId": "jwks",
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "$ref": "#/definitions/jwtkey.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/integrity": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get List Brand",
+                "description": "Negative stock, sold products without a stock row in the order's store, orders sold by staff of another store, staff manager cycles, orphaned ledger rows and stocks off their ledger. Admin role only",
                 "consumes": [
                     "application/json"
                 ],
@@ -31,34 +52,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Brand"
+                    "Admin"
                 ],
-                "summary": "Get List Brand",
-                "operationId": "get_list_brand",
+                "summary": "Integrity Check",
+                "operationId": "integrity_check",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
+                        "description": "true adds the SQL fixing the issues that have a mechanical fix",
+                        "name": "fix_sql",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Authorization",
-                        "name": "Authorization",
-                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -73,7 +76,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.IntegrityReport"
                                         }
                                     }
                                 }
@@ -98,6 +101,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server Error",
                         "schema": {
@@ -117,14 +138,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api_key": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create Brand",
+                "description": "Get List API Key with the revoked ones. Admin role only",
                 "consumes": [
                     "application/json"
                 ],
@@ -132,19 +155,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Brand"
+                    "API Key"
                 ],
-                "summary": "Create Brand",
-                "operationId": "create_brand",
+                "summary": "Get List API Key",
+                "operationId": "get_list_api_key",
                 "parameters": [
                     {
-                        "description": "CreateBrandRequest",
-                        "name": "brand",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateBrand"
-                        }
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -159,7 +185,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.GetListAPIKeyResponse"
                                         }
                                     }
                                 }
@@ -184,6 +210,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server Error",
                         "schema": {
@@ -203,16 +247,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/brand/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Brand",
+                "description": "Create a key for an integration, sent in the X-API-Key header. The key is only returned here. Scopes are \u003cresource\u003e:read or \u003cresource\u003e:write of catalog, customers, stores, orders, stock and reports; a store_id restricts the key to that store and expires_at is RFC 3339. Admin role only",
                 "consumes": [
                     "application/json"
                 ],
@@ -220,27 +262,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Brand"
+                    "API Key"
                 ],
-                "summary": "Get By ID Brand",
-                "operationId": "get_by_id_brand",
+                "summary": "Create API Key",
+                "operationId": "create_api_key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Authorization",
-                        "name": "Authorization",
-                        "in": "path"
+                        "description": "CreateAPIKeyRequest",
+                        "name": "api_key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAPIKey"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "201": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -251,7 +289,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.CreateAPIKeyResponse"
                                         }
                                     }
                                 }
@@ -276,6 +314,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server Error",
                         "schema": {
@@ -295,14 +351,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api_key/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Put Brand",
+                "description": "Get By ID API Key. Admin role only",
                 "consumes": [
                     "application/json"
                 ],
@@ -310,10 +368,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Brand"
+                    "API Key"
                 ],
-                "summary": "Update Put Brand",
-                "operationId": "updat_patch_brand",
+                "summary": "Get By ID API Key",
+                "operationId": "get_by_id_api_key",
                 "parameters": [
                     {
                         "type": "string",
@@ -321,15 +379,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "UpdateBrand",
-                        "name": "brand",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateBrand"
-                        }
                     }
                 ],
                 "responses": {
@@ -344,7 +393,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.APIKey"
                                         }
                                     }
                                 }
@@ -369,6 +418,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server Error",
                         "schema": {
@@ -389,13 +456,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Brand",
+                "description": "Update the name, scopes, store and expiry of a key not revoked, the key itself does not change. Admin role only",
                 "consumes": [
                     "application/json"
                 ],
@@ -403,10 +470,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Brand"
+                    "API Key"
                 ],
-                "summary": "Delete Brand",
-                "operationId": "get_by_id_brand",
+                "summary": "Update API Key",
+                "operationId": "update_api_key",
                 "parameters": [
                     {
                         "type": "string",
@@ -414,10 +481,19 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "UpdateAPIKeyRequest",
+                        "name": "api_key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateAPIKey"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "202": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -428,7 +504,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.APIKey"
                                         }
                                     }
                                 }
@@ -453,6 +529,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server Error",
                         "schema": {
@@ -473,13 +567,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Patch Brand",
+                "description": "Revoke API Key, its next request is refused. The key stays in the list with its revoked_at. Admin role only",
                 "consumes": [
                     "application/json"
                 ],
@@ -487,10 +581,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Brand"
+                    "API Key"
                 ],
-                "summary": "Update Patch Brand",
-                "operationId": "updat_patch_brand",
+                "summary": "Revoke API Key",
+                "operationId": "revoke_api_key",
                 "parameters": [
                     {
                         "type": "string",
@@ -498,19 +592,10 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "UpdatPatchBrandRequest",
-                        "name": "brand",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PatchRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "202": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -546,6 +631,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server Error",
                         "schema": {
@@ -567,14 +670,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/category": {
+        "/brand": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get List Category",
+                "description": "Get List Brand",
                 "consumes": [
                     "application/json"
                 ],
@@ -582,10 +685,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Brand"
                 ],
-                "summary": "Get List Category",
-                "operationId": "get_list_category",
+                "summary": "Get List Brand",
+                "operationId": "get_list_brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -604,10 +707,16 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -669,7 +778,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create Category",
+                "description": "Create Brand",
                 "consumes": [
                     "application/json"
                 ],
@@ -677,18 +786,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Brand"
                 ],
-                "summary": "Create Category",
-                "operationId": "create_category",
+                "summary": "Create Brand",
+                "operationId": "create_brand",
                 "parameters": [
                     {
-                        "description": "CreateCategoryRequest",
-                        "name": "category",
+                        "description": "CreateBrandRequest",
+                        "name": "brand",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateCategory"
+                            "$ref": "#/definitions/models.CreateBrand"
                         }
                     }
                 ],
@@ -750,14 +859,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/category/{id}": {
+        "/brand/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Category",
+                "description": "Get By ID Brand",
                 "consumes": [
                     "application/json"
                 ],
@@ -765,10 +874,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Brand"
                 ],
-                "summary": "Get By ID Category",
-                "operationId": "get_by_id_category",
+                "summary": "Get By ID Brand",
+                "operationId": "get_by_id_brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -776,6 +885,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "path"
                     }
                 ],
                 "responses": {
@@ -841,7 +956,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Put Category",
+                "description": "Update Put Brand",
                 "consumes": [
                     "application/json"
                 ],
@@ -849,10 +964,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Brand"
                 ],
-                "summary": "Update Put Category",
-                "operationId": "updat_patch_category",
+                "summary": "Update Put Brand",
+                "operationId": "updat_patch_brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -862,12 +977,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdateCategory",
-                        "name": "category",
+                        "description": "UpdateBrand",
+                        "name": "brand",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateCategory"
+                            "$ref": "#/definitions/models.UpdateBrand"
                         }
                     }
                 ],
@@ -934,7 +1049,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Category",
+                "description": "Delete Brand",
                 "consumes": [
                     "application/json"
                 ],
@@ -942,10 +1057,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Brand"
                 ],
-                "summary": "Delete Category",
-                "operationId": "get_by_id_category",
+                "summary": "Delete Brand",
+                "operationId": "get_by_id_brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -1018,7 +1133,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Patch Category",
+                "description": "Update Patch Brand",
                 "consumes": [
                     "application/json"
                 ],
@@ -1026,10 +1141,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Brand"
                 ],
-                "summary": "Update Patch Category",
-                "operationId": "updat_patch_category",
+                "summary": "Update Patch Brand",
+                "operationId": "updat_patch_brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -1039,8 +1154,8 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdatPatchCategoryRequest",
-                        "name": "category",
+                        "description": "UpdatPatchBrandRequest",
+                        "name": "brand",
                         "in": "body",
                         "required": true,
                         "schema": {
@@ -1106,9 +1221,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/customer": {
+        "/category": {
             "get": {
-                "description": "Get List Customer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get List Category",
                 "consumes": [
                     "application/json"
                 ],
@@ -1116,10 +1236,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Category"
                 ],
-                "summary": "Get List Customer",
-                "operationId": "get_list_customer",
+                "summary": "Get List Category",
+                "operationId": "get_list_category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1198,7 +1318,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create Customer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create Category",
                 "consumes": [
                     "application/json"
                 ],
@@ -1206,18 +1331,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Category"
                 ],
-                "summary": "Create Customer",
-                "operationId": "create_customer",
+                "summary": "Create Category",
+                "operationId": "create_category",
                 "parameters": [
                     {
-                        "description": "CreateCustomerRequest",
-                        "name": "customer",
+                        "description": "CreateCategoryRequest",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateCustomer"
+                            "$ref": "#/definitions/models.CreateCategory"
                         }
                     }
                 ],
@@ -1279,9 +1404,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/customer/{id}": {
+        "/category/{id}": {
             "get": {
-                "description": "Get By ID Customer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get By ID Category",
                 "consumes": [
                     "application/json"
                 ],
@@ -1289,10 +1419,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Category"
                 ],
-                "summary": "Get By ID Customer",
-                "operationId": "get_by_id_customer",
+                "summary": "Get By ID Category",
+                "operationId": "get_by_id_category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1360,7 +1490,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update Put Customer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Put Category",
                 "consumes": [
                     "application/json"
                 ],
@@ -1368,10 +1503,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Category"
                 ],
-                "summary": "Update Put Customer",
-                "operationId": "updat_patch_customer",
+                "summary": "Update Put Category",
+                "operationId": "updat_patch_category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1381,12 +1516,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdateCustomer",
-                        "name": "customer",
+                        "description": "UpdateCategory",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateCustomer"
+                            "$ref": "#/definitions/models.UpdateCategory"
                         }
                     }
                 ],
@@ -1448,7 +1583,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Customer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Category",
                 "consumes": [
                     "application/json"
                 ],
@@ -1456,10 +1596,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Category"
                 ],
-                "summary": "Delete Customer",
-                "operationId": "get_by_id_customer",
+                "summary": "Delete Category",
+                "operationId": "get_by_id_category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1527,7 +1667,12 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Update Patch Customer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Patch Category",
                 "consumes": [
                     "application/json"
                 ],
@@ -1535,10 +1680,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Category"
                 ],
-                "summary": "Update Patch Customer",
-                "operationId": "updat_patch_customer",
+                "summary": "Update Patch Category",
+                "operationId": "updat_patch_category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1548,8 +1693,8 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdatPatchCustomerRequest",
-                        "name": "customer",
+                        "description": "UpdatPatchCategoryRequest",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
@@ -1615,9 +1760,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Create Login",
+        "/customer": {
+            "get": {
+                "description": "Get List Customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -1625,23 +1770,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Login"
+                    "Customer"
                 ],
-                "summary": "Create Login",
-                "operationId": "login",
+                "summary": "Get List Customer",
+                "operationId": "get_list_customer",
                 "parameters": [
                     {
-                        "description": "LoginRequest",
-                        "name": "logim",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Login"
-                        }
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -1696,16 +1850,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/order": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get List Order",
+            },
+            "post": {
+                "description": "Create Customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -1713,28 +1860,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Customer"
                 ],
-                "summary": "Get List Order",
-                "operationId": "get_list_order",
+                "summary": "Create Customer",
+                "operationId": "create_customer",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "CreateCustomerRequest",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCustomer"
+                        }
                     }
                 ],
                 "responses": {
@@ -1793,14 +1931,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Create Order",
+            }
+        },
+        "/customer/{id}": {
+            "get": {
+                "description": "Get By ID Customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -1808,19 +1943,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Customer"
                 ],
-                "summary": "Create Order",
-                "operationId": "create_order",
+                "summary": "Get By ID Customer",
+                "operationId": "get_by_id_customer",
                 "parameters": [
                     {
-                        "description": "CreateOrderRequest",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateOrder"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1879,16 +2012,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/order/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get By ID Order",
+            },
+            "put": {
+                "description": "Update Put Customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -1896,10 +2022,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Customer"
                 ],
-                "summary": "Get By ID Order",
-                "operationId": "get_by_id_order",
+                "summary": "Update Put Customer",
+                "operationId": "updat_patch_customer",
                 "parameters": [
                     {
                         "type": "string",
@@ -1907,6 +2033,15 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "UpdateCustomer",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateCustomer"
+                        }
                     }
                 ],
                 "responses": {
@@ -1966,13 +2101,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Update Put Order",
+            "delete": {
+                "description": "Delete Customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -1980,10 +2110,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Customer"
                 ],
-                "summary": "Update Put Order",
-                "operationId": "updat_patch_order",
+                "summary": "Delete Customer",
+                "operationId": "get_by_id_customer",
                 "parameters": [
                     {
                         "type": "string",
@@ -1991,15 +2121,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "UpdateOrder",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateOrder"
-                        }
                     }
                 ],
                 "responses": {
@@ -2059,13 +2180,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Delete Order",
+            "patch": {
+                "description": "Update Patch Customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -2073,10 +2189,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Customer"
                 ],
-                "summary": "Delete Order",
-                "operationId": "get_by_id_order",
+                "summary": "Update Patch Customer",
+                "operationId": "updat_patch_customer",
                 "parameters": [
                     {
                         "type": "string",
@@ -2084,6 +2200,15 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "UpdatPatchCustomerRequest",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -2142,14 +2267,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Update Patch Order",
+            }
+        },
+        "/customer/{id}/orders": {
+            "get": {
+                "description": "Order history of the customer, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -2157,10 +2279,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Customer"
                 ],
-                "summary": "Update Patch Order",
-                "operationId": "updat_patch_order",
+                "summary": "Get Customer Orders",
+                "operationId": "get_customer_orders",
                 "parameters": [
                     {
                         "type": "string",
@@ -2170,13 +2292,22 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdatPatchOrderRequest",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PatchRequest"
-                        }
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated statuses: pending, processing, rejected, completed or 1-4",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2191,7 +2322,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.GetListOrderResponse"
                                         }
                                     }
                                 }
@@ -2237,14 +2368,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/order_item": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Create Order Item",
+        "/customer/{id}/summary": {
+            "get": {
+                "description": "Order count, lifetime spend, last order date and favourite store of the customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -2252,23 +2378,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Customer"
                 ],
-                "summary": "Create Order Item",
-                "operationId": "create_order_item",
+                "summary": "Get Customer Summary",
+                "operationId": "get_customer_summary",
                 "parameters": [
                     {
-                        "description": "CreateOrder_itemRequest",
-                        "name": "order_item",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateOrder_item"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -2279,7 +2403,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.CustomerSummary"
                                         }
                                     }
                                 }
@@ -2325,14 +2449,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/order_item/{id}": {
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
+        "/health": {
+            "get": {
+                "description": "Service health, the status is degraded while the cache is down and reads are served from postgresql",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health",
+                "operationId": "health",
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "The process is up and serving requests",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness",
+                "operationId": "health_live",
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
                     }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Pings postgresql and redis, the service is not ready without postgresql and degraded without redis",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
                 ],
-                "description": "Delete Order Item",
+                "summary": "Readiness",
+                "operationId": "health_ready",
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Create Login",
                 "consumes": [
                     "application/json"
                 ],
@@ -2340,37 +2528,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Login"
                 ],
-                "summary": "Delete Order Item",
-                "operationId": "delete_order_item",
+                "summary": "Create Login",
+                "operationId": "login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "item_id",
-                        "name": "item_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "description": "DeleteOrderItemRequest",
-                        "name": "orderItem",
+                        "description": "LoginRequest",
+                        "name": "logim",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.OrderItemPrimaryKey"
+                            "$ref": "#/definitions/models.Login"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
+                    "201": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -2406,8 +2580,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Server Error",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "allOf": [
                                 {
@@ -2423,13 +2597,36 @@ const docTemplate = `{
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/product": {
-            "get": {
-                "description": "Get List Product",
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/order": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get List Order",
                 "consumes": [
                     "application/json"
                 ],
@@ -2437,10 +2634,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Order"
                 ],
-                "summary": "Get List Product",
-                "operationId": "get_list_product",
+                "summary": "Get List Order",
+                "operationId": "get_list_order",
                 "parameters": [
                     {
                         "type": "string",
@@ -2459,6 +2656,18 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "overdue or at_risk, open orders past their required_date or due soon",
+                        "name": "delivery",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2519,7 +2728,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create Product",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create Order",
                 "consumes": [
                     "application/json"
                 ],
@@ -2527,19 +2741,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Order"
                 ],
-                "summary": "Create Product",
-                "operationId": "create_product",
+                "summary": "Create Order",
+                "operationId": "create_order",
                 "parameters": [
                     {
-                        "description": "CreateProductRequest",
-                        "name": "product",
+                        "description": "CreateOrderRequest",
+                        "name": "order",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateProduct"
+                            "$ref": "#/definitions/models.CreateOrder"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the first response of a retried request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -2600,9 +2820,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/{id}": {
+        "/order/{id}": {
             "get": {
-                "description": "Get By ID Product",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get By ID Order",
                 "consumes": [
                     "application/json"
                 ],
@@ -2610,10 +2835,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Order"
                 ],
-                "summary": "Get By ID Product",
-                "operationId": "get_by_id_product",
+                "summary": "Get By ID Order",
+                "operationId": "get_by_id_order",
                 "parameters": [
                     {
                         "type": "string",
@@ -2681,7 +2906,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update Put Product",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Put Order",
                 "consumes": [
                     "application/json"
                 ],
@@ -2689,10 +2919,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Order"
                 ],
-                "summary": "Update Put Product",
-                "operationId": "updat_patch_product",
+                "summary": "Update Put Order",
+                "operationId": "updat_patch_order",
                 "parameters": [
                     {
                         "type": "string",
@@ -2702,12 +2932,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdateProduct",
-                        "name": "product",
+                        "description": "UpdateOrder",
+                        "name": "order",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateProduct"
+                            "$ref": "#/definitions/models.UpdateOrder"
                         }
                     }
                 ],
@@ -2769,7 +2999,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Product",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Order, an order with returns is kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -2777,10 +3012,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Order"
                 ],
-                "summary": "Delete Product",
-                "operationId": "get_by_id_product",
+                "summary": "Delete Order",
+                "operationId": "get_by_id_order",
                 "parameters": [
                     {
                         "type": "string",
@@ -2827,6 +3062,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server Error",
                         "schema": {
@@ -2848,7 +3101,12 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Update Patch Product",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Patch Order",
                 "consumes": [
                     "application/json"
                 ],
@@ -2856,10 +3114,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Order"
                 ],
-                "summary": "Update Patch Product",
-                "operationId": "updat_patch_product",
+                "summary": "Update Patch Order",
+                "operationId": "updat_patch_order",
                 "parameters": [
                     {
                         "type": "string",
@@ -2869,8 +3127,8 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdatPatchProductRequest",
-                        "name": "product",
+                        "description": "UpdatPatchOrderRequest",
+                        "name": "order",
                         "in": "body",
                         "required": true,
                         "schema": {
@@ -2936,9 +3194,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/register": {
+        "/order_item": {
             "post": {
-                "description": "Create Register",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create Order Item",
                 "consumes": [
                     "application/json"
                 ],
@@ -2946,19 +3209,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Register"
+                    "Order"
                 ],
-                "summary": "Create Register",
-                "operationId": "register",
+                "summary": "Create Order Item",
+                "operationId": "create_order_item",
                 "parameters": [
                     {
-                        "description": "CreateRegisterRequest",
-                        "name": "register",
+                        "description": "CreateOrder_itemRequest",
+                        "name": "order_item",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Register"
+                            "$ref": "#/definitions/models.CreateOrder_item"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the first response of a retried request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -3019,9 +3288,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/staff": {
-            "get": {
-                "description": "Get List Staff",
+        "/order_item/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Order Item entered by mistake, sold products are returned with POST /order_return. An item with returns is kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -3029,32 +3303,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "Order"
                 ],
-                "summary": "Get List Staff",
-                "operationId": "get_list_staff",
+                "summary": "Delete Order Item",
+                "operationId": "delete_order_item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "item_id",
+                        "name": "item_id",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "DeleteOrderItemRequest",
+                        "name": "orderItem",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderItemPrimaryKey"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "204": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -3090,8 +3369,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "allOf": [
                                 {
@@ -3107,72 +3386,9 @@ const docTemplate = `{
                                 }
                             ]
                         }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create Staff",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Staff"
-                ],
-                "summary": "Create Staff",
-                "operationId": "create_staff",
-                "parameters": [
-                    {
-                        "description": "CreateStaffRequest",
-                        "name": "staff",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateStaff"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success Request",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Server Error",
+                    },
+                    "500": {
+                        "description": "Server Error",
                         "schema": {
                             "allOf": [
                                 {
@@ -3192,9 +3408,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/staff/{id}": {
+        "/order_return": {
             "get": {
-                "description": "Get By ID Staff",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get List Order Return",
                 "consumes": [
                     "application/json"
                 ],
@@ -3202,17 +3423,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "Order"
                 ],
-                "summary": "Get By ID Staff",
-                "operationId": "get_by_id_staff",
+                "summary": "Get List Order Return",
+                "operationId": "get_list_order_return",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "order_id",
+                        "name": "order_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3227,7 +3459,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.GetListOrderReturnResponse"
                                         }
                                     }
                                 }
@@ -3272,8 +3504,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update Put Staff",
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Return a quantity of an order item, the product is restocked into the store of the order",
                 "consumes": [
                     "application/json"
                 ],
@@ -3281,109 +3518,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "Order"
                 ],
-                "summary": "Update Put Staff",
-                "operationId": "updat_patch_staff",
+                "summary": "Create Order Return",
+                "operationId": "create_order_return",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "UpdateStaff",
-                        "name": "staff",
+                        "description": "CreateOrderReturnRequest",
+                        "name": "order_return",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateStaff"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success Request",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/models.CreateOrderReturn"
                         }
                     },
-                    "500": {
-                        "description": "Server Error",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Delete Staff",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Staff"
-                ],
-                "summary": "Delete Staff",
-                "operationId": "get_by_id_staff",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "replays the first response of a retried request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "201": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -3394,7 +3551,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.OrderReturn"
                                         }
                                     }
                                 }
@@ -3438,9 +3595,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Update Patch Staff",
+            }
+        },
+        "/order_return/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get By ID Order Return",
                 "consumes": [
                     "application/json"
                 ],
@@ -3448,10 +3612,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "Order"
                 ],
-                "summary": "Update Patch Staff",
-                "operationId": "updat_patch_staff",
+                "summary": "Get By ID Order Return",
+                "operationId": "get_by_id_order_return",
                 "parameters": [
                     {
                         "type": "string",
@@ -3459,15 +3623,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "UpdatPatchStaffRequest",
-                        "name": "staff",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PatchRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -3482,7 +3637,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.OrderReturn"
                                         }
                                     }
                                 }
@@ -3528,9 +3683,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/stock": {
+        "/product": {
             "get": {
-                "description": "Get List Stock",
+                "description": "Get List Product",
                 "consumes": [
                     "application/json"
                 ],
@@ -3538,10 +3693,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Stock"
+                    "Product"
                 ],
-                "summary": "Get List Stock",
-                "operationId": "get_list_stock",
+                "summary": "Get List Product",
+                "operationId": "get_list_product",
                 "parameters": [
                     {
                         "type": "string",
@@ -3620,7 +3775,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create Stock",
+                "description": "Create Product",
                 "consumes": [
                     "application/json"
                 ],
@@ -3628,18 +3783,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Stock"
+                    "Product"
                 ],
-                "summary": "Create Stock",
-                "operationId": "create_stock",
+                "summary": "Create Product",
+                "operationId": "create_product",
                 "parameters": [
                     {
-                        "description": "CreateStockRequest",
-                        "name": "stock",
+                        "description": "CreateProductRequest",
+                        "name": "product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateStock"
+                            "$ref": "#/definitions/models.CreateProduct"
                         }
                     }
                 ],
@@ -3701,9 +3856,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/stock/{id}": {
+        "/product/{id}": {
             "get": {
-                "description": "Get By ID Stock",
+                "description": "Get By ID Product",
                 "consumes": [
                     "application/json"
                 ],
@@ -3711,10 +3866,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Stock"
+                    "Product"
                 ],
-                "summary": "Get By ID Stock",
-                "operationId": "get_by_id_stock",
+                "summary": "Get By ID Product",
+                "operationId": "get_by_id_product",
                 "parameters": [
                     {
                         "type": "string",
@@ -3782,7 +3937,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update Put Stock",
+                "description": "Update Put Product",
                 "consumes": [
                     "application/json"
                 ],
@@ -3790,10 +3945,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Stock"
+                    "Product"
                 ],
-                "summary": "Update Put Stock",
-                "operationId": "updat_patch_stock",
+                "summary": "Update Put Product",
+                "operationId": "updat_patch_product",
                 "parameters": [
                     {
                         "type": "string",
@@ -3803,12 +3958,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdateStock",
-                        "name": "stock",
+                        "description": "UpdateProduct",
+                        "name": "product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateStock"
+                            "$ref": "#/definitions/models.UpdateProduct"
                         }
                     }
                 ],
@@ -3870,7 +4025,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Stock",
+                "description": "Delete Product",
                 "consumes": [
                     "application/json"
                 ],
@@ -3878,10 +4033,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Stock"
+                    "Product"
                 ],
-                "summary": "Delete Stock",
-                "operationId": "get_by_id_stock",
+                "summary": "Delete Product",
+                "operationId": "get_by_id_product",
                 "parameters": [
                     {
                         "type": "string",
@@ -3949,7 +4104,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Update Patch Stock",
+                "description": "Update Patch Product",
                 "consumes": [
                     "application/json"
                 ],
@@ -3957,10 +4112,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Stock"
+                    "Product"
                 ],
-                "summary": "Update Patch Stock",
-                "operationId": "updat_patch_stock",
+                "summary": "Update Patch Product",
+                "operationId": "updat_patch_product",
                 "parameters": [
                     {
                         "type": "string",
@@ -3970,8 +4125,8 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdatPatchStockRequest",
-                        "name": "stock",
+                        "description": "UpdatPatchProductRequest",
+                        "name": "product",
                         "in": "body",
                         "required": true,
                         "schema": {
@@ -4037,9 +4192,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/store": {
+        "/product/{id}/availability": {
             "get": {
-                "description": "Get List Store",
+                "description": "Quantity of the product across all stores",
                 "consumes": [
                     "application/json"
                 ],
@@ -4047,28 +4202,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Store"
+                    "Stock"
                 ],
-                "summary": "Get List Store",
-                "operationId": "get_list_store",
+                "summary": "Get Product Availability",
+                "operationId": "get_product_availability",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -4083,7 +4227,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.ProductAvailability"
                                         }
                                     }
                                 }
@@ -4127,9 +4271,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/register": {
             "post": {
-                "description": "Create Store",
+                "description": "Create Register",
                 "consumes": [
                     "application/json"
                 ],
@@ -4137,23 +4283,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Store"
+                    "Register"
                 ],
-                "summary": "Create Store",
-                "operationId": "create_store",
+                "summary": "Create Register",
+                "operationId": "register",
                 "parameters": [
                     {
-                        "description": "CreateStoreRequest",
-                        "name": "store",
+                        "description": "CreateRegisterRequest",
+                        "name": "register",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateStore"
+                            "$ref": "#/definitions/models.Register"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "201": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -4189,6 +4335,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server Error",
                         "schema": {
@@ -4210,9 +4374,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/store/{id}": {
+        "/report/overdue": {
             "get": {
-                "description": "Get By ID Store",
+                "description": "Open orders past their required_date and the ones at risk of being late per store",
                 "consumes": [
                     "application/json"
                 ],
@@ -4220,17 +4384,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Store"
+                    "Report"
                 ],
-                "summary": "Get By ID Store",
-                "operationId": "get_by_id_store",
+                "summary": "Overdue Report",
+                "operationId": "overdue_report",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4245,7 +4408,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.OverdueReportResponse"
                                         }
                                     }
                                 }
@@ -4289,9 +4452,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update Put Store",
+            }
+        },
+        "/report/sales": {
+            "get": {
+                "description": "Gross sales, refunds and net sales per store, refunds are counted by the date of the return",
                 "consumes": [
                     "application/json"
                 ],
@@ -4299,26 +4464,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Store"
+                    "Report"
                 ],
-                "summary": "Update Put Store",
-                "operationId": "updat_patch_store",
+                "summary": "Sales Report",
+                "operationId": "sales_report",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "from date, 2006-01-02",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
-                        "description": "UpdateStore",
-                        "name": "store",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateStore"
-                        }
+                        "type": "string",
+                        "description": "to date, 2006-01-02",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4333,7 +4500,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.SalesReportResponse"
                                         }
                                     }
                                 }
@@ -4377,9 +4544,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Store",
+            }
+        },
+        "/staff": {
+            "get": {
+                "description": "Get List Staff",
                 "consumes": [
                     "application/json"
                 ],
@@ -4387,17 +4556,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Store"
+                    "Staff"
                 ],
-                "summary": "Delete Store",
-                "operationId": "get_by_id_store",
+                "summary": "Get List Staff",
+                "operationId": "get_list_staff",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4457,8 +4643,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Update Patch Store",
+            "post": {
+                "description": "Create Staff",
                 "consumes": [
                     "application/json"
                 ],
@@ -4466,25 +4652,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Store"
+                    "Staff"
                 ],
-                "summary": "Update Patch Store",
-                "operationId": "updat_patch_store",
+                "summary": "Create Staff",
+                "operationId": "create_staff",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "UpdatPatchStoreRequest",
-                        "name": "store",
+                        "description": "CreateStaffRequest",
+                        "name": "staff",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PatchRequest"
+                            "$ref": "#/definitions/models.CreateStaff"
                         }
                     }
                 ],
@@ -4546,9 +4725,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/staff/{id}": {
             "get": {
-                "description": "Get List User",
+                "description": "Get By ID Staff",
                 "consumes": [
                     "application/json"
                 ],
@@ -4556,28 +4735,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Staff"
                 ],
-                "summary": "Get List User",
-                "operationId": "get_list_user",
+                "summary": "Get By ID Staff",
+                "operationId": "get_by_id_staff",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -4637,8 +4805,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create User",
+            "put": {
+                "description": "Update Put Staff",
                 "consumes": [
                     "application/json"
                 ],
@@ -4646,18 +4814,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Staff"
                 ],
-                "summary": "Create User",
-                "operationId": "create_user",
+                "summary": "Update Put Staff",
+                "operationId": "updat_patch_staff",
                 "parameters": [
                     {
-                        "description": "CreateUserRequest",
-                        "name": "user",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateStaff",
+                        "name": "staff",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateUser"
+                            "$ref": "#/definitions/models.UpdateStaff"
                         }
                     }
                 ],
@@ -4717,11 +4892,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/{id}": {
-            "get": {
-                "description": "Get By ID User",
+            },
+            "delete": {
+                "description": "Delete Staff",
                 "consumes": [
                     "application/json"
                 ],
@@ -4729,10 +4902,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Staff"
                 ],
-                "summary": "Get By ID User",
-                "operationId": "get_by_id_user",
+                "summary": "Delete Staff",
+                "operationId": "get_by_id_staff",
                 "parameters": [
                     {
                         "type": "string",
@@ -4799,8 +4972,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update Put User",
+            "patch": {
+                "description": "Update Patch Staff",
                 "consumes": [
                     "application/json"
                 ],
@@ -4808,10 +4981,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Staff"
                 ],
-                "summary": "Update Put User",
-                "operationId": "updat_patch_user",
+                "summary": "Update Patch Staff",
+                "operationId": "updat_patch_staff",
                 "parameters": [
                     {
                         "type": "string",
@@ -4821,12 +4994,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdateUser",
-                        "name": "user",
+                        "description": "UpdatPatchStaffRequest",
+                        "name": "staff",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateUser"
+                            "$ref": "#/definitions/models.PatchRequest"
                         }
                     }
                 ],
@@ -4886,9 +5059,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete User",
+            }
+        },
+        "/staff/{id}/managers": {
+            "get": {
+                "description": "Get the manager chain of the staff member, from the direct manager up to the top",
                 "consumes": [
                     "application/json"
                 ],
@@ -4896,10 +5071,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Staff"
                 ],
-                "summary": "Delete User",
-                "operationId": "get_by_id_user",
+                "summary": "Get Staff Managers",
+                "operationId": "get_staff_managers",
                 "parameters": [
                     {
                         "type": "string",
@@ -4921,7 +5096,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.GetStaffHierarchyResponse"
                                         }
                                     }
                                 }
@@ -4966,42 +5141,3913 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.Response": {
-            "type": "object",
+        },
+        "/staff/{id}/performance": {
+            "get": {
+                "description": "Orders handled, revenue, average fulfilment time, late shipments and rejection rate of the staff member by order_date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Staff Performance",
+                "operationId": "staff_performance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "from date, 2006-01-02",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "to date, 2006-01-02",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.StaffPerformance"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/{id}/reports": {
+            "get": {
+                "description": "Get the direct and indirect reports of the staff member",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Get Staff Reports",
+                "operationId": "get_staff_reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only direct reports",
+                        "name": "direct",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetStaffHierarchyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/{id}/store": {
+            "put": {
+                "description": "Move the staff member to another store and optionally to another manager, manager_id 0 makes the staff member a top level manager",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Reassign Staff",
+                "operationId": "reassign_staff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ReassignStaffRequest",
+                        "name": "staff",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReassignStaff"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Staff"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/stock": {
+            "get": {
+                "description": "Get List Stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Get List Stock",
+                "operationId": "get_list_stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create Stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Create Stock",
+                "operationId": "create_stock",
+                "parameters": [
+                    {
+                        "description": "CreateStockRequest",
+                        "name": "stock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStock"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the first response of a retried request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/stock/transfer": {
+            "post": {
+                "description": "Move quantity of a product from one store to another",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Transfer Stock",
+                "operationId": "transfer_stock",
+                "parameters": [
+                    {
+                        "description": "TransferStockRequest",
+                        "name": "stock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferStock"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the first response of a retried request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/stock/{store_id}/{product_id}": {
+            "get": {
+                "description": "Get By ID Stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Get By ID Stock",
+                "operationId": "get_by_id_stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update Put Stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Update Put Stock",
+                "operationId": "updat_put_stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateStock",
+                        "name": "stock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStock"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete Stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Delete Stock",
+                "operationId": "delete_stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update Patch Stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Update Patch Stock",
+                "operationId": "updat_patch_stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdatPatchStockRequest",
+                        "name": "stock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchStock"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/stock_movement": {
+            "get": {
+                "description": "Get List Stock Movement",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Get List Stock Movement",
+                "operationId": "get_list_stock_movement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sale, return, adjustment, transfer or receiving",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetListStockMovementResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/stock_movement/reconcile": {
+            "get": {
+                "description": "Recompute stock from the movement ledger and list the store products where it differs from current stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Reconcile Stock",
+                "operationId": "reconcile_stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "store_id",
+                        "name": "store_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.StockReconciliationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/store": {
+            "get": {
+                "description": "Get List Store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Store"
+                ],
+                "summary": "Get List Store",
+                "operationId": "get_list_store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create Store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Store"
+                ],
+                "summary": "Create Store",
+                "operationId": "create_store",
+                "parameters": [
+                    {
+                        "description": "CreateStoreRequest",
+                        "name": "store",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStore"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}": {
+            "get": {
+                "description": "Get By ID Store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Store"
+                ],
+                "summary": "Get By ID Store",
+                "operationId": "get_by_id_store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update Put Store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Store"
+                ],
+                "summary": "Update Put Store",
+                "operationId": "updat_patch_store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateStore",
+                        "name": "store",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStore"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete Store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Store"
+                ],
+                "summary": "Delete Store",
+                "operationId": "get_by_id_store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update Patch Store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Store"
+                ],
+                "summary": "Update Patch Store",
+                "operationId": "updat_patch_store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdatPatchStoreRequest",
+                        "name": "store",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/performance": {
+            "get": {
+                "description": "Performance of every staff member on the orders of the store and the store totals by order_date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Store Performance",
+                "operationId": "store_performance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "from date, 2006-01-02",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "to date, 2006-01-02",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.StaffPerformanceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/staff": {
+            "get": {
+                "description": "Get the staff members of the store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Get Store Staff",
+                "operationId": "get_store_staff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetListStaffResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/stock": {
+            "get": {
+                "description": "Full inventory of the store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Get Store Stock",
+                "operationId": "get_store_stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetListStockResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "description": "Get List User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get List User",
+                "operationId": "get_list_user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Create User",
+                "operationId": "create_user",
+                "parameters": [
+                    {
+                        "description": "CreateUserRequest",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "description": "Get By ID User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get By ID User",
+                "operationId": "get_by_id_user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update Put User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update Put User",
+                "operationId": "updat_patch_user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateUser",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete User",
+                "operationId": "get_by_id_user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get List Webhook. Admin role only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get List Webhook",
+                "operationId": "get_list_webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetListWebhookSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Subscribe a URL to events: order.created, order.status_changed and stock.changed. Every delivery is signed with the secret in the X-Webhook-Signature header, \"t=\u003cunix\u003e,v1=\u003chex HMAC-SHA256 of '\u003cunix\u003e.\u003cbody\u003e'\u003e\". The URL must reach a public address. Admin role only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create Webhook",
+                "operationId": "create_webhook",
+                "parameters": [
+                    {
+                        "description": "CreateWebhookRequest",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWebhookSubscription"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookSubscription"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get By ID Webhook. Admin role only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get By ID Webhook",
+                "operationId": "get_by_id_webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookSubscription"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Webhook, an empty secret keeps the current one. Admin role only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Update Webhook",
+                "operationId": "update_webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateWebhookRequest",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateWebhookSubscription"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookSubscription"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Webhook with its delivery log. Admin role only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete Webhook",
+                "operationId": "delete_webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delivery log of a webhook, the latest deliveries first. Admin role only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get Webhook Deliveries",
+                "operationId": "get_webhook_deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, delivered or failed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetListWebhookDeliveryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "description": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "jwtkey.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "Ed25519",
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "jwtkey.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jwtkey.JWK"
+                    }
+                }
+            }
+        },
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Brand": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "integer"
+                },
+                "brand_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateAPIKey": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/models.APIKey"
+                },
+                "key": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateBrand": {
+            "type": "object",
+            "properties": {
+                "brand_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateCategory": {
+            "type": "object",
+            "properties": {
+                "category_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateCustomer": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "zip_code": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateOrder": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "order_date": {},
+                "order_status": {
+                    "type": "integer"
+                },
+                "required_date": {},
+                "shipped_date": {},
+                "staff_id": {
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateOrderReturn": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "description": "Refund_amount defaults to the price paid for the returned quantity",
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateOrder_item": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "list_price": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateProduct": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "list_price": {
+                    "type": "number"
+                },
+                "model_year": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateStaff": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateStock": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateStore": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "store_name": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "zip_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateUser": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateWebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Customer": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "zip_code": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CustomerSummary": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "favourite_store_id": {
+                    "type": "integer"
+                },
+                "favourite_store_name": {
+                    "type": "string"
+                },
+                "last_order_date": {},
+                "lifetime_spend": {
+                    "type": "number"
+                },
+                "orders_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GetListAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.APIKey"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GetListOrderResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Order"
+                    }
+                }
+            }
+        },
+        "models.GetListOrderReturnResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "order_returns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderReturn"
+                    }
+                }
+            }
+        },
+        "models.GetListStaffResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "staffs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Staff"
+                    }
+                }
+            }
+        },
+        "models.GetListStockMovementResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "stock_movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockMovement"
+                    }
+                }
+            }
+        },
+        "models.GetListStockResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "stocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Stock"
+                    }
+                }
+            }
+        },
+        "models.GetListWebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDelivery"
+                    }
+                }
+            }
+        },
+        "models.GetListWebhookSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookSubscription"
+                    }
+                }
+            }
+        },
+        "models.GetStaffHierarchyResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "staffs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StaffHierarchy"
+                    }
+                }
+            }
+        },
+        "models.HealthResponse": {
+            "type": "object",
             "properties": {
-                "data": {},
-                "description": {
+                "cache": {
+                    "type": "string"
+                },
+                "postgres": {
                     "type": "string"
                 },
                 "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IntegrityIssue": {
+            "type": "object",
+            "properties": {
+                "check": {
+                    "type": "string"
+                },
+                "fix": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "staff_id": {
+                    "type": "integer"
+                },
+                "store_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.CreateBrand": {
+        "models.IntegrityReport": {
             "type": "object",
             "properties": {
-                "brand_name": {
+                "checks": {
+                    "description": "Checks is the number of issues of every check",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "consistent": {
+                    "type": "boolean"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "fix_sql": {
+                    "description": "Fix_sql runs the fixes of all the issues in one transaction",
+                    "type": "string"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IntegrityIssue"
+                    }
+                }
+            }
+        },
+        "models.Login": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Order": {
+            "type": "object",
+            "properties": {
+                "customer_data": {
+                    "$ref": "#/definitions/models.Customer"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "net_total": {
+                    "type": "number"
+                },
+                "order_date": {},
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                },
+                "order_status": {
+                    "type": "integer"
+                },
+                "refunded": {
+                    "type": "number"
+                },
+                "required_date": {},
+                "returns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderReturn"
+                    }
+                },
+                "shipped_date": {},
+                "staff_data": {
+                    "$ref": "#/definitions/models.Staff"
+                },
+                "staff_id": {
+                    "type": "integer"
+                },
+                "store_data": {
+                    "$ref": "#/definitions/models.Store"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.OrderItem": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "list_price": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OrderItemPrimaryKey": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OrderReturn": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "type": "number"
+                },
+                "return_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OverdueReportResponse": {
+            "type": "object",
+            "properties": {
+                "at_risk_count": {
+                    "type": "integer"
+                },
+                "at_risk_days": {
+                    "type": "integer"
+                },
+                "overdue_count": {
+                    "type": "integer"
+                },
+                "overdue_total": {
+                    "type": "number"
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StoreOverdue"
+                    }
+                }
+            }
+        },
+        "models.PatchRequest": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PatchStock": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "properties": {
+                "brand_data": {
+                    "$ref": "#/definitions/models.Brand"
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "category_data": {
+                    "$ref": "#/definitions/models.Category"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "list_price": {
+                    "type": "number"
+                },
+                "model_year": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductAvailability": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StoreAvailability"
+                    }
+                }
+            }
+        },
+        "models.ReassignStaff": {
+            "type": "object",
+            "properties": {
+                "manager_id": {
+                    "description": "Manager_id is kept when it is not given, 0 makes the staff member a top level manager",
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Register": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SalesReportResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "gross_sales": {
+                    "type": "number"
+                },
+                "net_sales": {
+                    "type": "number"
+                },
+                "refunds": {
+                    "type": "number"
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StoreSales"
+                    }
+                },
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateCategory": {
+        "models.Staff": {
             "type": "object",
             "properties": {
-                "category_name": {
+                "active": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "type": "integer"
+                },
+                "phone": {
                     "type": "string"
+                },
+                "staff_id": {
+                    "type": "integer"
+                },
+                "store_data": {
+                    "$ref": "#/definitions/models.Store"
+                },
+                "store_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateCustomer": {
+        "models.StaffHierarchy": {
             "type": "object",
             "properties": {
-                "city": {
-                    "type": "string"
+                "active": {
+                    "type": "integer"
                 },
                 "email": {
                     "type": "string"
@@ -5012,32 +9058,52 @@ const docTemplate = `{
                 "last_name": {
                     "type": "string"
                 },
-                "phone": {
-                    "type": "string"
+                "level": {
+                    "description": "Level is the distance from the requested staff member, 1 for the direct manager or report",
+                    "type": "integer"
                 },
-                "state": {
-                    "type": "string"
+                "manager_id": {
+                    "type": "integer"
                 },
-                "street": {
-                    "type": "string"
+                "staff_id": {
+                    "type": "integer"
                 },
-                "zip_code": {
-                    "type": "number"
+                "store_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateOrder": {
+        "models.StaffPerformance": {
             "type": "object",
             "properties": {
-                "customer_id": {
+                "avg_fulfilment_days": {
+                    "type": "number"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "late_shipments": {
                     "type": "integer"
                 },
-                "order_date": {},
-                "order_status": {
+                "orders_count": {
+                    "type": "integer"
+                },
+                "rejected_count": {
+                    "type": "integer"
+                },
+                "rejection_rate": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "shipped_count": {
+                    "description": "Shipped_count is the number of orders with a shipped_date, the fulfilment time is averaged over them",
                     "type": "integer"
                 },
-                "required_date": {},
-                "shipped_date": {},
                 "staff_id": {
                     "type": "integer"
                 },
@@ -5046,68 +9112,89 @@ const docTemplate = `{
                 }
             }
         },
-        "models.CreateOrder_item": {
+        "models.StaffPerformanceResponse": {
             "type": "object",
             "properties": {
-                "discount": {
+                "avg_fulfilment_days": {
                     "type": "number"
                 },
-                "item_id": {
+                "from": {
+                    "type": "string"
+                },
+                "late_shipments": {
                     "type": "integer"
                 },
-                "list_price": {
+                "orders_count": {
+                    "type": "integer"
+                },
+                "rejected_count": {
+                    "type": "integer"
+                },
+                "rejection_rate": {
                     "type": "number"
                 },
-                "order_id": {
+                "revenue": {
+                    "type": "number"
+                },
+                "shipped_count": {
                     "type": "integer"
                 },
-                "product_id": {
+                "staffs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StaffPerformance"
+                    }
+                },
+                "store_id": {
                     "type": "integer"
                 },
-                "quantity": {
-                    "type": "number"
+                "to": {
+                    "type": "string"
                 }
             }
         },
-        "models.CreateProduct": {
+        "models.Stock": {
             "type": "object",
             "properties": {
-                "brand_id": {
+                "product_data": {
+                    "$ref": "#/definitions/models.Product"
+                },
+                "product_id": {
                     "type": "integer"
                 },
-                "category_id": {
+                "quantity": {
                     "type": "integer"
                 },
-                "list_price": {
-                    "type": "number"
+                "store_data": {
+                    "$ref": "#/definitions/models.Store"
                 },
-                "model_year": {
+                "store_id": {
                     "type": "integer"
-                },
-                "product_name": {
-                    "type": "string"
                 }
             }
         },
-        "models.CreateStaff": {
+        "models.StockMovement": {
             "type": "object",
             "properties": {
-                "active": {
+                "actor": {
                     "type": "string"
                 },
-                "email": {
+                "created_at": {
                     "type": "string"
                 },
-                "first_name": {
-                    "type": "string"
+                "movement_id": {
+                    "type": "integer"
                 },
-                "last_name": {
-                    "type": "string"
+                "product_id": {
+                    "type": "integer"
                 },
-                "manager_id": {
+                "quantity": {
                     "type": "integer"
                 },
-                "phone": {
+                "reason": {
+                    "type": "string"
+                },
+                "reference_id": {
                     "type": "string"
                 },
                 "store_id": {
@@ -5115,19 +9202,44 @@ const docTemplate = `{
                 }
             }
         },
-        "models.CreateStock": {
+        "models.StockReconciliation": {
             "type": "object",
             "properties": {
+                "difference": {
+                    "type": "integer"
+                },
+                "ledger_quantity": {
+                    "type": "integer"
+                },
                 "product_id": {
                     "type": "integer"
                 },
-                "quantity": {},
+                "quantity": {
+                    "type": "integer"
+                },
                 "store_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.CreateStore": {
+        "models.StockReconciliationResponse": {
+            "type": "object",
+            "properties": {
+                "consistent": {
+                    "type": "boolean"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "mismatches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockReconciliation"
+                    }
+                }
+            }
+        },
+        "models.Store": {
             "type": "object",
             "properties": {
                 "city": {
@@ -5142,6 +9254,9 @@ const docTemplate = `{
                 "state": {
                     "type": "string"
                 },
+                "store_id": {
+                    "type": "integer"
+                },
                 "store_name": {
                     "type": "string"
                 },
@@ -5153,65 +9268,104 @@ const docTemplate = `{
                 }
             }
         },
-        "models.CreateUser": {
+        "models.StoreAvailability": {
             "type": "object",
             "properties": {
-                "login": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "store_id": {
+                    "type": "integer"
                 },
-                "password": {
+                "store_name": {
                     "type": "string"
                 }
             }
         },
-        "models.Login": {
+        "models.StoreOverdue": {
             "type": "object",
             "properties": {
-                "login": {
-                    "type": "string"
+                "at_risk_count": {
+                    "type": "integer"
                 },
-                "password": {
+                "oldest_due": {},
+                "overdue_count": {
+                    "type": "integer"
+                },
+                "overdue_total": {
+                    "type": "number"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "store_name": {
                     "type": "string"
                 }
             }
         },
-        "models.OrderItemPrimaryKey": {
+        "models.StoreSales": {
             "type": "object",
             "properties": {
-                "item_id": {
+                "gross_sales": {
+                    "type": "number"
+                },
+                "net_sales": {
+                    "type": "number"
+                },
+                "orders_count": {
                     "type": "integer"
                 },
-                "order_id": {
+                "refunds": {
+                    "type": "number"
+                },
+                "store_id": {
                     "type": "integer"
+                },
+                "store_name": {
+                    "type": "string"
                 }
             }
         },
-        "models.PatchRequest": {
+        "models.TransferStock": {
             "type": "object",
             "properties": {
-                "fields": {
-                    "type": "object",
-                    "additionalProperties": true
+                "from_store_id": {
+                    "type": "integer"
                 },
-                "id": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reference_id": {
+                    "type": "string"
+                },
+                "to_store_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.Register": {
+        "models.UpdateAPIKey": {
             "type": "object",
             "properties": {
-                "login": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "store_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -5350,7 +9504,9 @@ const docTemplate = `{
                 "product_id": {
                     "type": "integer"
                 },
-                "quantity": {},
+                "quantity": {
+                    "type": "integer"
+                },
                 "store_id": {
                     "type": "integer"
                 }
@@ -5401,6 +9557,88 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.UpdateWebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "integer"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {},
+                "response_code": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subscription_id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -5408,6 +9646,11 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
+        },
+        "IntegrationKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     }
 }`
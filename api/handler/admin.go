@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"app/api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security ApiKeyAuth
+// Integrity Check godoc
+// @ID integrity_check
+// @Router /admin/integrity [GET]
+// @Summary Integrity Check
+// @Description Negative stock, sold products without a stock row in the order's store, orders sold by staff of another store, staff manager cycles, orphaned ledger rows and stocks off their ledger. Admin role only
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param fix_sql query string false "true adds the SQL fixing the issues that have a mechanical fix"
+// @Success 200 {object} Response{data=models.IntegrityReport} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) IntegrityCheck(c *gin.Context) {
+
+	var fixSQL bool
+
+	if len(c.Query("fix_sql")) > 0 {
+		var err error
+		fixSQL, err = strconv.ParseBool(c.Query("fix_sql"))
+		if err != nil {
+			h.handlerResponse(c, "integrity check", http.StatusBadRequest, "invalid fix_sql")
+			return
+		}
+	}
+
+	resp, err := h.storages.Admin().CheckIntegrity(c.Request.Context(), &models.IntegrityCheckRequest{Fix_sql: fixSQL})
+	if err != nil {
+		h.handlerResponse(c, "storage.admin.checkIntegrity", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "integrity check", http.StatusOK, resp)
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/logger"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security ApiKeyAuth
+// Create API Key godoc
+// @ID create_api_key
+// @Router /api_key [POST]
+// @Summary Create API Key
+// @Description Create a key for an integration, sent in the X-API-Key header. The key is only returned here. Scopes are <resource>:read or <resource>:write of catalog, customers, stores, orders, stock and reports; a store_id restricts the key to that store and expires_at is RFC 3339. Admin role only
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Param api_key body models.CreateAPIKey true "CreateAPIKeyRequest"
+// @Success 201 {object} Response{data=models.CreateAPIKeyResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+
+	var createAPIKey models.CreateAPIKey
+
+	err := c.ShouldBindJSON(&createAPIKey)
+	if err != nil {
+		h.handlerResponse(c, "create api key", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = checkAPIKey(createAPIKey.Name, createAPIKey.Scopes, createAPIKey.Store_id, createAPIKey.Expires_at)
+	if err != nil {
+		h.handlerResponse(c, "create api key", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if createAPIKey.Store_id > 0 {
+		_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: createAPIKey.Store_id})
+		if err != nil {
+			h.handlerResponse(c, "storage.store.getByID", http.StatusBadRequest, "store not found")
+			return
+		}
+	}
+
+	key, prefix, hash, err := helper.GenerateAPIKey()
+	if err != nil {
+		h.handlerResponse(c, "create api key", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	createAPIKey.Prefix = prefix
+	createAPIKey.Key_hash = hash
+	createAPIKey.Created_by = h.getActor(c)
+
+	id, err := h.storages.APIKey().Create(c.Request.Context(), &createAPIKey)
+	if err != nil {
+		h.handlerResponse(c, "storage.api_key.create", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ID, _ := strconv.Atoi(id)
+	resp, err := h.storages.APIKey().GetByID(c.Request.Context(), &models.APIKeyPrimaryKey{Api_key_id: ID})
+	if err != nil {
+		h.handlerResponse(c, "storage.api_key.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// the key is written without handlerResponse, which logs the response: only the hash may be kept
+	h.logger.Info("create api key", logger.RequestIDField(c.Request.Context()), logger.Any("info", resp))
+
+	c.JSON(http.StatusCreated, Response{
+		Status: http.StatusCreated,
+		Data:   models.CreateAPIKeyResponse{Key: key, Api_key: resp},
+	})
+}
+
+// @Security ApiKeyAuth
+// Get By ID API Key godoc
+// @ID get_by_id_api_key
+// @Router /api_key/{id} [GET]
+// @Summary Get By ID API Key
+// @Description Get By ID API Key. Admin role only
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.APIKey} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetByIdAPIKey(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "get api key by id", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	resp, err := h.storages.APIKey().GetByID(c.Request.Context(), &models.APIKeyPrimaryKey{Api_key_id: id})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.api_key.getByID", http.StatusNotFound, "api key not found")
+			return
+		}
+		h.handlerResponse(c, "storage.api_key.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get api key by id", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// Get List API Key godoc
+// @ID get_list_api_key
+// @Router /api_key [GET]
+// @Summary Get List API Key
+// @Description Get List API Key with the revoked ones. Admin role only
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Success 200 {object} Response{data=models.GetListAPIKeyResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetListAPIKey(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list api key", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list api key", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	resp, err := h.storages.APIKey().GetList(c.Request.Context(), &models.GetListAPIKeyRequest{
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.api_key.getlist", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get list api key response", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// Update API Key godoc
+// @ID update_api_key
+// @Router /api_key/{id} [PUT]
+// @Summary Update API Key
+// @Description Update the name, scopes, store and expiry of a key not revoked, the key itself does not change. Admin role only
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param api_key body models.UpdateAPIKey true "UpdateAPIKeyRequest"
+// @Success 202 {object} Response{data=models.APIKey} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) UpdateAPIKey(c *gin.Context) {
+
+	var updateAPIKey models.UpdateAPIKey
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "update api key", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	err = c.ShouldBindJSON(&updateAPIKey)
+	if err != nil {
+		h.handlerResponse(c, "update api key", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = checkAPIKey(updateAPIKey.Name, updateAPIKey.Scopes, updateAPIKey.Store_id, updateAPIKey.Expires_at)
+	if err != nil {
+		h.handlerResponse(c, "update api key", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if updateAPIKey.Store_id > 0 {
+		_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: updateAPIKey.Store_id})
+		if err != nil {
+			h.handlerResponse(c, "storage.store.getByID", http.StatusBadRequest, "store not found")
+			return
+		}
+	}
+
+	updateAPIKey.Api_key_id = id
+
+	rowsAffected, err := h.storages.APIKey().Update(c.Request.Context(), &updateAPIKey)
+	if err != nil {
+		h.handlerResponse(c, "storage.api_key.update", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if rowsAffected <= 0 {
+		h.handlerResponse(c, "storage.api_key.update", http.StatusNotFound, "api key not found or revoked")
+		return
+	}
+
+	resp, err := h.storages.APIKey().GetByID(c.Request.Context(), &models.APIKeyPrimaryKey{Api_key_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.api_key.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "update api key", http.StatusAccepted, resp)
+}
+
+// @Security ApiKeyAuth
+// Revoke API Key godoc
+// @ID revoke_api_key
+// @Router /api_key/{id} [DELETE]
+// @Summary Revoke API Key
+// @Description Revoke API Key, its next request is refused. The key stays in the list with its revoked_at. Admin role only
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 202 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "revoke api key", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	rowsAffected, err := h.storages.APIKey().Revoke(c.Request.Context(), &models.APIKeyPrimaryKey{Api_key_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.api_key.revoke", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if rowsAffected <= 0 {
+		h.handlerResponse(c, "storage.api_key.revoke", http.StatusNotFound, "api key not found or already revoked")
+		return
+	}
+
+	h.handlerResponse(c, "revoke api key", http.StatusAccepted, id)
+}
+
+func checkAPIKey(name string, scopes []string, storeId int, expiresAt string) error {
+
+	if len(name) == 0 {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(scopes) == 0 {
+		return fmt.Errorf("scopes is required")
+	}
+
+	for _, scope := range scopes {
+		if !models.APIKeyScopes[scope] {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	if storeId < 0 {
+		return fmt.Errorf("invalid store_id")
+	}
+
+	if len(expiresAt) > 0 {
+		expires, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return fmt.Errorf("expires_at must be an RFC 3339 time")
+		}
+
+		if !expires.After(time.Now()) {
+			return fmt.Errorf("expires_at must be in the future")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"app/api/models"
+	"app/pkg/helper"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// apiKeyResources are the scope resources of the first path segment of the routes. The
+// routes of the other segments, like the users, the API keys, the webhooks and the admin
+// routes, do not accept API keys.
+var apiKeyResources = map[string]string{
+	"category":       "catalog",
+	"brand":          "catalog",
+	"product":        "catalog",
+	"customer":       "customers",
+	"store":          "stores",
+	"staff":          "stores",
+	"order":          "orders",
+	"order_item":     "orders",
+	"order_return":   "orders",
+	"stock":          "stock",
+	"stock_movement": "stock",
+	"report":         "reports",
+}
+
+// storeResources are the resources a key restricted to a store only reaches for its store,
+// the catalog and the customers are shared by the stores.
+var storeResources = map[string]bool{
+	"stores":  true,
+	"orders":  true,
+	"stock":   true,
+	"reports": true,
+}
+
+// storeFilteredLists are the routes filtering by the store_id query, a key restricted to a
+// store gets the filter of its store when the request has none.
+var storeFilteredLists = map[string]bool{
+	"/order":                    true,
+	"/staff":                    true,
+	"/stock":                    true,
+	"/stock_movement":           true,
+	"/stock_movement/reconcile": true,
+	"/report/sales":             true,
+	"/report/overdue":           true,
+}
+
+// APIKeyMiddleware authenticates the requests sent with an X-API-Key header. The key must
+// have the scope of the route, and a key restricted to a store only reaches the routes naming
+// its store. The key is put on the context like a token, so AuthMiddleware lets it through
+// and the stock ledger records it as the actor. The requests without the header go on.
+func (h *Handler) APIKeyMiddleware() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		key := c.GetHeader(apiKeyHeader)
+		if key == "" || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		apiKey, err := h.storages.APIKey().Authenticate(c.Request.Context(), helper.HashAPIKey(key))
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				h.handlerResponse(c, "api key", http.StatusUnauthorized, "invalid API key")
+			} else {
+				h.handlerResponse(c, "storage.api_key.authenticate", http.StatusInternalServerError, err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		resource, scope, ok := routeScope(c)
+		if !ok {
+			h.handlerResponse(c, "api key", http.StatusForbidden, "the route does not accept API keys")
+			c.Abort()
+			return
+		}
+
+		if !hasScope(apiKey.Scopes, resource, scope) {
+			h.handlerResponse(c, "api key", http.StatusForbidden, fmt.Sprintf("the API key has no %s scope", scope))
+			c.Abort()
+			return
+		}
+
+		if apiKey.Store_id > 0 && storeResources[resource] {
+			err = h.checkKeyStore(c, apiKey.Store_id)
+			if err != nil {
+				h.handlerResponse(c, "api key", http.StatusForbidden, err.Error())
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set("Auth", helper.TokenInfo{
+			UserID:   fmt.Sprintf("api_key:%d", apiKey.Api_key_id),
+			Name:     apiKey.Name,
+			APIKeyID: apiKey.Api_key_id,
+			StoreID:  apiKey.Store_id,
+			Scopes:   apiKey.Scopes,
+		})
+
+		c.Next()
+	}
+}
+
+// routeScope returns the resource of the route and the scope the request needs on it.
+func routeScope(c *gin.Context) (resource, scope string, ok bool) {
+
+	segment := strings.SplitN(strings.TrimPrefix(c.FullPath(), "/"), "/", 2)[0]
+
+	resource, ok = apiKeyResources[segment]
+	if !ok {
+		return "", "", false
+	}
+
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		return resource, resource + ":read", true
+	}
+
+	return resource, resource + ":write", true
+}
+
+// hasScope reports whether scopes grant scope, the write scope of a resource grants its read scope.
+func hasScope(scopes []string, resource, scope string) bool {
+
+	for _, s := range scopes {
+		if s == scope || s == resource+":write" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkKeyStore checks that the request names the store of a key restricted to storeId and
+// no other store. A list filtering by store gets the filter of the key when it has none, the
+// other requests not naming a store are refused.
+func (h *Handler) checkKeyStore(c *gin.Context, storeId int) error {
+
+	stores, err := h.requestStores(c)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range stores {
+		if id != storeId {
+			return fmt.Errorf("the API key is restricted to store %d", storeId)
+		}
+	}
+
+	if len(stores) > 0 {
+		return nil
+	}
+
+	if c.Request.Method == http.MethodGet && storeFilteredLists[c.FullPath()] {
+		// the query is read from the URL, not c.Query, so the handler reads the new one
+		query := c.Request.URL.Query()
+		query.Set("store_id", strconv.Itoa(storeId))
+		c.Request.URL.RawQuery = query.Encode()
+		return nil
+	}
+
+	return fmt.Errorf("the API key is restricted to store %d, the request must name it", storeId)
+}
+
+// requestStores returns the stores a request names: the store_id path parameter, the id of
+// the /store routes, the store of the staff member, order or return of the other :id routes,
+// the store ids of the JSON body and, on the lists filtering by store, the store_id query.
+func (h *Handler) requestStores(c *gin.Context) ([]int, error) {
+
+	var (
+		stores []int
+		values []interface{}
+		orders []interface{}
+		path   = c.FullPath()
+	)
+
+	if id := c.Param("store_id"); id != "" {
+		values = append(values, id)
+	}
+
+	// the row touched by the route is in its store, whatever the query says
+	switch {
+	case strings.HasPrefix(path, "/store/:id"):
+		values = append(values, c.Param("id"))
+
+	case strings.HasPrefix(path, "/staff/:id"):
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid staff id %s", c.Param("id"))
+		}
+
+		staff, err := h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: id})
+		if err != nil {
+			return nil, fmt.Errorf("staff %d not found", id)
+		}
+
+		stores = append(stores, staff.Store_id)
+
+	case strings.HasPrefix(path, "/order/:id"), strings.HasPrefix(path, "/order_item/:id"):
+		orders = append(orders, c.Param("id"))
+
+	case strings.HasPrefix(path, "/order_return/:id"):
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid return id %s", c.Param("id"))
+		}
+
+		orderReturn, err := h.storages.Order().GetReturnByID(c.Request.Context(), &models.OrderReturnPrimaryKey{Return_id: id})
+		if err != nil {
+			return nil, fmt.Errorf("return %d not found", id)
+		}
+
+		orders = append(orders, orderReturn.Order_id)
+	}
+
+	// the other handlers ignore the query, naming the store there proves nothing
+	if query := c.Request.URL.Query(); storeFilteredLists[path] && query.Has("store_id") {
+		values = append(values, query.Get("store_id"))
+	}
+
+	body, err := jsonBody(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// the handlers bind the keys case-insensitively, and the PATCH routes take the changed
+	// columns under fields, so every spelling of every key is checked
+	fields := []map[string]interface{}{body}
+	for key, value := range body {
+		if nested, ok := value.(map[string]interface{}); ok && strings.EqualFold(key, "fields") {
+			fields = append(fields, nested)
+		}
+	}
+
+	for _, object := range fields {
+		for key, value := range object {
+			switch strings.ToLower(key) {
+			case "store_id", "from_store_id", "to_store_id":
+				values = append(values, value)
+			case "order_id":
+				orders = append(orders, value)
+			}
+		}
+	}
+
+	for _, value := range values {
+
+		id, err := cast.ToIntE(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid store id %v", value)
+		}
+
+		stores = append(stores, id)
+	}
+
+	for _, value := range orders {
+
+		id, err := cast.ToIntE(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid order id %v", value)
+		}
+
+		order, err := h.storages.Order().GetByID(c.Request.Context(), &models.OrderPrimaryKey{Order_id: id})
+		if err != nil {
+			return nil, fmt.Errorf("order %d not found", id)
+		}
+
+		stores = append(stores, order.Store_id)
+	}
+
+	return stores, nil
+}
+
+// jsonBody decodes the body of the request and puts it back for the handler. The handlers
+// bind JSON whatever the Content-Type is, so every body that is not a JSON object is refused.
+func jsonBody(c *gin.Context) (map[string]interface{}, error) {
+
+	if c.Request.Body == nil {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var body map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err = decoder.Decode(&body)
+	if err != nil {
+		return nil, errors.New("the body is not a JSON object")
+	}
+
+	return body, nil
+}
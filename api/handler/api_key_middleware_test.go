@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"app/api/models"
+	"app/config"
+	"app/pkg/helper"
+	"app/pkg/logger"
+	"app/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errNoRows = errors.New("no rows in result set")
+
+// fakeAPIKeyRepo authenticates the keys by hash, the revoked and expired ones are not found
+// like the query of the postgres repo leaves them out.
+type fakeAPIKeyRepo struct {
+	storage.APIKeyRepoI
+	keys map[string]*models.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Authenticate(ctx context.Context, keyHash string) (*models.APIKey, error) {
+
+	key, ok := r.keys[keyHash]
+	if !ok || key.Revoked_at != "" {
+		return nil, errNoRows
+	}
+
+	if key.Expires_at != "" {
+		expires, err := time.Parse(time.RFC3339, key.Expires_at)
+		if err != nil || !expires.After(time.Now()) {
+			return nil, errNoRows
+		}
+	}
+
+	return key, nil
+}
+
+type fakeStaffRepo struct {
+	storage.StaffRepoI
+	staff map[int]*models.Staff
+}
+
+func (r *fakeStaffRepo) GetByID(ctx context.Context, req *models.StaffPrimaryKey) (*models.Staff, error) {
+
+	staff, ok := r.staff[req.Staff_id]
+	if !ok {
+		return nil, errNoRows
+	}
+
+	return staff, nil
+}
+
+type fakeOrderRepo struct {
+	storage.OrderRepoI
+	orders  map[int]*models.Order
+	returns map[int]*models.OrderReturn
+}
+
+func (r *fakeOrderRepo) GetByID(ctx context.Context, req *models.OrderPrimaryKey) (*models.Order, error) {
+
+	order, ok := r.orders[req.Order_id]
+	if !ok {
+		return nil, errNoRows
+	}
+
+	return order, nil
+}
+
+func (r *fakeOrderRepo) GetReturnByID(ctx context.Context, req *models.OrderReturnPrimaryKey) (*models.OrderReturn, error) {
+
+	orderReturn, ok := r.returns[req.Return_id]
+	if !ok {
+		return nil, errNoRows
+	}
+
+	return orderReturn, nil
+}
+
+type fakeStorage struct {
+	storage.StorageI
+	apiKey      *fakeAPIKeyRepo
+	staff       *fakeStaffRepo
+	order       *fakeOrderRepo
+	idempotency *fakeIdempotencyRepo
+	store       *fakeStoreRepo
+	product     *fakeProductRepo
+	stock       *fakeStockRepo
+}
+
+func (s *fakeStorage) APIKey() storage.APIKeyRepoI {
+	return s.apiKey
+}
+
+func (s *fakeStorage) Staff() storage.StaffRepoI {
+	return s.staff
+}
+
+func (s *fakeStorage) Order() storage.OrderRepoI {
+	return s.order
+}
+
+// newAPIKeyTestRouter serves the routes with the API key middleware, every route answers the
+// store_id query it got.
+func newAPIKeyTestRouter(keys map[string]*models.APIKey) *gin.Engine {
+
+	gin.SetMode(gin.TestMode)
+
+	hashed := map[string]*models.APIKey{}
+	for key, apiKey := range keys {
+		hashed[helper.HashAPIKey(key)] = apiKey
+	}
+
+	h := NewHandler(&config.Config{}, &fakeStorage{
+		apiKey: &fakeAPIKeyRepo{keys: hashed},
+		staff: &fakeStaffRepo{staff: map[int]*models.Staff{
+			7: {Staff_id: 7, Store_id: 2},
+			8: {Staff_id: 8, Store_id: 1},
+		}},
+		order: &fakeOrderRepo{
+			orders: map[int]*models.Order{
+				3: {Order_id: 3, Store_id: 2},
+				4: {Order_id: 4, Store_id: 1},
+			},
+			returns: map[int]*models.OrderReturn{
+				5: {Return_id: 5, Order_id: 3},
+				6: {Return_id: 6, Order_id: 4},
+			},
+		},
+	}, nil, nil, logger.NewLogger("test", logger.LevelError))
+
+	r := gin.New()
+	r.Use(h.APIKeyMiddleware())
+
+	ok := func(c *gin.Context) {
+		c.String(http.StatusOK, c.Query("store_id"))
+	}
+
+	r.GET("/product", ok)
+	r.POST("/product", ok)
+	r.GET("/order", ok)
+	r.GET("/staff/:id", ok)
+	r.DELETE("/staff/:id", ok)
+	r.PUT("/staff/:id/store", ok)
+	r.GET("/order_return/:id", ok)
+	r.POST("/order_return", ok)
+	r.GET("/stock/:store_id/:product_id", ok)
+	r.GET("/user", ok)
+
+	return r
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+
+	r := newAPIKeyTestRouter(map[string]*models.APIKey{
+		"sk_catalog": {Api_key_id: 1, Scopes: []string{models.ScopeCatalogRead}},
+		"sk_store1":  {Api_key_id: 2, Scopes: []string{models.ScopeOrdersWrite, models.ScopeStoresWrite, models.ScopeStockRead}, Store_id: 1},
+		"sk_revoked": {Api_key_id: 3, Scopes: []string{models.ScopeCatalogRead}, Revoked_at: time.Now().Format(time.RFC3339)},
+		"sk_expired": {Api_key_id: 4, Scopes: []string{models.ScopeCatalogRead}, Expires_at: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+	})
+
+	tests := []struct {
+		name   string
+		key    string
+		method string
+		path   string
+		body   string
+		code   int
+		query  string
+	}{
+		{name: "read scope", key: "sk_catalog", method: http.MethodGet, path: "/product", code: http.StatusOK},
+		{name: "write without the write scope", key: "sk_catalog", method: http.MethodPost, path: "/product", body: `{}`, code: http.StatusForbidden},
+		{name: "resource without scope", key: "sk_catalog", method: http.MethodGet, path: "/order", code: http.StatusForbidden},
+		{name: "route not accepting keys", key: "sk_catalog", method: http.MethodGet, path: "/user", code: http.StatusForbidden},
+		{name: "unknown key", key: "sk_unknown", method: http.MethodGet, path: "/product", code: http.StatusUnauthorized},
+		{name: "revoked key", key: "sk_revoked", method: http.MethodGet, path: "/product", code: http.StatusUnauthorized},
+		{name: "expired key", key: "sk_expired", method: http.MethodGet, path: "/product", code: http.StatusUnauthorized},
+		{name: "list gets the store of the key", key: "sk_store1", method: http.MethodGet, path: "/order", code: http.StatusOK, query: "1"},
+		{name: "list of another store", key: "sk_store1", method: http.MethodGet, path: "/order?store_id=2", code: http.StatusForbidden},
+		{name: "staff of the store", key: "sk_store1", method: http.MethodGet, path: "/staff/8", code: http.StatusOK},
+		{name: "staff of another store named by the query", key: "sk_store1", method: http.MethodDelete, path: "/staff/7?store_id=1", code: http.StatusForbidden},
+		{name: "moving staff out of another store", key: "sk_store1", method: http.MethodPut, path: "/staff/7/store", body: `{"store_id": 1}`, code: http.StatusForbidden},
+		{name: "moving staff to another store", key: "sk_store1", method: http.MethodPut, path: "/staff/8/store", body: `{"store_id": 2}`, code: http.StatusForbidden},
+		{name: "return of the store", key: "sk_store1", method: http.MethodGet, path: "/order_return/6", code: http.StatusOK},
+		{name: "return of another store named by the query", key: "sk_store1", method: http.MethodGet, path: "/order_return/5?store_id=1", code: http.StatusForbidden},
+		{name: "return of an order of another store", key: "sk_store1", method: http.MethodPost, path: "/order_return", body: `{"Order_ID": 3}`, code: http.StatusForbidden},
+		{name: "stock of another store", key: "sk_store1", method: http.MethodGet, path: "/stock/2/1", code: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(apiKeyHeader, tt.key)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Fatalf("%s %s = %d, want %d: %s", tt.method, tt.path, w.Code, tt.code, w.Body.String())
+			}
+
+			if tt.query != "" && w.Body.String() != tt.query {
+				t.Errorf("store_id query = %q, want %q", w.Body.String(), tt.query)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"app/api/models"
 	"app/config"
 	"app/pkg/helper"
-	"context"
 	"errors"
 	"net/http"
 
@@ -23,6 +22,7 @@ import (
 // @Param register body models.Register true "CreateRegisterRequest"
 // @Success 201 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 429 {object} Response{data=string} "Too Many Requests"
 // @Failure 500 {object} Response{data=string} "Server Error"
 func (h *Handler) Register(c *gin.Context) {
 
@@ -42,7 +42,7 @@ func (h *Handler) Register(c *gin.Context) {
 
 	createUser.Password = string(hashedPassword)
 
-	id, err := h.storages.User().Create(context.Background(), &createUser)
+	id, err := h.storages.User().Create(c.Request.Context(), &createUser)
 	if err != nil {
 		if err.Error() == `ERROR: duplicate key value violates unique constraint "users_login_key" (SQLSTATE 23505)` {
 			h.handlerResponse(c, "storage.user.create", http.StatusBadRequest, "user already exists please login!")
@@ -51,7 +51,7 @@ func (h *Handler) Register(c *gin.Context) {
 		h.handlerResponse(c, "storage.user.create", http.StatusInternalServerError, err.Error())
 		return
 	}
-	user, err := h.storages.User().GetByID(context.Background(), &models.UserPrimaryKey{Id: id})
+	user, err := h.storages.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.user.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -71,6 +71,7 @@ func (h *Handler) Register(c *gin.Context) {
 // @Param logim body models.Login true "LoginRequest"
 // @Success 201 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 429 {object} Response{data=string} "Too Many Requests"
 // @Failure 500 {object} Response{data=string} "Server Error"
 func (h *Handler) Login(c *gin.Context) {
 
@@ -82,9 +83,14 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.User().GetByID(context.Background(), &models.UserPrimaryKey{Login: login.Login})
+	if h.loginLocked(c, login.Login) {
+		return
+	}
+
+	resp, err := h.storages.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Login: login.Login})
 	if err != nil {
 		if err.Error() == "no rows in result set" {
+			h.loginFailed(c, login.Login)
 			h.handlerResponse(c, "storage.user.getByID", http.StatusBadRequest, "user not found please register first")
 			return
 		}
@@ -95,20 +101,23 @@ func (h *Handler) Login(c *gin.Context) {
 
 	err = bcrypt.CompareHashAndPassword([]byte(resp.Password), []byte(login.Password))
 	if err != nil {
+		h.loginFailed(c, login.Login)
 		h.handlerResponse(c, "storage.user.getByID", http.StatusBadRequest, "credentials are wrong")
 		return
 	}
 
+	h.loginSucceeded(c, login.Login)
+
 	data := map[string]interface{}{
 		"Id":         resp.Id,
 		"name":       resp.Name,
 		"login":      resp.Login,
-		"password":   resp.Password,
+		"role":       resp.Role,
 		"created_at": resp.CreatedAt,
 		"updated_at": resp.UpdatedAt,
 	}
 
-	token, err := helper.GenerateJWT(data, config.TimeExpiredAt, h.cfg.SecretKey)
+	token, err := helper.GenerateJWT(data, config.TimeExpiredAt, h.keys)
 	if err != nil {
 		h.handlerResponse(c, "storage.user.getByID", http.StatusBadRequest, errors.New("token error"))
 		return
@@ -117,3 +126,18 @@ func (h *Handler) Login(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, models.LoginResponse{Token: token, UserData: resp})
 }
+
+// JWKS godoc
+// @ID jwks
+// @Router /.well-known/jwks.json [GET]
+// @Summary Token Keys
+// @Description The public keys verifying the access tokens by kid, the HS256 keys are not published
+// @Tags Login
+// @Produce json
+// @Success 200 {object} jwtkey.JWKS "Success Request"
+func (h *Handler) JWKS(c *gin.Context) {
+
+	// the keys change only on a deploy, the verifiers can cache them for a while
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -2,7 +2,7 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
+	"app/storage/cachesync"
 	"net/http"
 	"strconv"
 
@@ -32,13 +32,13 @@ func (h *Handler) CreateBrand(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storages.Brand().Create(context.Background(), &createBrand)
+	id, err := h.storages.Brand().Create(c.Request.Context(), &createBrand)
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 	ID, _ := strconv.Atoi(id)
-	resp, err := h.storages.Brand().GetByID(context.Background(), &models.BrandPrimaryKey{Brand_id: ID})
+	resp, err := h.storages.Brand().GetByID(c.Request.Context(), &models.BrandPrimaryKey{Brand_id: ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -64,7 +64,13 @@ func (h *Handler) CreateBrand(c *gin.Context) {
 func (h *Handler) GetByIdBrand(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	resp, err := h.storages.Brand().GetByID(context.Background(), &models.BrandPrimaryKey{Brand_id: id})
+
+	var resp *models.Brand
+
+	err := h.readThrough(c.Request.Context(), cachesync.Brand, cachesync.KeyByID(id), h.cfg.CacheTTL, &resp, func() (err error) {
+		resp, err = h.storages.Brand().GetByID(c.Request.Context(), &models.BrandPrimaryKey{Brand_id: id})
+		return err
+	})
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -103,10 +109,17 @@ func (h *Handler) GetListBrand(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Brand().GetList(context.Background(), &models.GetListBrandRequest{
+	req := &models.GetListBrandRequest{
 		Offset: offset,
 		Limit:  limit,
 		Search: c.Query("search"),
+	}
+
+	var resp *models.GetListBrandResponse
+
+	err = h.readThrough(c.Request.Context(), cachesync.Brand, cacheKeyList(req), h.cfg.CacheListTTL, &resp, func() (err error) {
+		resp, err = h.storages.Brand().GetList(c.Request.Context(), req)
+		return err
 	})
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.getlist", http.StatusInternalServerError, err.Error())
@@ -144,7 +157,7 @@ func (h *Handler) UpdateBrand(c *gin.Context) {
 
 	updateBrand.Brand_id = id
 
-	rowsAffected, err := h.storages.Brand().Update(context.Background(), &updateBrand)
+	rowsAffected, err := h.storages.Brand().Update(c.Request.Context(), &updateBrand)
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.update", http.StatusInternalServerError, err.Error())
 		return
@@ -155,7 +168,7 @@ func (h *Handler) UpdateBrand(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Brand().GetByID(context.Background(), &models.BrandPrimaryKey{Brand_id: id})
+	resp, err := h.storages.Brand().GetByID(c.Request.Context(), &models.BrandPrimaryKey{Brand_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -192,7 +205,7 @@ func (h *Handler) UpdatePatchBrand(c *gin.Context) {
 
 	object.ID = id
 
-	rowsAffected, err := h.storages.Brand().Patch(context.Background(), &object)
+	rowsAffected, err := h.storages.Brand().Patch(c.Request.Context(), &object)
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.patchupdate", http.StatusInternalServerError, err.Error())
 		return
@@ -203,7 +216,7 @@ func (h *Handler) UpdatePatchBrand(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Brand().GetByID(context.Background(), &models.BrandPrimaryKey{Brand_id: object.ID})
+	resp, err := h.storages.Brand().GetByID(c.Request.Context(), &models.BrandPrimaryKey{Brand_id: object.ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -229,7 +242,7 @@ func (h *Handler) DeleteBrand(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	_, err := h.storages.Brand().Delete(context.Background(), &models.BrandPrimaryKey{Brand_id: id})
+	_, err := h.storages.Brand().Delete(c.Request.Context(), &models.BrandPrimaryKey{Brand_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.brand.delete", http.StatusInternalServerError, err.Error())
 		return
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"app/storage/cachesync"
+)
+
+// cacheKeyList builds the key of a GetList response from the list request. The request
+// already holds the parsed offset and limit with their defaults, so equal queries share a key.
+func cacheKeyList(req interface{}) string {
+
+	body, _ := json.Marshal(req)
+
+	return "list:" + strings.ToLower(string(body))
+}
+
+// readThrough serves the response of the entity from the cache, see cachesync.ReadThrough.
+func (h *Handler) readThrough(ctx context.Context, entity, key string, ttl time.Duration, dest interface{}, load func() error) error {
+	return cachesync.ReadThrough(ctx, h.caches.Cache(), h.logger, entity, key, ttl, dest, load)
+}
@@ -2,7 +2,7 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
+	"app/storage/cachesync"
 	"net/http"
 	"strconv"
 
@@ -32,13 +32,13 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storages.Category().Create(context.Background(), &createCategory)
+	id, err := h.storages.Category().Create(c.Request.Context(), &createCategory)
 	if err != nil {
 		h.handlerResponse(c, "storage.category.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 	ID, _ := strconv.Atoi(id)
-	resp, err := h.storages.Category().GetByID(context.Background(), &models.CategoryPrimaryKey{Category_id: ID})
+	resp, err := h.storages.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Category_id: ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.category.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -63,7 +63,13 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 func (h *Handler) GetByIdCategory(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	resp, err := h.storages.Category().GetByID(context.Background(), &models.CategoryPrimaryKey{Category_id: id})
+
+	var resp *models.Category
+
+	err := h.readThrough(c.Request.Context(), cachesync.Category, cachesync.KeyByID(id), h.cfg.CacheTTL, &resp, func() (err error) {
+		resp, err = h.storages.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Category_id: id})
+		return err
+	})
 	if err != nil {
 		h.handlerResponse(c, "storage.category.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -101,10 +107,17 @@ func (h *Handler) GetListCategory(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Category().GetList(context.Background(), &models.GetListCategoryRequest{
+	req := &models.GetListCategoryRequest{
 		Offset: offset,
 		Limit:  limit,
 		Search: c.Query("search"),
+	}
+
+	var resp *models.GetListCategoryResponse
+
+	err = h.readThrough(c.Request.Context(), cachesync.Category, cacheKeyList(req), h.cfg.CacheListTTL, &resp, func() (err error) {
+		resp, err = h.storages.Category().GetList(c.Request.Context(), req)
+		return err
 	})
 	if err != nil {
 		h.handlerResponse(c, "storage.category.getlist", http.StatusInternalServerError, err.Error())
@@ -142,7 +155,7 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 
 	updateCategory.Category_id = id
 
-	rowsAffected, err := h.storages.Category().Update(context.Background(), &updateCategory)
+	rowsAffected, err := h.storages.Category().Update(c.Request.Context(), &updateCategory)
 	if err != nil {
 		h.handlerResponse(c, "storage.category.update", http.StatusInternalServerError, err.Error())
 		return
@@ -153,7 +166,7 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Category().GetByID(context.Background(), &models.CategoryPrimaryKey{Category_id: id})
+	resp, err := h.storages.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Category_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.category.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -190,7 +203,7 @@ func (h *Handler) UpdatePatchCategory(c *gin.Context) {
 
 	object.ID = id
 
-	rowsAffected, err := h.storages.Category().Patch(context.Background(), &object)
+	rowsAffected, err := h.storages.Category().Patch(c.Request.Context(), &object)
 	if err != nil {
 		h.handlerResponse(c, "storage.category.patchupdate", http.StatusInternalServerError, err.Error())
 		return
@@ -201,7 +214,7 @@ func (h *Handler) UpdatePatchCategory(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Category().GetByID(context.Background(), &models.CategoryPrimaryKey{Category_id: object.ID})
+	resp, err := h.storages.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Category_id: object.ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.category.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -227,7 +240,7 @@ func (h *Handler) DeleteCategory(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	_, err := h.storages.Category().Delete(context.Background(), &models.CategoryPrimaryKey{Category_id: id})
+	_, err := h.storages.Category().Delete(c.Request.Context(), &models.CategoryPrimaryKey{Category_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.category.delete", http.StatusInternalServerError, err.Error())
 		return
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
 	"net/http"
 	"strconv"
 
@@ -31,13 +30,13 @@ func (h *Handler) CreateCustomer(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storages.Customer().Create(context.Background(), &createCustomer)
+	id, err := h.storages.Customer().Create(c.Request.Context(), &createCustomer)
 	if err != nil {
 		h.handlerResponse(c, "storage.customer.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 	ID, _ := strconv.Atoi(id)
-	resp, err := h.storages.Customer().GetByID(context.Background(), &models.CustomerPrimaryKey{Customer_id: ID})
+	resp, err := h.storages.Customer().GetByID(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.customer.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -61,7 +60,7 @@ func (h *Handler) CreateCustomer(c *gin.Context) {
 func (h *Handler) GetByIdCustomer(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	resp, err := h.storages.Customer().GetByID(context.Background(), &models.CustomerPrimaryKey{Customer_id: id})
+	resp, err := h.storages.Customer().GetByID(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.customer.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -98,7 +97,7 @@ func (h *Handler) GetListCustomer(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Customer().GetList(context.Background(), &models.GetListCustomerRequest{
+	resp, err := h.storages.Customer().GetList(c.Request.Context(), &models.GetListCustomerRequest{
 		Offset: offset,
 		Limit:  limit,
 		Search: c.Query("search"),
@@ -138,7 +137,7 @@ func (h *Handler) UpdateCustomer(c *gin.Context) {
 
 	updateCustomer.Customer_id = id
 
-	rowsAffected, err := h.storages.Customer().Update(context.Background(), &updateCustomer)
+	rowsAffected, err := h.storages.Customer().Update(c.Request.Context(), &updateCustomer)
 	if err != nil {
 		h.handlerResponse(c, "storage.customer.update", http.StatusInternalServerError, err.Error())
 		return
@@ -149,7 +148,7 @@ func (h *Handler) UpdateCustomer(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Customer().GetByID(context.Background(), &models.CustomerPrimaryKey{Customer_id: id})
+	resp, err := h.storages.Customer().GetByID(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.customer.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -185,7 +184,7 @@ func (h *Handler) UpdatePatchCustomer(c *gin.Context) {
 
 	object.ID = id
 
-	rowsAffected, err := h.storages.Customer().Patch(context.Background(), &object)
+	rowsAffected, err := h.storages.Customer().Patch(c.Request.Context(), &object)
 	if err != nil {
 		h.handlerResponse(c, "storage.customer.patchupdate", http.StatusInternalServerError, err.Error())
 		return
@@ -196,7 +195,7 @@ func (h *Handler) UpdatePatchCustomer(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Customer().GetByID(context.Background(), &models.CustomerPrimaryKey{Customer_id: object.ID})
+	resp, err := h.storages.Customer().GetByID(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: object.ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.customer.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -221,7 +220,7 @@ func (h *Handler) DeleteCustomer(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	_, err := h.storages.Customer().Delete(context.Background(), &models.CustomerPrimaryKey{Customer_id: id})
+	_, err := h.storages.Customer().Delete(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.customer.delete", http.StatusInternalServerError, err.Error())
 		return
@@ -229,3 +228,97 @@ func (h *Handler) DeleteCustomer(c *gin.Context) {
 
 	h.handlerResponse(c, "delete customer", http.StatusAccepted, id)
 }
+
+// Get Customer Orders godoc
+// @ID get_customer_orders
+// @Router /customer/{id}/orders [GET]
+// @Summary Get Customer Orders
+// @Description Order history of the customer, newest first
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Param status query string false "comma separated statuses: pending, processing, rejected, completed or 1-4"
+// @Success 200 {object} Response{data=models.GetListOrderResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetCustomerOrders(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "get customer orders", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get customer orders", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get customer orders", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	statuses, err := h.getStatusesQuery(c.Query("status"))
+	if err != nil {
+		h.handlerResponse(c, "get customer orders", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	_, err = h.storages.Customer().GetByID(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.customer.getByID", http.StatusNotFound, err.Error())
+		return
+	}
+
+	resp, err := h.storages.Order().GetList(c.Request.Context(), &models.GetListOrderRequest{
+		Offset:      offset,
+		Limit:       limit,
+		Customer_id: id,
+		Statuses:    statuses,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.order.getlist", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get customer orders response", http.StatusOK, resp)
+}
+
+// Get Customer Summary godoc
+// @ID get_customer_summary
+// @Router /customer/{id}/summary [GET]
+// @Summary Get Customer Summary
+// @Description Order count, lifetime spend, last order date and favourite store of the customer
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.CustomerSummary} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetCustomerSummary(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "get customer summary", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	resp, err := h.storages.Customer().GetSummary(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: id})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.customer.getSummary", http.StatusNotFound, "customer not found")
+			return
+		}
+		h.handlerResponse(c, "storage.customer.getSummary", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get customer summary", http.StatusOK, resp)
+}
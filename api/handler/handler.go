@@ -1,10 +1,18 @@
 package handler
 
 import (
+	"app/api/models"
 	"app/config"
+	"app/pkg/helper"
+	"app/pkg/jwtkey"
 	"app/pkg/logger"
 	"app/storage"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,6 +22,7 @@ type Handler struct {
 	logger   logger.LoggerI
 	storages storage.StorageI
 	caches   storage.CacheStorageI
+	keys     *jwtkey.KeySet
 }
 
 type Response struct {
@@ -22,32 +31,53 @@ type Response struct {
 	Data        interface{}
 }
 
-func NewHandler(cfg *config.Config, store storage.StorageI, cache storage.CacheStorageI, logger logger.LoggerI) *Handler {
+func NewHandler(cfg *config.Config, store storage.StorageI, cache storage.CacheStorageI, keys *jwtkey.KeySet, logger logger.LoggerI) *Handler {
 	return &Handler{
 		cfg:      cfg,
 		logger:   logger,
 		storages: store,
 		caches:   cache,
+		keys:     keys,
 	}
 }
 
 func (h *Handler) handlerResponse(c *gin.Context, path string, code int, message interface{}) {
 
+	if code >= 400 && h.timedOut(c, message) {
+		code = http.StatusGatewayTimeout
+		message = storage.ErrTimeout.Error()
+	}
+
 	response := Response{
 		Status: code,
 		Data:   message,
 	}
 
+	fields := []logger.Field{
+		logger.RequestIDField(c.Request.Context()),
+		logger.Any("info", logger.Redact(response)),
+	}
+
 	switch {
 	case code < 300:
-		h.logger.Info(path, logger.Any("info", response))
+		h.logger.Info(path, fields...)
 	case code >= 400:
-		h.logger.Error(path, logger.Any("info", response))
+		h.logger.Error(path, fields...)
 	}
 
 	c.JSON(code, response)
 }
 
+// timedOut reports whether the request failed on the storage timeout or its context deadline.
+func (h *Handler) timedOut(c *gin.Context, message interface{}) bool {
+
+	if message == storage.ErrTimeout.Error() {
+		return true
+	}
+
+	return c.Request != nil && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
 func (h *Handler) getOffsetQuery(offset string) (int, error) {
 
 	if len(offset) <= 0 {
@@ -65,3 +95,59 @@ func (h *Handler) getLimitQuery(limit string) (int, error) {
 
 	return strconv.Atoi(limit)
 }
+
+// getStatusesQuery parses comma separated order statuses given by number or by name, e.g. "1,processing".
+func (h *Handler) getStatusesQuery(value string) ([]int, error) {
+
+	var statuses []int
+
+	if len(value) <= 0 {
+		return statuses, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+
+		if status, ok := models.OrderStatuses[item]; ok {
+			statuses = append(statuses, status)
+			continue
+		}
+
+		status, err := strconv.Atoi(item)
+		if err != nil || status < models.OrderStatusPending || status > models.OrderStatusCompleted {
+			return nil, fmt.Errorf("invalid status %q", item)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func (h *Handler) getIntQuery(value string) (int, error) {
+
+	if len(value) <= 0 {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+// getActor returns the id of the authorized user. The routes without AuthMiddleware
+// read the bearer token when it is sent, else the actor is anonymous with the client IP.
+func (h *Handler) getActor(c *gin.Context) string {
+
+	if info, exists := c.Get("Auth"); exists {
+		if tokenInfo, ok := info.(helper.TokenInfo); ok && tokenInfo.UserID != "" {
+			return tokenInfo.UserID
+		}
+	}
+
+	if token := c.GetHeader("Authorization"); token != "" {
+		tokenInfo, err := helper.ParseClaims(token, h.keys)
+		if err == nil && tokenInfo.UserID != "" {
+			return tokenInfo.UserID
+		}
+	}
+
+	return "anonymous:" + c.ClientIP()
+}
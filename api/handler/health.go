@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"app/api/models"
+	"app/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const readinessTimeout = 2 * time.Second
+
+// Health godoc
+// @ID health
+// @Router /health [GET]
+// @Summary Health
+// @Description Service health, the status is degraded while the cache is down and reads are served from postgresql
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse "Success Request"
+func (h *Handler) Health(c *gin.Context) {
+
+	resp := models.HealthResponse{
+		Status: "ok",
+		Cache:  h.caches.Status(),
+	}
+
+	if resp.Cache != "up" {
+		resp.Status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Liveness godoc
+// @ID health_live
+// @Router /health/live [GET]
+// @Summary Liveness
+// @Description The process is up and serving requests
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse "Success Request"
+func (h *Handler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, models.HealthResponse{Status: "ok", Cache: h.caches.Status()})
+}
+
+// Readiness godoc
+// @ID health_ready
+// @Router /health/ready [GET]
+// @Summary Readiness
+// @Description Pings postgresql and redis, the service is not ready without postgresql and degraded without redis
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse "Success Request"
+// @Failure 503 {object} models.HealthResponse "Service Unavailable"
+func (h *Handler) Readiness(c *gin.Context) {
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	var (
+		code = http.StatusOK
+		resp = models.HealthResponse{
+			Status:   "ok",
+			Postgres: "up",
+			Cache:    "up",
+		}
+	)
+
+	err := h.caches.Ping()
+	if err != nil {
+		h.logger.Warn("health.ready.redis", logger.Error(err))
+		resp.Status = "degraded"
+		resp.Cache = "down"
+	}
+
+	err = h.storages.Ping(ctx)
+	if err != nil {
+		h.logger.Error("health.ready.postgres", logger.Error(err))
+		code = http.StatusServiceUnavailable
+		resp.Status = "unavailable"
+		resp.Postgres = "down"
+	}
+
+	c.JSON(code, resp)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"app/api/models"
+	"app/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLength   = 255
+
+	// idempotencySaveTimeout bounds saving the response, the request context may be done by then
+	idempotencySaveTimeout = 5 * time.Second
+	// idempotencyLease is how long a key stays running without a response when REQUEST_TIMEOUT
+	// does not bound the requests
+	idempotencyLease = time.Minute
+)
+
+// IdempotencyMiddleware makes the retries of a request sent with an Idempotency-Key header
+// replay its first response instead of running again. Reusing the key with another method,
+// path or body is 422, and a retry sent while the first request still runs is 409. The key
+// of a request that ended without a response, like on a crash, is free again after its lease.
+// Responses of 5xx are not kept so the request can be retried with the same key.
+func (h *Handler) IdempotencyMiddleware() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			h.handlerResponse(c, "idempotency", http.StatusBadRequest, "Idempotency-Key is longer than 255 characters")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			h.handlerResponse(c, "idempotency", http.StatusBadRequest, err.Error())
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		request := &models.IdempotencyKey{
+			Key:          key,
+			Request_hash: requestHash(c.Request.Method, c.Request.URL.Path, body),
+		}
+
+		existing, reserved, err := h.storages.Idempotency().Reserve(c.Request.Context(), request, h.cfg.IdempotencyKeyTTL, h.idempotencyLease())
+		if err != nil {
+			h.handlerResponse(c, "storage.idempotency.reserve", http.StatusInternalServerError, err.Error())
+			c.Abort()
+			return
+		}
+
+		if !reserved {
+			switch {
+			case existing.Request_hash != request.Request_hash:
+				h.handlerResponse(c, "idempotency", http.StatusUnprocessableEntity, "Idempotency-Key was already used with another request")
+			case existing.Status == 0:
+				h.handlerResponse(c, "idempotency", http.StatusConflict, "a request with this Idempotency-Key is in progress")
+			default:
+				c.Header(idempotencyReplayedHeader, "true")
+				c.Data(existing.Status, existing.Content_type, existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), logger.RequestID(c.Request.Context())), idempotencySaveTimeout)
+		defer cancel()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			err = h.storages.Idempotency().Release(ctx, key)
+			if err != nil {
+				h.logger.Error("storage.idempotency.release", logger.RequestIDField(ctx), logger.Error(err))
+			}
+			return
+		}
+
+		request.Status = recorder.Status()
+		request.Content_type = recorder.Header().Get("Content-Type")
+		request.Body = recorder.body.Bytes()
+
+		err = h.storages.Idempotency().Complete(ctx, request)
+		if err != nil {
+			h.logger.Error("storage.idempotency.complete", logger.RequestIDField(ctx), logger.Error(err))
+		}
+	}
+}
+
+// idempotencyLease outlasts the requests, they are stopped by REQUEST_TIMEOUT and then save
+// their response.
+func (h *Handler) idempotencyLease() time.Duration {
+
+	if h.cfg.RequestTimeout <= 0 {
+		return idempotencyLease
+	}
+
+	return h.cfg.RequestTimeout + idempotencySaveTimeout
+}
+
+// requestHash identifies the request a key was first used with.
+func requestHash(method, path string, body []byte) string {
+
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written through it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"app/api/models"
+	"app/config"
+	"app/pkg/logger"
+	"app/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeIdempotencyRepo keeps the keys in memory, they never expire.
+type fakeIdempotencyRepo struct {
+	storage.IdempotencyRepoI
+	keys map[string]*models.IdempotencyKey
+}
+
+func (r *fakeIdempotencyRepo) Reserve(ctx context.Context, key *models.IdempotencyKey, ttl, lease time.Duration) (*models.IdempotencyKey, bool, error) {
+
+	if existing, ok := r.keys[key.Key]; ok {
+		return existing, false, nil
+	}
+
+	r.keys[key.Key] = &models.IdempotencyKey{Key: key.Key, Request_hash: key.Request_hash}
+
+	return nil, true, nil
+}
+
+func (r *fakeIdempotencyRepo) Complete(ctx context.Context, key *models.IdempotencyKey) error {
+	r.keys[key.Key] = key
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) Release(ctx context.Context, key string) error {
+	delete(r.keys, key)
+	return nil
+}
+
+func (s *fakeStorage) Idempotency() storage.IdempotencyRepoI {
+	return s.idempotency
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	repo := &fakeIdempotencyRepo{keys: map[string]*models.IdempotencyKey{
+		// reserved by a request still running
+		"running": {Key: "running", Request_hash: requestHash(http.MethodPost, "/order_item", []byte(`{"quantity":1}`))},
+	}}
+	h := NewHandler(&config.Config{IdempotencyKeyTTL: time.Hour}, &fakeStorage{idempotency: repo}, nil, nil, logger.NewLogger("test", logger.LevelError))
+
+	runs := 0
+	failing := false
+
+	r := gin.New()
+	r.POST("/order_item", h.IdempotencyMiddleware(), func(c *gin.Context) {
+		runs++
+		if failing {
+			c.JSON(http.StatusInternalServerError, Response{Status: http.StatusInternalServerError})
+			return
+		}
+		c.JSON(http.StatusCreated, Response{Status: http.StatusCreated, Data: runs})
+	})
+
+	tests := []struct {
+		Name     string
+		Key      string
+		Body     string
+		Failing  bool
+		Code     int
+		Runs     int
+		Replayed bool
+	}{
+		{Name: "first request", Key: "a", Body: `{"quantity":1}`, Code: http.StatusCreated, Runs: 1},
+		{Name: "retry is replayed", Key: "a", Body: `{"quantity":1}`, Code: http.StatusCreated, Runs: 1, Replayed: true},
+		{Name: "key reused with another body", Key: "a", Body: `{"quantity":2}`, Code: http.StatusUnprocessableEntity, Runs: 1},
+		{Name: "retry while running", Key: "running", Body: `{"quantity":1}`, Code: http.StatusConflict, Runs: 1},
+		{Name: "no key", Body: `{"quantity":1}`, Code: http.StatusCreated, Runs: 2},
+		{Name: "server error", Key: "b", Body: `{"quantity":1}`, Failing: true, Code: http.StatusInternalServerError, Runs: 3},
+		{Name: "retry after a server error runs again", Key: "b", Body: `{"quantity":1}`, Code: http.StatusCreated, Runs: 4},
+	}
+
+	var first string
+
+	for _, test := range tests {
+
+		failing = test.Failing
+
+		req := httptest.NewRequest(http.MethodPost, "/order_item", strings.NewReader(test.Body))
+		if test.Key != "" {
+			req.Header.Set(idempotencyKeyHeader, test.Key)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != test.Code || runs != test.Runs {
+			t.Errorf("%s: code %d after %d runs, want %d after %d", test.Name, w.Code, runs, test.Code, test.Runs)
+		}
+
+		if replayed := w.Header().Get(idempotencyReplayedHeader) == "true"; replayed != test.Replayed {
+			t.Errorf("%s: replayed %v, want %v", test.Name, replayed, test.Replayed)
+		}
+
+		if first == "" {
+			first = w.Body.String()
+		} else if test.Replayed && w.Body.String() != first {
+			t.Errorf("%s: replayed %s, want %s", test.Name, w.Body.String(), first)
+		}
+	}
+}
@@ -1,20 +1,27 @@
 package handler
 
 import (
+	"app/api/models"
 	"app/pkg/helper"
-	"fmt"
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+// AuthMiddleware lets through the requests with a valid token and the ones already
+// authenticated by the API key of APIKeyMiddleware.
 func (h *Handler) AuthMiddleware() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 
+		if _, ok := c.Get("Auth"); ok {
+			c.Next()
+			return
+		}
+
 		token := c.GetHeader("Authorization")
-		fmt.Println(":::::::::::::::::::::::::::::::::::::::---", token)
-		info, err := helper.ParseClaims(token, h.cfg.SecretKey)
+		info, err := helper.ParseClaims(token, h.keys)
 		if err != nil {
 			c.AbortWithError(http.StatusForbidden, err)
 			return
@@ -25,3 +32,39 @@ func (h *Handler) AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AdminMiddleware lets through the users with the admin role, it runs after AuthMiddleware.
+func (h *Handler) AdminMiddleware() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		info, ok := c.Get("Auth")
+		if !ok || info.(helper.TokenInfo).Role != models.UserRoleAdmin {
+			h.handlerResponse(c, "admin", http.StatusForbidden, "admin role is required")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// TimeoutMiddleware puts the request timeout from the config on the request context,
+// the repos stop their queries once it passes and the response is 504.
+func (h *Handler) TimeoutMiddleware() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		if h.cfg.RequestTimeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), h.cfg.RequestTimeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
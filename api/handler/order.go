@@ -2,7 +2,9 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
+	"app/pkg/metrics"
+	"app/storage"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -19,6 +21,7 @@ import (
 // @Accept json
 // @Produce json
 // @Param order body models.CreateOrder true "CreateOrderRequest"
+// @Param Idempotency-Key header string false "replays the first response of a retried request"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
@@ -32,30 +35,32 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	_, err = h.storages.Customer().GetByID(context.Background(), &models.CustomerPrimaryKey{Customer_id: createOrder.Customer_id})
+	_, err = h.storages.Customer().GetByID(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: createOrder.Customer_id})
 	if err != nil {
 		h.handlerResponse(c, "handler.order.create.GetCustomerByIDForCreateOrder", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: createOrder.Store_id})
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: createOrder.Store_id})
 	if err != nil {
 		h.handlerResponse(c, "handler.order.create.GetStoreByIDForCreateOrder", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Staff().GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: createOrder.Staff_id})
+	_, err = h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: createOrder.Staff_id})
 	if err != nil {
 		h.handlerResponse(c, "handler.order.create.GetStaffByIDForCreateOrder", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	resp, err := h.storages.Order().Create(context.Background(), &createOrder)
+	resp, err := h.storages.Order().Create(c.Request.Context(), &createOrder)
 	if err != nil {
 		h.handlerResponse(c, "storage.order.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	metrics.OrdersCreated.Inc()
+
 	h.handlerResponse(c, "create order", http.StatusCreated, resp)
 }
 
@@ -75,7 +80,7 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 func (h *Handler) GetByIdOrder(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	resp, err := h.storages.Order().GetByID(context.Background(), &models.OrderPrimaryKey{Order_id: id})
+	resp, err := h.storages.Order().GetByID(c.Request.Context(), &models.OrderPrimaryKey{Order_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.order.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -96,6 +101,8 @@ func (h *Handler) GetByIdOrder(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param store_id query string false "store_id"
+// @Param delivery query string false "overdue or at_risk, open orders past their required_date or due soon"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
@@ -113,10 +120,25 @@ func (h *Handler) GetListOrder(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Order().GetList(context.Background(), &models.GetListOrderRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+	storeId, err := h.getIntQuery(c.Query("store_id"))
+	if err != nil {
+		h.handlerResponse(c, "get list order", http.StatusBadRequest, "invalid store_id")
+		return
+	}
+
+	delivery := c.Query("delivery")
+	if len(delivery) > 0 && delivery != models.OrderDeliveryOverdue && delivery != models.OrderDeliveryAtRisk {
+		h.handlerResponse(c, "get list order", http.StatusBadRequest, "invalid delivery")
+		return
+	}
+
+	resp, err := h.storages.Order().GetList(c.Request.Context(), &models.GetListOrderRequest{
+		Offset:       offset,
+		Limit:        limit,
+		Search:       c.Query("search"),
+		Store_id:     storeId,
+		Delivery:     delivery,
+		At_risk_days: h.cfg.OrderAtRiskDays,
 	})
 	if err != nil {
 		h.handlerResponse(c, "storage.order.getlist", http.StatusInternalServerError, err.Error())
@@ -152,19 +174,19 @@ func (h *Handler) UpdateOrder(c *gin.Context) {
 		return
 	}
 
-	_, err = h.storages.Customer().GetByID(context.Background(), &models.CustomerPrimaryKey{Customer_id: updateOrder.Customer_id})
+	_, err = h.storages.Customer().GetByID(c.Request.Context(), &models.CustomerPrimaryKey{Customer_id: updateOrder.Customer_id})
 	if err != nil {
 		h.handlerResponse(c, "handler.order.update.GetCustomerByIDForUpdateOrder", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: updateOrder.Store_id})
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: updateOrder.Store_id})
 	if err != nil {
 		h.handlerResponse(c, "handler.order.update.GetStoreByIDForUpdateOrder", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Staff().GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: updateOrder.Staff_id})
+	_, err = h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: updateOrder.Staff_id})
 	if err != nil {
 		h.handlerResponse(c, "handler.order.update.GetStaffByIDForUpdateOrder", http.StatusInternalServerError, err.Error())
 		return
@@ -172,7 +194,7 @@ func (h *Handler) UpdateOrder(c *gin.Context) {
 
 	updateOrder.Order_id = id
 
-	rowsAffected, err := h.storages.Order().Update(context.Background(), &updateOrder)
+	rowsAffected, err := h.storages.Order().Update(c.Request.Context(), &updateOrder)
 	if err != nil {
 		h.handlerResponse(c, "storage.order.update", http.StatusInternalServerError, err.Error())
 		return
@@ -183,7 +205,7 @@ func (h *Handler) UpdateOrder(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Order().GetByID(context.Background(), &models.OrderPrimaryKey{Order_id: id})
+	resp, err := h.storages.Order().GetByID(c.Request.Context(), &models.OrderPrimaryKey{Order_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.order.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -220,7 +242,7 @@ func (h *Handler) UpdatePatchOrder(c *gin.Context) {
 
 	object.ID = id
 
-	rowsAffected, err := h.storages.Order().Patch(context.Background(), &object)
+	rowsAffected, err := h.storages.Order().Patch(c.Request.Context(), &object)
 	if err != nil {
 		h.handlerResponse(c, "storage.order.patchupdate", http.StatusInternalServerError, err.Error())
 		return
@@ -231,7 +253,7 @@ func (h *Handler) UpdatePatchOrder(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Order().GetByID(context.Background(), &models.OrderPrimaryKey{Order_id: object.ID})
+	resp, err := h.storages.Order().GetByID(c.Request.Context(), &models.OrderPrimaryKey{Order_id: object.ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.order.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -245,19 +267,24 @@ func (h *Handler) UpdatePatchOrder(c *gin.Context) {
 // @ID get_by_id_order
 // @Router /order/{id} [DELETE]
 // @Summary Delete Order
-// @Description Delete Order
+// @Description Delete Order, an order with returns is kept
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Param id path string true "id"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 409 {object} Response{data=string} "Conflict"
 // @Failure 500 {object} Response{data=string} "Server Error"
 func (h *Handler) DeleteOrder(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	_, err := h.storages.Order().Delete(context.Background(), &models.OrderPrimaryKey{Order_id: id})
+	_, err := h.storages.Order().Delete(c.Request.Context(), &models.OrderPrimaryKey{Order_id: id})
+	if errors.Is(err, storage.ErrHasReturns) {
+		h.handlerResponse(c, "storage.order.delete", http.StatusConflict, "the order has returns")
+		return
+	}
 	if err != nil {
 		h.handlerResponse(c, "storage.order.delete", http.StatusInternalServerError, err.Error())
 		return
@@ -279,6 +306,7 @@ func (h *Handler) DeleteOrder(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param order_item body models.CreateOrder_item true "CreateOrder_itemRequest"
+// @Param Idempotency-Key header string false "replays the first response of a retried request"
 // @Success 201 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
@@ -292,33 +320,41 @@ func (h *Handler) CreateOrderItem(c *gin.Context) {
 		return
 	}
 	// get order for getting store_id
-	order, err := h.storages.Order().GetByID(context.Background(), &models.OrderPrimaryKey{Order_id: createOrderItem.Order_id})
+	order, err := h.storages.Order().GetByID(c.Request.Context(), &models.OrderPrimaryKey{Order_id: createOrderItem.Order_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.order.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	// check count of products in store
-	stockData, err := h.storages.Stock().GetByIdProductStock(context.Background(), order.Store_id, createOrderItem.Product_id)
+	stockData, err := h.storages.Stock().GetByID(c.Request.Context(), &models.StockPrimaryKey{Store_id: order.Store_id, Product_id: createOrderItem.Product_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.stock.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
-	QuantInt, _ := stockData.Quantity.(int)
-	QuantF, _ := stockData.Quantity.(float64)
-	if QuantInt <= 0 || createOrderItem.Quantity > QuantF {
+	if stockData.Quantity <= 0 || createOrderItem.Quantity > float64(stockData.Quantity) {
 		h.handlerResponse(c, "create order_item", http.StatusBadRequest, "Товарь не найден")
 		return
 	}
 	// ----------CREATE ORDER ITEM------------------------------------------------------------------------------------------
 	// WHEN create order item in postgres will execute trigger for getting products from store
-	_, err = h.storages.Order().AddOrderItem(context.Background(), &models.OrderItem{})
+	_, err = h.storages.Order().AddOrderItem(c.Request.Context(), &models.OrderItem{
+		Order_id:   createOrderItem.Order_id,
+		Product_id: createOrderItem.Product_id,
+		Quantity:   int(createOrderItem.Quantity),
+		List_price: createOrderItem.List_price,
+		Discount:   createOrderItem.Discount,
+		Actor:      h.getActor(c),
+	})
 	if err != nil {
 		h.handlerResponse(c, "storage.order_item.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	resp, err := h.storages.Order().GetByID(context.Background(), &models.OrderPrimaryKey{Order_id: createOrderItem.Order_id})
+	metrics.OrderItemsSold.Add(float64(int(createOrderItem.Quantity)))
+	metrics.StockChanges.WithLabelValues(models.StockMovementSale).Inc()
+
+	resp, err := h.storages.Order().GetByID(c.Request.Context(), &models.OrderPrimaryKey{Order_id: createOrderItem.Order_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.order.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -332,7 +368,7 @@ func (h *Handler) CreateOrderItem(c *gin.Context) {
 // @ID delete_order_item
 // @Router /order_item/{id} [DELETE]
 // @Summary Delete Order Item
-// @Description Delete Order Item
+// @Description Delete Order Item entered by mistake, sold products are returned with POST /order_return. An item with returns is kept
 // @Tags Order
 // @Accept json
 // @Produce json
@@ -341,6 +377,7 @@ func (h *Handler) CreateOrderItem(c *gin.Context) {
 // @Param orderItem body models.OrderItemPrimaryKey true "DeleteOrderItemRequest"
 // @Success 204 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 409 {object} Response{data=string} "Conflict"
 // @Failure 500 {object} Response{data=string} "Server Error"
 func (h *Handler) DeleteOrderItem(c *gin.Context) {
 
@@ -359,11 +396,17 @@ func (h *Handler) DeleteOrderItem(c *gin.Context) {
 		return
 	}
 
-	_, err = h.storages.Order().RemoveOrderItem(context.Background(), &models.OrderItemPrimaryKey{Order_id: idInt, Item_id: idItemInt})
+	_, err = h.storages.Order().RemoveOrderItem(c.Request.Context(), &models.OrderItemPrimaryKey{Order_id: idInt, Item_id: idItemInt})
+	if errors.Is(err, storage.ErrHasReturns) {
+		h.handlerResponse(c, "storage.order_item.delete", http.StatusConflict, "the order item has returns")
+		return
+	}
 	if err != nil {
 		h.handlerResponse(c, "storage.order_item.delete", http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	metrics.StockChanges.WithLabelValues(models.StockMovementReturn).Inc()
+
 	h.handlerResponse(c, "delete order_item", http.StatusNoContent, "Deleted succesfully")
 }
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"app/api/models"
+	"app/pkg/metrics"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security ApiKeyAuth
+// Create Order Return godoc
+// @ID create_order_return
+// @Router /order_return [POST]
+// @Summary Create Order Return
+// @Description Return a quantity of an order item, the product is restocked into the store of the order
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param order_return body models.CreateOrderReturn true "CreateOrderReturnRequest"
+// @Param Idempotency-Key header string false "replays the first response of a retried request"
+// @Success 201 {object} Response{data=models.OrderReturn} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) CreateOrderReturn(c *gin.Context) {
+
+	var createOrderReturn models.CreateOrderReturn
+
+	err := c.ShouldBindJSON(&createOrderReturn)
+	if err != nil {
+		h.handlerResponse(c, "create order_return", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if createOrderReturn.Quantity <= 0 {
+		h.handlerResponse(c, "create order_return", http.StatusBadRequest, "quantity must be positive")
+		return
+	}
+
+	if createOrderReturn.Refund_amount != nil && *createOrderReturn.Refund_amount < 0 {
+		h.handlerResponse(c, "create order_return", http.StatusBadRequest, "refund_amount can not be negative")
+		return
+	}
+
+	createOrderReturn.Actor = h.getActor(c)
+
+	id, err := h.storages.Order().AddReturn(c.Request.Context(), &createOrderReturn)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.order_return.create", http.StatusNotFound, "order item not found")
+			return
+		}
+		h.handlerResponse(c, "storage.order_return.create", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	metrics.OrderReturns.Inc()
+	metrics.StockChanges.WithLabelValues(models.StockMovementReturn).Inc()
+
+	ID, _ := strconv.Atoi(id)
+	resp, err := h.storages.Order().GetReturnByID(c.Request.Context(), &models.OrderReturnPrimaryKey{Return_id: ID})
+	if err != nil {
+		h.handlerResponse(c, "storage.order_return.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "create order_return", http.StatusCreated, resp)
+}
+
+// @Security ApiKeyAuth
+// Get By ID Order Return godoc
+// @ID get_by_id_order_return
+// @Router /order_return/{id} [GET]
+// @Summary Get By ID Order Return
+// @Description Get By ID Order Return
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.OrderReturn} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetByIdOrderReturn(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "get order_return by id", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	resp, err := h.storages.Order().GetReturnByID(c.Request.Context(), &models.OrderReturnPrimaryKey{Return_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.order_return.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get order_return by id", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// Get List Order Return godoc
+// @ID get_list_order_return
+// @Router /order_return [GET]
+// @Summary Get List Order Return
+// @Description Get List Order Return
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Param order_id query string false "order_id"
+// @Success 200 {object} Response{data=models.GetListOrderReturnResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetListOrderReturn(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list order_return", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list order_return", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	orderId, err := h.getIntQuery(c.Query("order_id"))
+	if err != nil {
+		h.handlerResponse(c, "get list order_return", http.StatusBadRequest, "invalid order_id")
+		return
+	}
+
+	resp, err := h.storages.Order().GetListReturn(c.Request.Context(), &models.GetListOrderReturnRequest{
+		Offset:   offset,
+		Limit:    limit,
+		Order_id: orderId,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.order_return.getlist", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get list order_return response", http.StatusOK, resp)
+}
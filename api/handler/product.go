@@ -2,9 +2,7 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
-	"fmt"
-	"log"
+	"app/storage/cachesync"
 	"net/http"
 	"strconv"
 
@@ -33,35 +31,29 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	_, err = h.storages.Brand().GetByID(context.Background(), &models.BrandPrimaryKey{Brand_id: createProduct.Brand_id})
+	_, err = h.storages.Brand().GetByID(c.Request.Context(), &models.BrandPrimaryKey{Brand_id: createProduct.Brand_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.create.GetBrandByID", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Category().GetByID(context.Background(), &models.CategoryPrimaryKey{Category_id: createProduct.Category_id})
+	_, err = h.storages.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Category_id: createProduct.Category_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.create.GetCategoryByID", http.StatusNotFound, err.Error())
 		return
 	}
 
-	id, err := h.storages.Product().Create(context.Background(), &createProduct)
+	id, err := h.storages.Product().Create(c.Request.Context(), &createProduct)
 	if err != nil {
 		h.handlerResponse(c, "storage.product.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 	ID, _ := strconv.Atoi(id)
-	resp, err := h.storages.Product().GetByID(context.Background(), &models.ProductPrimaryKey{Product_id: ID})
+	resp, err := h.storages.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Product_id: ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
-	err = h.caches.ProductCache().Delete()
-	if err != nil {
-		log.Println("error whiling delete cache product:", err.Error())
-		c.JSON(http.StatusBadRequest, err.Error())
-		return
-	}
 	h.handlerResponse(c, "create product", http.StatusCreated, resp)
 }
 
@@ -80,7 +72,13 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 func (h *Handler) GetByIdProduct(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	resp, err := h.storages.Product().GetByID(context.Background(), &models.ProductPrimaryKey{Product_id: id})
+
+	var resp *models.Product
+
+	err := h.readThrough(c.Request.Context(), cachesync.Product, cachesync.KeyByID(id), h.cfg.CacheTTL, &resp, func() (err error) {
+		resp, err = h.storages.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Product_id: id})
+		return err
+	})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -117,44 +115,21 @@ func (h *Handler) GetListProduct(c *gin.Context) {
 		return
 	}
 
-	ok, er := h.caches.ProductCache().Exists()
-	fmt.Println("-------------->", ok, er)
-	if er != nil {
-		log.Println("error whiling check redis:", err.Error())
-		c.JSON(http.StatusBadRequest, err.Error())
-		return
+	req := &models.GetListProductRequest{
+		Offset: offset,
+		Limit:  limit,
+		Search: c.Query("search"),
 	}
 
 	var resp *models.GetListProductResponse
 
-	if !ok {
-		resp, err = h.storages.Product().GetList(context.Background(), &models.GetListProductRequest{
-			Offset: offset,
-			Limit:  limit,
-			Search: c.Query("search"),
-		})
-		if err != nil {
-			h.handlerResponse(c, "storage.product.getlist", http.StatusInternalServerError, err.Error())
-			return
-		}
-
-		err = h.caches.ProductCache().Create(resp)
-
-		if err != nil {
-			log.Println("error whiling set product_list to redis:", err.Error())
-			c.JSON(http.StatusBadRequest, err.Error())
-			return
-		}
-		fmt.Println("Postgress------------------------")
-	} else {
-		resp, err = h.caches.ProductCache().GetList()
-		if err != nil {
-			log.Println("error whiling get list product from redis:", err.Error())
-			c.JSON(http.StatusInternalServerError, err.Error())
-			return
-		}
-		fmt.Println("Redis------------------------")
-
+	err = h.readThrough(c.Request.Context(), cachesync.Product, cacheKeyList(req), h.cfg.CacheListTTL, &resp, func() (err error) {
+		resp, err = h.storages.Product().GetList(c.Request.Context(), req)
+		return err
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.product.getlist", http.StatusInternalServerError, err.Error())
+		return
 	}
 
 	h.handlerResponse(c, "get list product response", http.StatusOK, resp)
@@ -185,13 +160,13 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	_, err = h.storages.Brand().GetByID(context.Background(), &models.BrandPrimaryKey{Brand_id: updateProduct.Brand_id})
+	_, err = h.storages.Brand().GetByID(c.Request.Context(), &models.BrandPrimaryKey{Brand_id: updateProduct.Brand_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.update.GetBrandByID", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Category().GetByID(context.Background(), &models.CategoryPrimaryKey{Category_id: updateProduct.Category_id})
+	_, err = h.storages.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Category_id: updateProduct.Category_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.update.GetCategoryByID", http.StatusNotFound, err.Error())
 		return
@@ -199,7 +174,7 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 
 	updateProduct.Product_id = id
 
-	rowsAffected, err := h.storages.Product().Update(context.Background(), &updateProduct)
+	rowsAffected, err := h.storages.Product().Update(c.Request.Context(), &updateProduct)
 	if err != nil {
 		h.handlerResponse(c, "storage.product.update", http.StatusInternalServerError, err.Error())
 		return
@@ -210,19 +185,12 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Product().GetByID(context.Background(), &models.ProductPrimaryKey{Product_id: id})
+	resp, err := h.storages.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Product_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	err = h.caches.ProductCache().Delete()
-	if err != nil {
-		log.Println("error whiling delete cache product:", err.Error())
-		c.JSON(http.StatusBadRequest, err.Error())
-		return
-	}
-
 	h.handlerResponse(c, "update product", http.StatusAccepted, resp)
 }
 
@@ -253,7 +221,7 @@ func (h *Handler) UpdatePatchProduct(c *gin.Context) {
 
 	object.ID = id
 
-	rowsAffected, err := h.storages.Product().Patch(context.Background(), &object)
+	rowsAffected, err := h.storages.Product().Patch(c.Request.Context(), &object)
 	if err != nil {
 		h.handlerResponse(c, "storage.product.patchupdate", http.StatusInternalServerError, err.Error())
 		return
@@ -264,19 +232,12 @@ func (h *Handler) UpdatePatchProduct(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Product().GetByID(context.Background(), &models.ProductPrimaryKey{Product_id: object.ID})
+	resp, err := h.storages.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Product_id: object.ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	err = h.caches.ProductCache().Delete()
-	if err != nil {
-		log.Println("error whiling delete cache product:", err.Error())
-		c.JSON(http.StatusBadRequest, err.Error())
-		return
-	}
-
 	h.handlerResponse(c, "update patch product", http.StatusAccepted, resp)
 }
 
@@ -296,18 +257,11 @@ func (h *Handler) DeleteProduct(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	_, err := h.storages.Product().Delete(context.Background(), &models.ProductPrimaryKey{Product_id: id})
+	_, err := h.storages.Product().Delete(c.Request.Context(), &models.ProductPrimaryKey{Product_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.product.delete", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	err = h.caches.ProductCache().Delete()
-	if err != nil {
-		log.Println("error whiling delete cache product:", err.Error())
-		c.JSON(http.StatusBadRequest, err.Error())
-		return
-	}
-
 	h.handlerResponse(c, "delete product", http.StatusAccepted, id)
 }
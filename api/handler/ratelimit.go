@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/config"
+	"app/pkg/helper"
+	"app/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware allows limit requests per window to every client of the group, the client
+// is the user of a valid Authorization token or API key and the IP otherwise. While the cache is
+// unavailable the requests are not limited.
+func (h *Handler) RateLimitMiddleware(group string, limit config.RateLimit) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		if limit.Requests <= 0 || limit.Window <= 0 {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if info, ok := c.Get("Auth"); ok {
+			client = "user:" + info.(helper.TokenInfo).UserID
+		} else if info, err := helper.ParseClaims(c.GetHeader("Authorization"), h.keys); err == nil {
+			client = "user:" + info.UserID
+		}
+
+		count, reset, err := h.caches.Limiter().Hit(c.Request.Context(), group+":"+client, limit.Window)
+		if err != nil {
+			h.logger.Debug("rate limit skipped", logger.RequestIDField(c.Request.Context()), logger.Error(err))
+			c.Next()
+			return
+		}
+
+		remaining := int64(limit.Requests) - count
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		c.Header("X-RateLimit-Reset", seconds(reset))
+
+		if count > int64(limit.Requests) {
+			c.Header("Retry-After", seconds(reset))
+			h.handlerResponse(c, "rate limit", http.StatusTooManyRequests, "too many requests, retry later")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// loginLocked answers 429 when login is locked out after its failed logins.
+func (h *Handler) loginLocked(c *gin.Context, login string) bool {
+
+	lock, err := h.caches.Limiter().LoginLock(c.Request.Context(), login)
+	if err != nil {
+		h.logger.Debug("login lockout skipped", logger.RequestIDField(c.Request.Context()), logger.Error(err))
+		return false
+	}
+
+	if lock <= 0 {
+		return false
+	}
+
+	c.Header("Retry-After", seconds(lock))
+	h.handlerResponse(c, "login", http.StatusTooManyRequests, "too many failed logins, retry later")
+
+	return true
+}
+
+// loginFailed counts a failed login of login and locks it once it failed LoginLockoutThreshold
+// times, every further failure doubles the lock.
+func (h *Handler) loginFailed(c *gin.Context, login string) {
+
+	if h.cfg.LoginLockoutThreshold <= 0 {
+		return
+	}
+
+	failures, err := h.caches.Limiter().AddLoginFailure(c.Request.Context(), login, h.cfg.LoginFailuresTTL)
+	if err != nil {
+		h.logger.Debug("login lockout skipped", logger.RequestIDField(c.Request.Context()), logger.Error(err))
+		return
+	}
+
+	if failures < int64(h.cfg.LoginLockoutThreshold) {
+		return
+	}
+
+	lock := lockoutDuration(failures-int64(h.cfg.LoginLockoutThreshold), h.cfg.LoginLockoutBase, h.cfg.LoginLockoutMax)
+
+	err = h.caches.Limiter().LockLogin(c.Request.Context(), login, lock)
+	if err != nil {
+		h.logger.Debug("login lockout skipped", logger.RequestIDField(c.Request.Context()), logger.Error(err))
+	}
+}
+
+// loginSucceeded forgets the failed logins of login.
+func (h *Handler) loginSucceeded(c *gin.Context, login string) {
+
+	err := h.caches.Limiter().ResetLogin(c.Request.Context(), login)
+	if err != nil {
+		h.logger.Debug("login lockout reset skipped", logger.RequestIDField(c.Request.Context()), logger.Error(err))
+	}
+}
+
+// lockoutDuration is base doubled n times, at most max.
+func lockoutDuration(n int64, base, max time.Duration) time.Duration {
+
+	lock := base
+	for i := int64(0); i < n && lock < max; i++ {
+		lock *= 2
+	}
+
+	if lock > max {
+		lock = max
+	}
+
+	return lock
+}
+
+// seconds rounds d up to the whole seconds of the Retry-After header.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
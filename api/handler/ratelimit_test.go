@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/config"
+	"app/pkg/logger"
+	"app/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeLimiter counts the hits of every key in one window ending in reset.
+type fakeLimiter struct {
+	storage.LimiterRepoI
+	hits  map[string]int64
+	reset time.Duration
+}
+
+func (l *fakeLimiter) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	l.hits[key]++
+	return l.hits[key], l.reset, nil
+}
+
+type fakeCache struct {
+	storage.CacheStorageI
+	limiter *fakeLimiter
+}
+
+func (c *fakeCache) Limiter() storage.LimiterRepoI {
+	return c.limiter
+}
+
+func TestLockoutDuration(t *testing.T) {
+	tests := []struct {
+		Name string
+		N    int64
+		Base time.Duration
+		Max  time.Duration
+		Want time.Duration
+	}{
+		{Name: "first lock", N: 0, Base: time.Minute, Max: time.Hour, Want: time.Minute},
+		{Name: "doubled", N: 1, Base: time.Minute, Max: time.Hour, Want: 2 * time.Minute},
+		{Name: "doubled three times", N: 3, Base: time.Minute, Max: time.Hour, Want: 8 * time.Minute},
+		{Name: "capped", N: 10, Base: time.Minute, Max: time.Hour, Want: time.Hour},
+		{Name: "no overflow", N: 1 << 40, Base: time.Minute, Max: time.Hour, Want: time.Hour},
+		{Name: "base over max", N: 0, Base: 2 * time.Hour, Max: time.Hour, Want: time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := lockoutDuration(test.N, test.Base, test.Max); got != test.Want {
+				t.Errorf("lockoutDuration(%d, %s, %s) = %s, want %s", test.N, test.Base, test.Max, got, test.Want)
+			}
+		})
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	tests := []struct {
+		Input time.Duration
+		Want  string
+	}{
+		{Input: 0, Want: "0"},
+		{Input: time.Millisecond, Want: "1"},
+		{Input: time.Second, Want: "1"},
+		{Input: 1500 * time.Millisecond, Want: "2"},
+		{Input: time.Minute, Want: "60"},
+	}
+
+	for _, test := range tests {
+		if got := seconds(test.Input); got != test.Want {
+			t.Errorf("seconds(%s) = %s, want %s", test.Input, got, test.Want)
+		}
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	limiter := &fakeLimiter{hits: map[string]int64{}, reset: 1500 * time.Millisecond}
+	h := NewHandler(&config.Config{}, nil, &fakeCache{limiter: limiter}, nil, logger.NewLogger("test", logger.LevelError))
+
+	r := gin.New()
+	err := r.SetTrustedProxies(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.GET("/brand", h.RateLimitMiddleware("api", config.RateLimit{Requests: 2, Window: time.Minute}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		Name       string
+		RemoteAddr string
+		Forwarded  string
+		Code       int
+		Remaining  string
+		RetryAfter string
+	}{
+		{Name: "first", RemoteAddr: "192.0.2.1:1234", Code: http.StatusOK, Remaining: "1"},
+		{Name: "last allowed", RemoteAddr: "192.0.2.1:1234", Code: http.StatusOK, Remaining: "0"},
+		{Name: "over the limit", RemoteAddr: "192.0.2.1:1234", Code: http.StatusTooManyRequests, Remaining: "0", RetryAfter: "2"},
+		// no proxy is trusted, a forged X-Forwarded-For does not make a new client
+		{Name: "forged forwarded for", RemoteAddr: "192.0.2.1:1234", Forwarded: "203.0.113.9", Code: http.StatusTooManyRequests, Remaining: "0", RetryAfter: "2"},
+		{Name: "other client", RemoteAddr: "192.0.2.2:1234", Code: http.StatusOK, Remaining: "1"},
+	}
+
+	for _, test := range tests {
+
+		req := httptest.NewRequest(http.MethodGet, "/brand", nil)
+		req.RemoteAddr = test.RemoteAddr
+		if test.Forwarded != "" {
+			req.Header.Set("X-Forwarded-For", test.Forwarded)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != test.Code {
+			t.Errorf("%s: code = %d, want %d", test.Name, w.Code, test.Code)
+		}
+
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != test.Remaining {
+			t.Errorf("%s: X-RateLimit-Remaining = %q, want %q", test.Name, got, test.Remaining)
+		}
+
+		if got := w.Header().Get("Retry-After"); got != test.RetryAfter {
+			t.Errorf("%s: Retry-After = %q, want %q", test.Name, got, test.RetryAfter)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"app/api/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const dateLayout = "2006-01-02"
+
+// Sales Report godoc
+// @ID sales_report
+// @Router /report/sales [GET]
+// @Summary Sales Report
+// @Description Gross sales, refunds and net sales per store, refunds are counted by the date of the return
+// @Tags Report
+// @Accept json
+// @Produce json
+// @Param from query string false "from date, 2006-01-02"
+// @Param to query string false "to date, 2006-01-02"
+// @Param store_id query string false "store_id"
+// @Success 200 {object} Response{data=models.SalesReportResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) SalesReport(c *gin.Context) {
+
+	from, to, err := h.getDateRangeQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "sales report", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	storeId, err := h.getIntQuery(c.Query("store_id"))
+	if err != nil {
+		h.handlerResponse(c, "sales report", http.StatusBadRequest, "invalid store_id")
+		return
+	}
+
+	resp, err := h.storages.Report().Sales(c.Request.Context(), &models.SalesReportRequest{
+		From:     from,
+		To:       to,
+		Store_id: storeId,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.report.sales", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "sales report response", http.StatusOK, resp)
+}
+
+// Staff Performance godoc
+// @ID staff_performance
+// @Router /staff/{id}/performance [GET]
+// @Summary Staff Performance
+// @Description Orders handled, revenue, average fulfilment time, late shipments and rejection rate of the staff member by order_date
+// @Tags Report
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param from query string false "from date, 2006-01-02"
+// @Param to query string false "to date, 2006-01-02"
+// @Success 200 {object} Response{data=models.StaffPerformance} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) StaffPerformance(c *gin.Context) {
+
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	from, to, err := h.getDateRangeQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "staff performance", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.storages.Report().StaffPerformance(c.Request.Context(), &models.StaffPerformanceRequest{
+		From:     from,
+		To:       to,
+		Staff_id: id,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.report.staffPerformance", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if len(resp.Staffs) == 0 {
+		h.handlerResponse(c, "storage.report.staffPerformance", http.StatusNotFound, "staff not found")
+		return
+	}
+
+	h.handlerResponse(c, "staff performance response", http.StatusOK, resp.Staffs[0])
+}
+
+// Store Performance godoc
+// @ID store_performance
+// @Router /store/{id}/performance [GET]
+// @Summary Store Performance
+// @Description Performance of every staff member on the orders of the store and the store totals by order_date
+// @Tags Report
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param from query string false "from date, 2006-01-02"
+// @Param to query string false "to date, 2006-01-02"
+// @Success 200 {object} Response{data=models.StaffPerformanceResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) StorePerformance(c *gin.Context) {
+
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	from, to, err := h.getDateRangeQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "store performance", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.report.storePerformance.GetStoreByID", http.StatusNotFound, err.Error())
+		return
+	}
+
+	resp, err := h.storages.Report().StaffPerformance(c.Request.Context(), &models.StaffPerformanceRequest{
+		From:     from,
+		To:       to,
+		Store_id: id,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.report.storePerformance", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "store performance response", http.StatusOK, resp)
+}
+
+// Overdue Report godoc
+// @ID overdue_report
+// @Router /report/overdue [GET]
+// @Summary Overdue Report
+// @Description Open orders past their required_date and the ones at risk of being late per store
+// @Tags Report
+// @Accept json
+// @Produce json
+// @Param store_id query string false "store_id"
+// @Success 200 {object} Response{data=models.OverdueReportResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) OverdueReport(c *gin.Context) {
+
+	storeId, err := h.getIntQuery(c.Query("store_id"))
+	if err != nil {
+		h.handlerResponse(c, "overdue report", http.StatusBadRequest, "invalid store_id")
+		return
+	}
+
+	resp, err := h.storages.Report().Overdue(c.Request.Context(), &models.OverdueReportRequest{
+		Store_id:     storeId,
+		At_risk_days: h.cfg.OrderAtRiskDays,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.report.overdue", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "overdue report response", http.StatusOK, resp)
+}
+
+// getDateRangeQuery reads from and to query params, by default the range is the last 30 days.
+func (h *Handler) getDateRangeQuery(c *gin.Context) (string, string, error) {
+
+	var (
+		to   = time.Now()
+		from = to.AddDate(0, 0, -30)
+		err  error
+	)
+
+	if len(c.Query("to")) > 0 {
+		to, err = time.Parse(dateLayout, c.Query("to"))
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if len(c.Query("from")) > 0 {
+		from, err = time.Parse(dateLayout, c.Query("from"))
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return from.Format(dateLayout), to.Format(dateLayout), nil
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"regexp"
+	"time"
+
+	"app/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID limits the propagated request ids to what is safe to log and echo back.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestLogMiddleware propagates the X-Request-ID header or generates one, puts it on the
+// request context for the handlers and repos, and logs every request with redacted headers.
+func (h *Handler) RequestLogMiddleware() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		start := time.Now()
+
+		requestID := c.GetHeader(requestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(requestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+
+		fields := []logger.Field{
+			logger.String("request_id", requestID),
+			logger.String("method", c.Request.Method),
+			logger.String("route", c.FullPath()),
+			logger.String("path", c.Request.URL.Path),
+			logger.Int("status", c.Writer.Status()),
+			logger.Any("latency", time.Since(start).String()),
+			logger.String("client_ip", c.ClientIP()),
+			logger.Any("headers", logger.RedactHeaders(c.Request.Header)),
+		}
+
+		// the tracing middleware runs after this one, its span is on the request by now
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.HasTraceID() {
+			fields = append(fields, logger.String("trace_id", span.TraceID().String()))
+		}
+
+		if len(c.Errors) > 0 {
+			fields = append(fields, logger.String("errors", c.Errors.String()))
+		}
+
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			h.logger.Error("request", fields...)
+		case status >= 400:
+			h.logger.Warn("request", fields...)
+		default:
+			h.logger.Info("request", fields...)
+		}
+	}
+}
@@ -2,11 +2,11 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
 )
 
 // Create Staff godoc
@@ -31,25 +31,27 @@ func (h *Handler) CreateStaff(c *gin.Context) {
 		return
 	}
 
-	_, err = h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: createStaff.Store_id})
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: createStaff.Store_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.create.GetStoreByID", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Staff().GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: createStaff.Manager_id})
-	if err != nil {
-		h.handlerResponse(c, "storage.staff.create.GetManagerByID", http.StatusNotFound, err.Error())
-		return
+	if createStaff.Manager_id > 0 {
+		_, err = h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: createStaff.Manager_id})
+		if err != nil {
+			h.handlerResponse(c, "storage.staff.create.GetManagerByID", http.StatusNotFound, err.Error())
+			return
+		}
 	}
 
-	id, err := h.storages.Staff().Create(context.Background(), &createStaff)
+	id, err := h.storages.Staff().Create(c.Request.Context(), &createStaff)
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 	ID, _ := strconv.Atoi(id)
-	resp, err := h.storages.Staff().GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: ID})
+	resp, err := h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -73,7 +75,7 @@ func (h *Handler) CreateStaff(c *gin.Context) {
 func (h *Handler) GetByIdStaff(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	resp, err := h.storages.Staff().GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: id})
+	resp, err := h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -93,6 +95,7 @@ func (h *Handler) GetByIdStaff(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param store_id query string false "store_id"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
@@ -110,10 +113,17 @@ func (h *Handler) GetListStaff(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Staff().GetList(context.Background(), &models.GetListStaffRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+	storeId, err := h.getIntQuery(c.Query("store_id"))
+	if err != nil {
+		h.handlerResponse(c, "get list staff", http.StatusBadRequest, "invalid store_id")
+		return
+	}
+
+	resp, err := h.storages.Staff().GetList(c.Request.Context(), &models.GetListStaffRequest{
+		Offset:   offset,
+		Limit:    limit,
+		Search:   c.Query("search"),
+		Store_id: storeId,
 	})
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.getlist", http.StatusInternalServerError, err.Error())
@@ -148,21 +158,27 @@ func (h *Handler) UpdateStaff(c *gin.Context) {
 		return
 	}
 
-	_, err = h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: updateStaff.Store_id})
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: updateStaff.Store_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.update.GetStoreByID", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Staff().GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: updateStaff.Manager_id})
-	if err != nil {
-		h.handlerResponse(c, "storage.staff.update.GetManagerByID", http.StatusNotFound, err.Error())
+	if updateStaff.Manager_id > 0 {
+		_, err = h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: updateStaff.Manager_id})
+		if err != nil {
+			h.handlerResponse(c, "storage.staff.update.GetManagerByID", http.StatusNotFound, err.Error())
+			return
+		}
+	}
+
+	if !h.checkManagerCycle(c, "storage.staff.update", id, updateStaff.Manager_id) {
 		return
 	}
 
 	updateStaff.Staff_id = id
 
-	rowsAffected, err := h.storages.Staff().Update(context.Background(), &updateStaff)
+	rowsAffected, err := h.storages.Staff().Update(c.Request.Context(), &updateStaff)
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.update", http.StatusInternalServerError, err.Error())
 		return
@@ -173,7 +189,7 @@ func (h *Handler) UpdateStaff(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Staff().GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: id})
+	resp, err := h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -209,7 +225,13 @@ func (h *Handler) UpdatePatchStaff(c *gin.Context) {
 
 	object.ID = id
 
-	rowsAffected, err := h.storages.Staff().Patch(context.Background(), &object)
+	if managerId, ok := object.Fields["manager_id"]; ok {
+		if !h.checkManagerCycle(c, "storage.staff.patch", id, cast.ToInt(managerId)) {
+			return
+		}
+	}
+
+	rowsAffected, err := h.storages.Staff().Patch(c.Request.Context(), &object)
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.patchupdate", http.StatusInternalServerError, err.Error())
 		return
@@ -220,7 +242,7 @@ func (h *Handler) UpdatePatchStaff(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Staff().GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: object.ID})
+	resp, err := h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: object.ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -245,7 +267,7 @@ func (h *Handler) DeleteStaff(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	_, err := h.storages.Staff().Delete(context.Background(), &models.StaffPrimaryKey{Staff_id: id})
+	_, err := h.storages.Staff().Delete(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.staff.delete", http.StatusInternalServerError, err.Error())
 		return
@@ -253,3 +275,216 @@ func (h *Handler) DeleteStaff(c *gin.Context) {
 
 	h.handlerResponse(c, "delete staff", http.StatusAccepted, id)
 }
+
+// Get Staff Managers godoc
+// @ID get_staff_managers
+// @Router /staff/{id}/managers [GET]
+// @Summary Get Staff Managers
+// @Description Get the manager chain of the staff member, from the direct manager up to the top
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.GetStaffHierarchyResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetStaffManagers(c *gin.Context) {
+
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	_, err := h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.managers.getByID", http.StatusNotFound, err.Error())
+		return
+	}
+
+	resp, err := h.storages.Staff().GetManagers(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.managers", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get staff managers", http.StatusOK, resp)
+}
+
+// Get Staff Reports godoc
+// @ID get_staff_reports
+// @Router /staff/{id}/reports [GET]
+// @Summary Get Staff Reports
+// @Description Get the direct and indirect reports of the staff member
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param direct query bool false "only direct reports"
+// @Success 200 {object} Response{data=models.GetStaffHierarchyResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetStaffReports(c *gin.Context) {
+
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	direct := false
+	if len(c.Query("direct")) > 0 {
+		var err error
+		direct, err = strconv.ParseBool(c.Query("direct"))
+		if err != nil {
+			h.handlerResponse(c, "get staff reports", http.StatusBadRequest, "invalid direct")
+			return
+		}
+	}
+
+	_, err := h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.reports.getByID", http.StatusNotFound, err.Error())
+		return
+	}
+
+	resp, err := h.storages.Staff().GetReports(c.Request.Context(), &models.GetStaffHierarchyRequest{
+		Staff_id: id,
+		Direct:   direct,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.reports", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get staff reports", http.StatusOK, resp)
+}
+
+// Get Store Staff godoc
+// @ID get_store_staff
+// @Router /store/{id}/staff [GET]
+// @Summary Get Store Staff
+// @Description Get the staff members of the store
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Param search query string false "search"
+// @Success 200 {object} Response{data=models.GetListStaffResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetStoreStaff(c *gin.Context) {
+
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get store staff", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get store staff", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.store.GetStoreByID", http.StatusNotFound, err.Error())
+		return
+	}
+
+	resp, err := h.storages.Staff().GetList(c.Request.Context(), &models.GetListStaffRequest{
+		Offset:   offset,
+		Limit:    limit,
+		Search:   c.Query("search"),
+		Store_id: id,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.getlist", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get store staff", http.StatusOK, resp)
+}
+
+// Reassign Staff godoc
+// @ID reassign_staff
+// @Router /staff/{id}/store [PUT]
+// @Summary Reassign Staff
+// @Description Move the staff member to another store and optionally to another manager, manager_id 0 makes the staff member a top level manager
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param staff body models.ReassignStaff true "ReassignStaffRequest"
+// @Success 200 {object} Response{data=models.Staff} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) ReassignStaff(c *gin.Context) {
+
+	var reassignStaff models.ReassignStaff
+
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	err := c.ShouldBindJSON(&reassignStaff)
+	if err != nil {
+		h.handlerResponse(c, "reassign staff", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: reassignStaff.Store_id})
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.reassign.GetStoreByID", http.StatusNotFound, err.Error())
+		return
+	}
+
+	if reassignStaff.Manager_id != nil {
+
+		if *reassignStaff.Manager_id > 0 {
+			_, err = h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: *reassignStaff.Manager_id})
+			if err != nil {
+				h.handlerResponse(c, "storage.staff.reassign.GetManagerByID", http.StatusNotFound, err.Error())
+				return
+			}
+		}
+
+		if !h.checkManagerCycle(c, "storage.staff.reassign", id, *reassignStaff.Manager_id) {
+			return
+		}
+	}
+
+	reassignStaff.Staff_id = id
+
+	rowsAffected, err := h.storages.Staff().Reassign(c.Request.Context(), &reassignStaff)
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.reassign", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if rowsAffected <= 0 {
+		h.handlerResponse(c, "storage.staff.reassign", http.StatusNotFound, "no rows affected")
+		return
+	}
+
+	resp, err := h.storages.Staff().GetByID(c.Request.Context(), &models.StaffPrimaryKey{Staff_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.staff.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "reassign staff", http.StatusAccepted, resp)
+}
+
+// checkManagerCycle writes a bad request response and returns false when managerId
+// can not become the manager of staffId without creating a cycle in the manager tree.
+func (h *Handler) checkManagerCycle(c *gin.Context, path string, staffId, managerId int) bool {
+
+	cycle, err := h.storages.Staff().HasManagerCycle(c.Request.Context(), staffId, managerId)
+	if err != nil {
+		h.handlerResponse(c, path+".checkManagerCycle", http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	if cycle {
+		h.handlerResponse(c, path+".checkManagerCycle", http.StatusBadRequest, "manager can not be the staff member or one of the staff member reports")
+		return false
+	}
+
+	return true
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
+	"app/pkg/metrics"
+	"app/storage/cachesync"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -18,7 +20,8 @@ import (
 // @Accept json
 // @Produce json
 // @Param stock body models.CreateStock true "CreateStockRequest"
-// @Success 200 {object} Response{data=string} "Success Request"
+// @Param Idempotency-Key header string false "replays the first response of a retried request"
+// @Success 201 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
 func (h *Handler) CreateStock(c *gin.Context) {
@@ -31,26 +34,34 @@ func (h *Handler) CreateStock(c *gin.Context) {
 		return
 	}
 
-	_, err = h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: createStock.Store_id})
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: createStock.Store_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.stock.create.GetStoreByID", http.StatusNotFound, err.Error())
 		return
 	}
 
-	_, err = h.storages.Product().GetByID(context.Background(), &models.ProductPrimaryKey{Product_id: createStock.Product_id})
+	_, err = h.storages.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Product_id: createStock.Product_id})
 	if err != nil {
 		h.handlerResponse(c, "storage.stock.create.GetProductByID", http.StatusNotFound, err.Error())
 		return
 	}
 
-	id, err := h.storages.Stock().Create(context.Background(), &createStock)
+	createStock.Actor = h.getActor(c)
+
+	key, err := h.storages.Stock().Create(c.Request.Context(), &createStock)
 	if err != nil {
 		h.handlerResponse(c, "storage.stock.create", http.StatusInternalServerError, err.Error())
 		return
 	}
-	ID, _ := strconv.Atoi(id)
-	resp, err := h.storages.Stock().GetByID(context.Background(), &models.StockPrimaryKey{Store_id: ID})
+
+	metrics.StockChanges.WithLabelValues(models.StockMovementReceiving).Inc()
+
+	resp, err := h.storages.Stock().GetByID(c.Request.Context(), key)
 	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.stock.getByID", http.StatusNotFound, "stock not found")
+			return
+		}
 		h.handlerResponse(c, "storage.stock.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -60,21 +71,36 @@ func (h *Handler) CreateStock(c *gin.Context) {
 
 // Get By ID Stock godoc
 // @ID get_by_id_stock
-// @Router /stock/{id} [GET]
+// @Router /stock/{store_id}/{product_id} [GET]
 // @Summary Get By ID Stock
 // @Description Get By ID Stock
 // @Tags Stock
 // @Accept json
 // @Produce json
-// @Param id path string true "id"
+// @Param store_id path string true "store_id"
+// @Param product_id path string true "product_id"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
 func (h *Handler) GetByIdStock(c *gin.Context) {
 
-	id, _ := strconv.Atoi(c.Param("id"))
-	resp, err := h.storages.Stock().GetByID(context.Background(), &models.StockPrimaryKey{Store_id: id})
+	key, err := h.getStockPrimaryKey(c)
+	if err != nil {
+		h.handlerResponse(c, "get stock by id", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var resp *models.Stock
+
+	err = h.readThrough(c.Request.Context(), cachesync.Stock, cachesync.KeyByID(key.Store_id, key.Product_id), h.cfg.CacheTTL, &resp, func() (err error) {
+		resp, err = h.storages.Stock().GetByID(c.Request.Context(), key)
+		return err
+	})
 	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.stock.getByID", http.StatusNotFound, "stock not found")
+			return
+		}
 		h.handlerResponse(c, "storage.stock.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -93,6 +119,8 @@ func (h *Handler) GetByIdStock(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param store_id query string false "store_id"
+// @Param product_id query string false "product_id"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
@@ -110,10 +138,31 @@ func (h *Handler) GetListStock(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Stock().GetList(context.Background(), &models.GetListStockRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+	storeId, err := h.getIntQuery(c.Query("store_id"))
+	if err != nil {
+		h.handlerResponse(c, "get list stock", http.StatusBadRequest, "invalid store_id")
+		return
+	}
+
+	productId, err := h.getIntQuery(c.Query("product_id"))
+	if err != nil {
+		h.handlerResponse(c, "get list stock", http.StatusBadRequest, "invalid product_id")
+		return
+	}
+
+	req := &models.GetListStockRequest{
+		Offset:     offset,
+		Limit:      limit,
+		Search:     c.Query("search"),
+		Store_id:   storeId,
+		Product_id: productId,
+	}
+
+	var resp *models.GetListStockResponse
+
+	err = h.readThrough(c.Request.Context(), cachesync.Stock, cacheKeyList(req), h.cfg.CacheListTTL, &resp, func() (err error) {
+		resp, err = h.storages.Stock().GetList(c.Request.Context(), req)
+		return err
 	})
 	if err != nil {
 		h.handlerResponse(c, "storage.stock.getlist", http.StatusInternalServerError, err.Error())
@@ -123,15 +172,107 @@ func (h *Handler) GetListStock(c *gin.Context) {
 	h.handlerResponse(c, "get list stock response", http.StatusOK, resp)
 }
 
+// Get Store Stock godoc
+// @ID get_store_stock
+// @Router /store/{id}/stock [GET]
+// @Summary Get Store Stock
+// @Description Full inventory of the store
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Param search query string false "search"
+// @Success 200 {object} Response{data=models.GetListStockResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetStoreStock(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "get store stock", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get store stock", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get store stock", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.store.getByID", http.StatusNotFound, err.Error())
+		return
+	}
+
+	req := &models.GetListStockRequest{
+		Offset:   offset,
+		Limit:    limit,
+		Search:   c.Query("search"),
+		Store_id: id,
+	}
+
+	var resp *models.GetListStockResponse
+
+	err = h.readThrough(c.Request.Context(), cachesync.Stock, cacheKeyList(req), h.cfg.CacheListTTL, &resp, func() (err error) {
+		resp, err = h.storages.Stock().GetList(c.Request.Context(), req)
+		return err
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.stock.getlist", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get store stock response", http.StatusOK, resp)
+}
+
+// Get Product Availability godoc
+// @ID get_product_availability
+// @Router /product/{id}/availability [GET]
+// @Summary Get Product Availability
+// @Description Quantity of the product across all stores
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.ProductAvailability} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetProductAvailability(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "get product availability", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	resp, err := h.storages.Stock().GetAvailability(c.Request.Context(), &models.ProductPrimaryKey{Product_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.stock.getAvailability", http.StatusNotFound, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get product availability", http.StatusOK, resp)
+}
+
 // Update Put Stock godoc
-// @ID updat_patch_stock
-// @Router /stock/{id} [PUT]
+// @ID updat_put_stock
+// @Router /stock/{store_id}/{product_id} [PUT]
 // @Summary Update Put Stock
 // @Description Update Put Stock
 // @Tags Stock
 // @Accept json
 // @Produce json
-// @Param id path string true "id"
+// @Param store_id path string true "store_id"
+// @Param product_id path string true "product_id"
 // @Param stock body models.UpdateStock true "UpdateStock"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
@@ -140,29 +281,23 @@ func (h *Handler) UpdateStock(c *gin.Context) {
 
 	var updateStock models.UpdateStock
 
-	id, _ := strconv.Atoi(c.Param("id"))
-
-	err := c.ShouldBindJSON(&updateStock)
+	key, err := h.getStockPrimaryKey(c)
 	if err != nil {
 		h.handlerResponse(c, "update stock", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	_, err = h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: updateStock.Store_id})
-	if err != nil {
-		h.handlerResponse(c, "storage.stock.update.GetStoreByID", http.StatusNotFound, err.Error())
-		return
-	}
-
-	_, err = h.storages.Product().GetByID(context.Background(), &models.ProductPrimaryKey{Product_id: updateStock.Product_id})
+	err = c.ShouldBindJSON(&updateStock)
 	if err != nil {
-		h.handlerResponse(c, "storage.stock.update.GetProductByID", http.StatusNotFound, err.Error())
+		h.handlerResponse(c, "update stock", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	updateStock.Store_id = id
+	updateStock.Store_id = key.Store_id
+	updateStock.Product_id = key.Product_id
+	updateStock.Actor = h.getActor(c)
 
-	rowsAffected, err := h.storages.Stock().Update(context.Background(), &updateStock)
+	rowsAffected, err := h.storages.Stock().Update(c.Request.Context(), &updateStock)
 	if err != nil {
 		h.handlerResponse(c, "storage.stock.update", http.StatusInternalServerError, err.Error())
 		return
@@ -173,8 +308,14 @@ func (h *Handler) UpdateStock(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Stock().GetByID(context.Background(), &models.StockPrimaryKey{Store_id: id})
+	metrics.StockChanges.WithLabelValues(models.StockMovementAdjustment).Inc()
+
+	resp, err := h.storages.Stock().GetByID(c.Request.Context(), key)
 	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.stock.getByID", http.StatusNotFound, "stock not found")
+			return
+		}
 		h.handlerResponse(c, "storage.stock.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -184,32 +325,39 @@ func (h *Handler) UpdateStock(c *gin.Context) {
 
 // Update Patch Stock godoc
 // @ID updat_patch_stock
-// @Router /stock/{id} [PATCH]
+// @Router /stock/{store_id}/{product_id} [PATCH]
 // @Summary Update Patch Stock
 // @Description Update Patch Stock
 // @Tags Stock
 // @Accept json
 // @Produce json
-// @Param id path string true "id"
-// @Param stock body models.PatchRequest true "UpdatPatchStockRequest"
+// @Param store_id path string true "store_id"
+// @Param product_id path string true "product_id"
+// @Param stock body models.PatchStock true "UpdatPatchStockRequest"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
 func (h *Handler) UpdatePatchStock(c *gin.Context) {
 
-	var object models.PatchRequest
+	var object models.PatchStock
 
-	id, _ := strconv.Atoi(c.Param("id"))
+	key, err := h.getStockPrimaryKey(c)
+	if err != nil {
+		h.handlerResponse(c, "update patch stock", http.StatusBadRequest, err.Error())
+		return
+	}
 
-	err := c.ShouldBindJSON(&object)
+	err = c.ShouldBindJSON(&object)
 	if err != nil {
 		h.handlerResponse(c, "update patch stock", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	object.ID = id
+	object.Store_id = key.Store_id
+	object.Product_id = key.Product_id
+	object.Actor = h.getActor(c)
 
-	rowsAffected, err := h.storages.Stock().Patch(context.Background(), &object)
+	rowsAffected, err := h.storages.Stock().Patch(c.Request.Context(), &object)
 	if err != nil {
 		h.handlerResponse(c, "storage.stock.patchupdate", http.StatusInternalServerError, err.Error())
 		return
@@ -220,8 +368,14 @@ func (h *Handler) UpdatePatchStock(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Stock().GetByID(context.Background(), &models.StockPrimaryKey{Store_id: object.ID})
+	metrics.StockChanges.WithLabelValues(models.StockMovementAdjustment).Inc()
+
+	resp, err := h.storages.Stock().GetByID(c.Request.Context(), key)
 	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.stock.getByID", http.StatusNotFound, "stock not found")
+			return
+		}
 		h.handlerResponse(c, "storage.stock.getByID", http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -230,26 +384,131 @@ func (h *Handler) UpdatePatchStock(c *gin.Context) {
 }
 
 // Delete Stock godoc
-// @ID get_by_id_stock
-// @Router /stock/{id} [DELETE]
+// @ID delete_stock
+// @Router /stock/{store_id}/{product_id} [DELETE]
 // @Summary Delete Stock
 // @Description Delete Stock
 // @Tags Stock
 // @Accept json
 // @Produce json
-// @Param id path string true "id"
+// @Param store_id path string true "store_id"
+// @Param product_id path string true "product_id"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server Error"
 func (h *Handler) DeleteStock(c *gin.Context) {
 
-	id, _ := strconv.Atoi(c.Param("id"))
+	key, err := h.getStockPrimaryKey(c)
+	if err != nil {
+		h.handlerResponse(c, "delete stock", http.StatusBadRequest, err.Error())
+		return
+	}
 
-	_, err := h.storages.Stock().Delete(context.Background(), &models.StockPrimaryKey{Store_id: id})
+	rowsAffected, err := h.storages.Stock().Delete(c.Request.Context(), &models.DeleteStock{
+		Store_id:   key.Store_id,
+		Product_id: key.Product_id,
+		Actor:      h.getActor(c),
+	})
 	if err != nil {
 		h.handlerResponse(c, "storage.stock.delete", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	h.handlerResponse(c, "delete stock", http.StatusAccepted, id)
+	if rowsAffected <= 0 {
+		h.handlerResponse(c, "storage.stock.delete", http.StatusNotFound, "now rows affected")
+		return
+	}
+
+	metrics.StockChanges.WithLabelValues(models.StockMovementAdjustment).Inc()
+
+	h.handlerResponse(c, "delete stock", http.StatusAccepted, key)
+}
+
+// Transfer Stock godoc
+// @ID transfer_stock
+// @Router /stock/transfer [POST]
+// @Summary Transfer Stock
+// @Description Move quantity of a product from one store to another
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Param stock body models.TransferStock true "TransferStockRequest"
+// @Param Idempotency-Key header string false "replays the first response of a retried request"
+// @Success 200 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) TransferStock(c *gin.Context) {
+
+	var transferStock models.TransferStock
+
+	err := c.ShouldBindJSON(&transferStock)
+	if err != nil {
+		h.handlerResponse(c, "transfer stock", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if transferStock.Quantity <= 0 {
+		h.handlerResponse(c, "transfer stock", http.StatusBadRequest, "quantity must be positive")
+		return
+	}
+
+	if transferStock.From_store_id == transferStock.To_store_id {
+		h.handlerResponse(c, "transfer stock", http.StatusBadRequest, "source and destination stores are the same")
+		return
+	}
+
+	_, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: transferStock.To_store_id})
+	if err != nil {
+		h.handlerResponse(c, "storage.stock.transfer.GetStoreByID", http.StatusNotFound, err.Error())
+		return
+	}
+
+	transferStock.Actor = h.getActor(c)
+
+	err = h.storages.Stock().Transfer(c.Request.Context(), &transferStock)
+	if err != nil {
+		h.handlerResponse(c, "storage.stock.transfer", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	metrics.StockChanges.WithLabelValues(models.StockMovementTransfer).Inc()
+
+	from, err := h.storages.Stock().GetByID(c.Request.Context(), &models.StockPrimaryKey{Store_id: transferStock.From_store_id, Product_id: transferStock.Product_id})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.stock.getByID", http.StatusNotFound, "stock not found")
+			return
+		}
+		h.handlerResponse(c, "storage.stock.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	to, err := h.storages.Stock().GetByID(c.Request.Context(), &models.StockPrimaryKey{Store_id: transferStock.To_store_id, Product_id: transferStock.Product_id})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.stock.getByID", http.StatusNotFound, "stock not found")
+			return
+		}
+		h.handlerResponse(c, "storage.stock.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := []*models.Stock{from, to}
+
+	h.handlerResponse(c, "transfer stock", http.StatusAccepted, resp)
+}
+
+func (h *Handler) getStockPrimaryKey(c *gin.Context) (*models.StockPrimaryKey, error) {
+
+	storeId, err := strconv.Atoi(c.Param("store_id"))
+	if err != nil {
+		return nil, errors.New("invalid store_id")
+	}
+
+	productId, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		return nil, errors.New("invalid product_id")
+	}
+
+	return &models.StockPrimaryKey{Store_id: storeId, Product_id: productId}, nil
 }
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"app/api/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get List Stock Movement godoc
+// @ID get_list_stock_movement
+// @Router /stock_movement [GET]
+// @Summary Get List Stock Movement
+// @Description Get List Stock Movement
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Param store_id query string false "store_id"
+// @Param product_id query string false "product_id"
+// @Param reason query string false "sale, return, adjustment, transfer or receiving"
+// @Success 200 {object} Response{data=models.GetListStockMovementResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetListStockMovement(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list stock movement", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list stock movement", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	storeId, err := h.getIntQuery(c.Query("store_id"))
+	if err != nil {
+		h.handlerResponse(c, "get list stock movement", http.StatusBadRequest, "invalid store_id")
+		return
+	}
+
+	productId, err := h.getIntQuery(c.Query("product_id"))
+	if err != nil {
+		h.handlerResponse(c, "get list stock movement", http.StatusBadRequest, "invalid product_id")
+		return
+	}
+
+	reason := c.Query("reason")
+	if len(reason) > 0 && !models.IsValidStockMovementReason(reason) {
+		h.handlerResponse(c, "get list stock movement", http.StatusBadRequest, "invalid reason")
+		return
+	}
+
+	resp, err := h.storages.StockMovement().GetList(c.Request.Context(), &models.GetListStockMovementRequest{
+		Offset:     offset,
+		Limit:      limit,
+		Store_id:   storeId,
+		Product_id: productId,
+		Reason:     reason,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.stock_movement.getlist", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get list stock movement response", http.StatusOK, resp)
+}
+
+// Reconcile Stock godoc
+// @ID reconcile_stock
+// @Router /stock_movement/reconcile [GET]
+// @Summary Reconcile Stock
+// @Description Recompute stock from the movement ledger and list the store products where it differs from current stock
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Param store_id query string false "store_id"
+// @Param product_id query string false "product_id"
+// @Success 200 {object} Response{data=models.StockReconciliationResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) ReconcileStock(c *gin.Context) {
+
+	storeId, err := h.getIntQuery(c.Query("store_id"))
+	if err != nil {
+		h.handlerResponse(c, "reconcile stock", http.StatusBadRequest, "invalid store_id")
+		return
+	}
+
+	productId, err := h.getIntQuery(c.Query("product_id"))
+	if err != nil {
+		h.handlerResponse(c, "reconcile stock", http.StatusBadRequest, "invalid product_id")
+		return
+	}
+
+	resp, err := h.storages.StockMovement().Reconcile(c.Request.Context(), &models.StockReconciliationRequest{
+		Store_id:   storeId,
+		Product_id: productId,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.stock_movement.reconcile", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "reconcile stock response", http.StatusOK, resp)
+}
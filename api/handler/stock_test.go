@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/api/models"
+	"app/config"
+	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type fakeStoreRepo struct {
+	storage.StoreRepoI
+}
+
+func (r *fakeStoreRepo) GetByID(ctx context.Context, req *models.StorePrimaryKey) (*models.Store, error) {
+	return &models.Store{Store_id: req.Store_id}, nil
+}
+
+type fakeProductRepo struct {
+	storage.ProductRepoI
+}
+
+func (r *fakeProductRepo) GetByID(ctx context.Context, req *models.ProductPrimaryKey) (*models.Product, error) {
+	return &models.Product{Product_id: req.Product_id}, nil
+}
+
+// fakeStockRepo keeps the stocks in memory, every change is one movement of the ledger
+// and actor is who made the last one.
+type fakeStockRepo struct {
+	storage.StockRepoI
+	stocks map[models.StockPrimaryKey]*models.Stock
+	actor  string
+}
+
+func (r *fakeStockRepo) Create(ctx context.Context, req *models.CreateStock) (*models.StockPrimaryKey, error) {
+
+	r.actor = req.Actor
+
+	key := models.StockPrimaryKey{Store_id: req.Store_id, Product_id: req.Product_id}
+	r.stocks[key] = &models.Stock{Store_id: req.Store_id, Product_id: req.Product_id, Quantity: req.Quantity}
+
+	return &key, nil
+}
+
+func (r *fakeStockRepo) GetByID(ctx context.Context, req *models.StockPrimaryKey) (*models.Stock, error) {
+
+	stock, ok := r.stocks[*req]
+	if !ok {
+		return nil, errNoRows
+	}
+
+	return stock, nil
+}
+
+func (r *fakeStockRepo) Delete(ctx context.Context, req *models.DeleteStock) (int64, error) {
+
+	r.actor = req.Actor
+
+	key := models.StockPrimaryKey{Store_id: req.Store_id, Product_id: req.Product_id}
+	if _, ok := r.stocks[key]; !ok {
+		return 0, nil
+	}
+	delete(r.stocks, key)
+
+	return 1, nil
+}
+
+func (s *fakeStorage) Store() storage.StoreRepoI {
+	return s.store
+}
+
+func (s *fakeStorage) Product() storage.ProductRepoI {
+	return s.product
+}
+
+func (s *fakeStorage) Stock() storage.StockRepoI {
+	return s.stock
+}
+
+// TestStockChangesMetric checks that the stock routes count their change under the reason the
+// ledger records it with, and that a change without a token is recorded with the client IP.
+func TestStockChangesMetric(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	stock := &fakeStockRepo{stocks: map[models.StockPrimaryKey]*models.Stock{}}
+
+	h := NewHandler(&config.Config{}, &fakeStorage{
+		store:   &fakeStoreRepo{},
+		product: &fakeProductRepo{},
+		stock:   stock,
+	}, nil, nil, logger.NewLogger("test", logger.LevelError))
+
+	r := gin.New()
+	r.POST("/stock", h.CreateStock)
+	r.DELETE("/stock/:store_id/:product_id", h.DeleteStock)
+
+	tests := []struct {
+		Name   string
+		Method string
+		Path   string
+		Body   string
+		Code   int
+		Reason string
+		Actor  string
+	}{
+		{
+			Name:   "create",
+			Method: http.MethodPost,
+			Path:   "/stock",
+			Body:   `{"store_id": 1, "product_id": 1, "quantity": 5}`,
+			Code:   http.StatusCreated,
+			Reason: models.StockMovementReceiving,
+			Actor:  "anonymous:192.0.2.1",
+		},
+		{
+			Name:   "delete",
+			Method: http.MethodDelete,
+			Path:   "/stock/1/1",
+			Code:   http.StatusAccepted,
+			Reason: models.StockMovementAdjustment,
+			Actor:  "anonymous:192.0.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			before := testutil.ToFloat64(metrics.StockChanges.WithLabelValues(tt.Reason))
+			stock.actor = ""
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.Method, tt.Path, strings.NewReader(tt.Body)))
+
+			if w.Code != tt.Code {
+				t.Fatalf("%s %s = %d, want %d: %s", tt.Method, tt.Path, w.Code, tt.Code, w.Body.String())
+			}
+
+			if got := testutil.ToFloat64(metrics.StockChanges.WithLabelValues(tt.Reason)) - before; got != 1 {
+				t.Errorf("stock_changes_total{reason=%q} grew by %v, want 1", tt.Reason, got)
+			}
+
+			if stock.actor != tt.Actor {
+				t.Errorf("actor: got %q, want %q", stock.actor, tt.Actor)
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
+	"app/storage/cachesync"
 	"net/http"
 	"strconv"
 
@@ -31,13 +31,13 @@ func (h *Handler) CreateStore(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storages.Store().Create(context.Background(), &createStore)
+	id, err := h.storages.Store().Create(c.Request.Context(), &createStore)
 	if err != nil {
 		h.handlerResponse(c, "storage.store.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 	ID, _ := strconv.Atoi(id)
-	resp, err := h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: ID})
+	resp, err := h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.store.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -61,7 +61,13 @@ func (h *Handler) CreateStore(c *gin.Context) {
 func (h *Handler) GetByIdStore(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	resp, err := h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: id})
+
+	var resp *models.Store
+
+	err := h.readThrough(c.Request.Context(), cachesync.Store, cachesync.KeyByID(id), h.cfg.CacheTTL, &resp, func() (err error) {
+		resp, err = h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: id})
+		return err
+	})
 	if err != nil {
 		h.handlerResponse(c, "storage.store.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -98,10 +104,17 @@ func (h *Handler) GetListStore(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Store().GetList(context.Background(), &models.GetListStoreRequest{
+	req := &models.GetListStoreRequest{
 		Offset: offset,
 		Limit:  limit,
 		Search: c.Query("search"),
+	}
+
+	var resp *models.GetListStoreResponse
+
+	err = h.readThrough(c.Request.Context(), cachesync.Store, cacheKeyList(req), h.cfg.CacheListTTL, &resp, func() (err error) {
+		resp, err = h.storages.Store().GetList(c.Request.Context(), req)
+		return err
 	})
 	if err != nil {
 		h.handlerResponse(c, "storage.store.getlist", http.StatusInternalServerError, err.Error())
@@ -138,7 +151,7 @@ func (h *Handler) UpdateStore(c *gin.Context) {
 
 	updateStore.Store_id = id
 
-	rowsAffected, err := h.storages.Store().Update(context.Background(), &updateStore)
+	rowsAffected, err := h.storages.Store().Update(c.Request.Context(), &updateStore)
 	if err != nil {
 		h.handlerResponse(c, "storage.store.update", http.StatusInternalServerError, err.Error())
 		return
@@ -149,7 +162,7 @@ func (h *Handler) UpdateStore(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: id})
+	resp, err := h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.store.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -185,7 +198,7 @@ func (h *Handler) UpdatePatchStore(c *gin.Context) {
 
 	object.ID = id
 
-	rowsAffected, err := h.storages.Store().Patch(context.Background(), &object)
+	rowsAffected, err := h.storages.Store().Patch(c.Request.Context(), &object)
 	if err != nil {
 		h.handlerResponse(c, "storage.store.patchupdate", http.StatusInternalServerError, err.Error())
 		return
@@ -196,7 +209,7 @@ func (h *Handler) UpdatePatchStore(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.Store().GetByID(context.Background(), &models.StorePrimaryKey{Store_id: object.ID})
+	resp, err := h.storages.Store().GetByID(c.Request.Context(), &models.StorePrimaryKey{Store_id: object.ID})
 	if err != nil {
 		h.handlerResponse(c, "storage.store.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -221,7 +234,7 @@ func (h *Handler) DeleteStore(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	_, err := h.storages.Store().Delete(context.Background(), &models.StorePrimaryKey{Store_id: id})
+	_, err := h.storages.Store().Delete(c.Request.Context(), &models.StorePrimaryKey{Store_id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.store.delete", http.StatusInternalServerError, err.Error())
 		return
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"app/api/models"
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,13 +29,13 @@ func (h *Handler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storages.User().Create(context.Background(), &createUser)
+	id, err := h.storages.User().Create(c.Request.Context(), &createUser)
 	if err != nil {
 		h.handlerResponse(c, "storage.user.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	resp, err := h.storages.User().GetByID(context.Background(), &models.UserPrimaryKey{Id: id})
+	resp, err := h.storages.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.user.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -60,7 +59,7 @@ func (h *Handler) CreateUser(c *gin.Context) {
 func (h *Handler) GetByIdUser(c *gin.Context) {
 
 	id := c.Param("id")
-	resp, err := h.storages.User().GetByID(context.Background(), &models.UserPrimaryKey{Id: id})
+	resp, err := h.storages.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.user.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -97,7 +96,7 @@ func (h *Handler) GetListUser(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.User().GetList(context.Background(), &models.GetListUserRequest{
+	resp, err := h.storages.User().GetList(c.Request.Context(), &models.GetListUserRequest{
 		Offset: offset,
 		Limit:  limit,
 		Search: c.Query("search"),
@@ -137,7 +136,7 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 
 	updateUser.Id = id
 
-	rowsAffected, err := h.storages.User().Update(context.Background(), &updateUser)
+	rowsAffected, err := h.storages.User().Update(c.Request.Context(), &updateUser)
 	if err != nil {
 		h.handlerResponse(c, "storage.user.update", http.StatusInternalServerError, err.Error())
 		return
@@ -148,7 +147,7 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.storages.User().GetByID(context.Background(), &models.UserPrimaryKey{Id: id})
+	resp, err := h.storages.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.user.getByID", http.StatusInternalServerError, err.Error())
 		return
@@ -173,7 +172,7 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 
 	id := c.Param("id")
 
-	_, err := h.storages.User().Delete(context.Background(), &models.UserPrimaryKey{Id: id})
+	_, err := h.storages.User().Delete(c.Request.Context(), &models.UserPrimaryKey{Id: id})
 	if err != nil {
 		h.handlerResponse(c, "storage.user.delete", http.StatusInternalServerError, err.Error())
 		return
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"app/api/models"
+	"app/pkg/webhook"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security ApiKeyAuth
+// Create Webhook godoc
+// @ID create_webhook
+// @Router /webhook [POST]
+// @Summary Create Webhook
+// @Description Subscribe a URL to events: order.created, order.status_changed and stock.changed. Every delivery is signed with the secret in the X-Webhook-Signature header, "t=<unix>,v1=<hex HMAC-SHA256 of '<unix>.<body>'>". The URL must reach a public address. Admin role only
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param webhook body models.CreateWebhookSubscription true "CreateWebhookRequest"
+// @Success 201 {object} Response{data=models.WebhookSubscription} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) CreateWebhook(c *gin.Context) {
+
+	var createWebhook models.CreateWebhookSubscription
+
+	err := c.ShouldBindJSON(&createWebhook)
+	if err != nil {
+		h.handlerResponse(c, "create webhook", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(createWebhook.Secret) == 0 {
+		h.handlerResponse(c, "create webhook", http.StatusBadRequest, "secret is required")
+		return
+	}
+
+	err = h.checkWebhook(c.Request.Context(), createWebhook.Url, createWebhook.Event_types)
+	if err != nil {
+		h.handlerResponse(c, "create webhook", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.storages.Webhook().Create(c.Request.Context(), &createWebhook)
+	if err != nil {
+		h.handlerResponse(c, "storage.webhook.create", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ID, _ := strconv.Atoi(id)
+	resp, err := h.storages.Webhook().GetByID(c.Request.Context(), &models.WebhookSubscriptionPrimaryKey{Subscription_id: ID})
+	if err != nil {
+		h.handlerResponse(c, "storage.webhook.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "create webhook", http.StatusCreated, resp)
+}
+
+// @Security ApiKeyAuth
+// Get By ID Webhook godoc
+// @ID get_by_id_webhook
+// @Router /webhook/{id} [GET]
+// @Summary Get By ID Webhook
+// @Description Get By ID Webhook. Admin role only
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.WebhookSubscription} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetByIdWebhook(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "get webhook by id", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	resp, err := h.storages.Webhook().GetByID(c.Request.Context(), &models.WebhookSubscriptionPrimaryKey{Subscription_id: id})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.webhook.getByID", http.StatusNotFound, "webhook not found")
+			return
+		}
+		h.handlerResponse(c, "storage.webhook.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get webhook by id", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// Get List Webhook godoc
+// @ID get_list_webhook
+// @Router /webhook [GET]
+// @Summary Get List Webhook
+// @Description Get List Webhook. Admin role only
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Success 200 {object} Response{data=models.GetListWebhookSubscriptionResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetListWebhook(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list webhook", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list webhook", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	resp, err := h.storages.Webhook().GetList(c.Request.Context(), &models.GetListWebhookSubscriptionRequest{
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.webhook.getlist", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get list webhook response", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// Update Webhook godoc
+// @ID update_webhook
+// @Router /webhook/{id} [PUT]
+// @Summary Update Webhook
+// @Description Update Webhook, an empty secret keeps the current one. Admin role only
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param webhook body models.UpdateWebhookSubscription true "UpdateWebhookRequest"
+// @Success 202 {object} Response{data=models.WebhookSubscription} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+
+	var updateWebhook models.UpdateWebhookSubscription
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "update webhook", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	err = c.ShouldBindJSON(&updateWebhook)
+	if err != nil {
+		h.handlerResponse(c, "update webhook", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.checkWebhook(c.Request.Context(), updateWebhook.Url, updateWebhook.Event_types)
+	if err != nil {
+		h.handlerResponse(c, "update webhook", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updateWebhook.Subscription_id = id
+
+	rowsAffected, err := h.storages.Webhook().Update(c.Request.Context(), &updateWebhook)
+	if err != nil {
+		h.handlerResponse(c, "storage.webhook.update", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if rowsAffected <= 0 {
+		h.handlerResponse(c, "storage.webhook.update", http.StatusNotFound, "webhook not found")
+		return
+	}
+
+	resp, err := h.storages.Webhook().GetByID(c.Request.Context(), &models.WebhookSubscriptionPrimaryKey{Subscription_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.webhook.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "update webhook", http.StatusAccepted, resp)
+}
+
+// @Security ApiKeyAuth
+// Delete Webhook godoc
+// @ID delete_webhook
+// @Router /webhook/{id} [DELETE]
+// @Summary Delete Webhook
+// @Description Delete Webhook with its delivery log. Admin role only
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 202 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "delete webhook", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	_, err = h.storages.Webhook().Delete(c.Request.Context(), &models.WebhookSubscriptionPrimaryKey{Subscription_id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.webhook.delete", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "delete webhook", http.StatusAccepted, id)
+}
+
+// @Security ApiKeyAuth
+// Get Webhook Deliveries godoc
+// @ID get_webhook_deliveries
+// @Router /webhook/{id}/deliveries [GET]
+// @Summary Get Webhook Deliveries
+// @Description Delivery log of a webhook, the latest deliveries first. Admin role only
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Param status query string false "pending, delivered or failed"
+// @Success 200 {object} Response{data=models.GetListWebhookDeliveryResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Forbidden"
+// @Failure 500 {object} Response{data=string} "Server Error"
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.handlerResponse(c, "get webhook deliveries", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get webhook deliveries", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get webhook deliveries", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		h.handlerResponse(c, "get webhook deliveries", http.StatusBadRequest, "invalid status")
+		return
+	}
+
+	resp, err := h.storages.Webhook().GetListDelivery(c.Request.Context(), &models.GetListWebhookDeliveryRequest{
+		Offset:          offset,
+		Limit:           limit,
+		Subscription_id: id,
+		Status:          status,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.webhook.getListDelivery", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get webhook deliveries", http.StatusOK, resp)
+}
+
+// checkWebhook validates the URL and the event types of a subscription.
+func (h *Handler) checkWebhook(ctx context.Context, rawURL string, eventTypes []string) error {
+
+	err := webhook.CheckURL(ctx, rawURL, h.cfg.WebhookAllowPrivateNetworks)
+	if err != nil {
+		return err
+	}
+
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("event_types is required")
+	}
+
+	for _, eventType := range eventTypes {
+		if !models.WebhookEventTypes[eventType] {
+			return fmt.Errorf("unknown event type %q", eventType)
+		}
+	}
+
+	return nil
+}
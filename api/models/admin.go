@@ -0,0 +1,68 @@
+package models
+
+// Stock recompute sources
+const (
+	// StockRecomputeLedger sets every stock to the sum of its ledger.
+	StockRecomputeLedger = "ledger"
+	// StockRecomputeOrders takes off the stock the order items that never reached the ledger,
+	// like the ones added while the stock row of the order's store was missing.
+	StockRecomputeOrders = "orders"
+)
+
+type StockRecomputeRequest struct {
+	Source     string `json:"source"`
+	Store_id   int    `json:"store_id"`
+	Product_id int    `json:"product_id"`
+	// Apply writes the changes, else they are only returned
+	Apply bool   `json:"apply"`
+	Actor string `json:"-"`
+}
+
+type StockRecompute struct {
+	Store_id     int `json:"store_id"`
+	Product_id   int `json:"product_id"`
+	Quantity     int `json:"quantity"`
+	New_quantity int `json:"new_quantity"`
+}
+
+type StockRecomputeResponse struct {
+	Source  string            `json:"source"`
+	Applied bool              `json:"applied"`
+	Count   int               `json:"count"`
+	Changes []*StockRecompute `json:"changes"`
+}
+
+// Integrity checks
+const (
+	IntegrityNegativeStock   = "negative_stock"
+	IntegrityMissingStockRow = "missing_stock_row"
+	IntegrityOrderStaffStore = "order_staff_store"
+	IntegrityManagerCycle    = "staff_manager_cycle"
+	IntegrityOrphanMovement  = "orphaned_stock_movement"
+	IntegrityLedgerMismatch  = "stock_ledger_mismatch"
+)
+
+type IntegrityCheckRequest struct {
+	// Fix_sql adds the SQL fixing the issues that have a mechanical fix
+	Fix_sql bool `json:"fix_sql"`
+}
+
+type IntegrityIssue struct {
+	Check      string `json:"check"`
+	Message    string `json:"message"`
+	Store_id   int    `json:"store_id,omitempty"`
+	Product_id int    `json:"product_id,omitempty"`
+	Order_id   int    `json:"order_id,omitempty"`
+	Staff_id   int    `json:"staff_id,omitempty"`
+	Fix        string `json:"fix,omitempty"`
+}
+
+type IntegrityReport struct {
+	Consistent bool `json:"consistent"`
+	Count      int  `json:"count"`
+	// Checks is the number of issues of every check
+	Checks map[string]int    `json:"checks"`
+	Issues []*IntegrityIssue `json:"issues"`
+	// Fix_sql runs the fixes of all the issues in one transaction
+	Fix_sql string `json:"fix_sql,omitempty"`
+}
@@ -0,0 +1,86 @@
+package models
+
+// API key scopes, <resource>:read allows the GET routes of the resource and <resource>:write
+// every route of it
+const (
+	ScopeCatalogRead    = "catalog:read"
+	ScopeCatalogWrite   = "catalog:write"
+	ScopeCustomersRead  = "customers:read"
+	ScopeCustomersWrite = "customers:write"
+	ScopeStoresRead     = "stores:read"
+	ScopeStoresWrite    = "stores:write"
+	ScopeOrdersRead     = "orders:read"
+	ScopeOrdersWrite    = "orders:write"
+	ScopeStockRead      = "stock:read"
+	ScopeStockWrite     = "stock:write"
+	ScopeReportsRead    = "reports:read"
+)
+
+var APIKeyScopes = map[string]bool{
+	ScopeCatalogRead:    true,
+	ScopeCatalogWrite:   true,
+	ScopeCustomersRead:  true,
+	ScopeCustomersWrite: true,
+	ScopeStoresRead:     true,
+	ScopeStoresWrite:    true,
+	ScopeOrdersRead:     true,
+	ScopeOrdersWrite:    true,
+	ScopeStockRead:      true,
+	ScopeStockWrite:     true,
+	ScopeReportsRead:    true,
+}
+
+// APIKey never returns its hash, a zero Store_id lets the key reach every store and an empty
+// Expires_at never expires.
+type APIKey struct {
+	Api_key_id   int      `json:"api_key_id"`
+	Name         string   `json:"name"`
+	Prefix       string   `json:"prefix"`
+	Key_hash     string   `json:"-"`
+	Scopes       []string `json:"scopes"`
+	Store_id     int      `json:"store_id"`
+	Expires_at   string   `json:"expires_at"`
+	Last_used_at string   `json:"last_used_at"`
+	Revoked_at   string   `json:"revoked_at"`
+	Created_by   string   `json:"created_by"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+type APIKeyPrimaryKey struct {
+	Api_key_id int `json:"api_key_id"`
+}
+
+// CreateAPIKey takes Expires_at as RFC 3339, the handler sets the generated Prefix and Key_hash.
+type CreateAPIKey struct {
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	Store_id   int      `json:"store_id"`
+	Expires_at string   `json:"expires_at"`
+	Prefix     string   `json:"-"`
+	Key_hash   string   `json:"-"`
+	Created_by string   `json:"-"`
+}
+
+// CreateAPIKeyResponse is the only response holding the key, it can not be read again.
+type CreateAPIKeyResponse struct {
+	Key     string  `json:"key"`
+	Api_key *APIKey `json:"api_key"`
+}
+
+type UpdateAPIKey struct {
+	Api_key_id int      `json:"api_key_id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	Store_id   int      `json:"store_id"`
+	Expires_at string   `json:"expires_at"`
+}
+
+type GetListAPIKeyRequest struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+type GetListAPIKeyResponse struct {
+	Count    int       `json:"count"`
+	Api_keys []*APIKey `json:"api_keys"`
+}
@@ -49,3 +49,12 @@ type GetListCustomerResponse struct {
 	Count     int         `json:"count"`
 	Customers []*Customer `json:"customers"`
 }
+
+type CustomerSummary struct {
+	Customer_id          int         `json:"customer_id"`
+	Orders_count         int         `json:"orders_count"`
+	Lifetime_spend       float64     `json:"lifetime_spend"`
+	Last_order_date      interface{} `json:"last_order_date"`
+	Favourite_store_id   int         `json:"favourite_store_id"`
+	Favourite_store_name string      `json:"favourite_store_name"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type HealthResponse struct {
+	Status   string `json:"status"`
+	Postgres string `json:"postgres,omitempty"`
+	Cache    string `json:"cache"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// IdempotencyKey is a request sent with an Idempotency-Key header and the response it got,
+// Status is 0 while the request is still running.
+type IdempotencyKey struct {
+	Key          string `json:"key"`
+	Request_hash string `json:"request_hash"`
+	Status       int    `json:"status"`
+	Content_type string `json:"content_type"`
+	Body         []byte `json:"body"`
+}
@@ -1,18 +1,45 @@
 package models
 
+// Order status
+const (
+	OrderStatusPending    = 1
+	OrderStatusProcessing = 2
+	OrderStatusRejected   = 3
+	OrderStatusCompleted  = 4
+)
+
+var OrderStatuses = map[string]int{
+	"pending":    OrderStatusPending,
+	"processing": OrderStatusProcessing,
+	"rejected":   OrderStatusRejected,
+	"completed":  OrderStatusCompleted,
+}
+
+// Order delivery filters, an order is open while it is pending or processing and has no shipped_date
+const (
+	// OrderDeliveryOverdue is an open order past its required_date
+	OrderDeliveryOverdue = "overdue"
+	// OrderDeliveryAtRisk is an open order whose required_date is within the at risk window
+	OrderDeliveryAtRisk = "at_risk"
+)
+
 type Order struct {
-	Order_id      int          `json:"order_id"`
-	Customer_id   int          `json:"customer_id"`
-	CustomerData  *Customer    `json:"customer_data"`
-	Order_status  int          `json:"order_status"`
-	Order_date    interface{}  `json:"order_date"`
-	Required_date interface{}  `json:"required_date"`
-	Shipped_date  interface{}  `json:"shipped_date"`
-	Store_id      int          `json:"store_id"`
-	StoreData     *Store       `json:"store_data"`
-	Staff_id      int          `json:"staff_id"`
-	StaffData     *Staff       `json:"staff_data"`
-	OrderItems    []*OrderItem `json:"order_items"`
+	Order_id      int            `json:"order_id"`
+	Customer_id   int            `json:"customer_id"`
+	CustomerData  *Customer      `json:"customer_data"`
+	Order_status  int            `json:"order_status"`
+	Order_date    interface{}    `json:"order_date"`
+	Required_date interface{}    `json:"required_date"`
+	Shipped_date  interface{}    `json:"shipped_date"`
+	Store_id      int            `json:"store_id"`
+	StoreData     *Store         `json:"store_data"`
+	Staff_id      int            `json:"staff_id"`
+	StaffData     *Staff         `json:"staff_data"`
+	Total         float64        `json:"total"`
+	Refunded      float64        `json:"refunded"`
+	Net_total     float64        `json:"net_total"`
+	OrderItems    []*OrderItem   `json:"order_items"`
+	Returns       []*OrderReturn `json:"returns"`
 }
 
 type OrderPrimaryKey struct {
@@ -41,12 +68,29 @@ type UpdateOrder struct {
 }
 
 type GetListOrderRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset      int    `json:"offset"`
+	Limit       int    `json:"limit"`
+	Search      string `json:"search"`
+	Customer_id int    `json:"customer_id"`
+	Statuses    []int  `json:"statuses"`
+	Store_id    int    `json:"store_id"`
+	Delivery    string `json:"delivery"`
+	// At_risk_days is the window in days before required_date used by the at_risk delivery filter
+	At_risk_days int `json:"at_risk_days"`
 }
 
 type GetListOrderResponse struct {
 	Count  int      `json:"count"`
 	Orders []*Order `json:"orders"`
 }
+
+type OverdueOrder struct {
+	Order_id      int         `json:"order_id"`
+	Customer_id   int         `json:"customer_id"`
+	Store_id      int         `json:"store_id"`
+	Staff_id      int         `json:"staff_id"`
+	Order_status  int         `json:"order_status"`
+	Order_date    interface{} `json:"order_date"`
+	Required_date interface{} `json:"required_date"`
+	Days_overdue  int         `json:"days_overdue"`
+}
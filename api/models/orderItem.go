@@ -7,6 +7,7 @@ type OrderItem struct {
 	Quantity   int     `json:"quantity"`
 	List_price float64 `json:"list_price"`
 	Discount   float64 `json:"discount"`
+	Actor      string  `json:"-"`
 }
 
 type OrderItemPrimaryKey struct {
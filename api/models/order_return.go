@@ -0,0 +1,37 @@
+package models
+
+type OrderReturn struct {
+	Return_id     int     `json:"return_id"`
+	Order_id      int     `json:"order_id"`
+	Item_id       int     `json:"item_id"`
+	Product_id    int     `json:"product_id"`
+	Quantity      int     `json:"quantity"`
+	Reason        string  `json:"reason"`
+	Refund_amount float64 `json:"refund_amount"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+type OrderReturnPrimaryKey struct {
+	Return_id int `json:"return_id"`
+}
+
+type CreateOrderReturn struct {
+	Order_id int    `json:"order_id"`
+	Item_id  int    `json:"item_id"`
+	Quantity int    `json:"quantity"`
+	Reason   string `json:"reason"`
+	// Refund_amount defaults to the price paid for the returned quantity
+	Refund_amount *float64 `json:"refund_amount"`
+	Actor         string   `json:"-"`
+}
+
+type GetListOrderReturnRequest struct {
+	Offset   int `json:"offset"`
+	Limit    int `json:"limit"`
+	Order_id int `json:"order_id"`
+}
+
+type GetListOrderReturnResponse struct {
+	Count        int            `json:"count"`
+	OrderReturns []*OrderReturn `json:"order_returns"`
+}
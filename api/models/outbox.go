@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event written to the outbox in the transaction of its change.
+type OutboxEvent struct {
+	Event_id       int64     `json:"event_id"`
+	Event_type     string    `json:"event_type"`
+	Aggregate_type string    `json:"aggregate_type"`
+	Aggregate_id   string    `json:"aggregate_id"`
+	Payload        []byte    `json:"payload"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,83 @@
+package models
+
+type SalesReportRequest struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Store_id int    `json:"store_id"`
+}
+
+type StoreSales struct {
+	Store_id     int     `json:"store_id"`
+	Store_name   string  `json:"store_name"`
+	Orders_count int     `json:"orders_count"`
+	Gross_sales  float64 `json:"gross_sales"`
+	Refunds      float64 `json:"refunds"`
+	Net_sales    float64 `json:"net_sales"`
+}
+
+type SalesReportResponse struct {
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Gross_sales float64       `json:"gross_sales"`
+	Refunds     float64       `json:"refunds"`
+	Net_sales   float64       `json:"net_sales"`
+	Stores      []*StoreSales `json:"stores"`
+}
+
+type StaffPerformanceRequest struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Staff_id int    `json:"staff_id"`
+	Store_id int    `json:"store_id"`
+}
+
+type StaffPerformance struct {
+	Staff_id     int     `json:"staff_id"`
+	First_name   string  `json:"first_name"`
+	Last_name    string  `json:"last_name"`
+	Store_id     int     `json:"store_id"`
+	Orders_count int     `json:"orders_count"`
+	Revenue      float64 `json:"revenue"`
+	// Shipped_count is the number of orders with a shipped_date, the fulfilment time is averaged over them
+	Shipped_count       int     `json:"shipped_count"`
+	Avg_fulfilment_days float64 `json:"avg_fulfilment_days"`
+	Late_shipments      int     `json:"late_shipments"`
+	Rejected_count      int     `json:"rejected_count"`
+	Rejection_rate      float64 `json:"rejection_rate"`
+}
+
+type StaffPerformanceResponse struct {
+	From                string              `json:"from"`
+	To                  string              `json:"to"`
+	Store_id            int                 `json:"store_id,omitempty"`
+	Orders_count        int                 `json:"orders_count"`
+	Revenue             float64             `json:"revenue"`
+	Shipped_count       int                 `json:"shipped_count"`
+	Avg_fulfilment_days float64             `json:"avg_fulfilment_days"`
+	Late_shipments      int                 `json:"late_shipments"`
+	Rejected_count      int                 `json:"rejected_count"`
+	Rejection_rate      float64             `json:"rejection_rate"`
+	Staffs              []*StaffPerformance `json:"staffs"`
+}
+
+type OverdueReportRequest struct {
+	Store_id     int `json:"store_id"`
+	At_risk_days int `json:"at_risk_days"`
+}
+
+type StoreOverdue struct {
+	Store_id      int         `json:"store_id"`
+	Store_name    string      `json:"store_name"`
+	Overdue_count int         `json:"overdue_count"`
+	At_risk_count int         `json:"at_risk_count"`
+	Overdue_total float64     `json:"overdue_total"`
+	Oldest_due    interface{} `json:"oldest_due"`
+}
+
+type OverdueReportResponse struct {
+	At_risk_days  int             `json:"at_risk_days"`
+	Overdue_count int             `json:"overdue_count"`
+	At_risk_count int             `json:"at_risk_count"`
+	Overdue_total float64         `json:"overdue_total"`
+	Stores        []*StoreOverdue `json:"stores"`
+}
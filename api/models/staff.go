@@ -38,12 +38,42 @@ type UpdateStaff struct {
 }
 
 type GetListStaffRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Search   string `json:"search"`
+	Store_id int    `json:"store_id"`
 }
 
 type GetListStaffResponse struct {
 	Count  int      `json:"count"`
 	Staffs []*Staff `json:"staffs"`
 }
+
+type StaffHierarchy struct {
+	Staff_id   int    `json:"staff_id"`
+	First_name string `json:"first_name"`
+	Last_name  string `json:"last_name"`
+	Email      string `json:"email"`
+	Active     int    `json:"active"`
+	Store_id   int    `json:"store_id"`
+	Manager_id int    `json:"manager_id"`
+	// Level is the distance from the requested staff member, 1 for the direct manager or report
+	Level int `json:"level"`
+}
+
+type GetStaffHierarchyRequest struct {
+	Staff_id int  `json:"staff_id"`
+	Direct   bool `json:"direct"`
+}
+
+type GetStaffHierarchyResponse struct {
+	Count  int               `json:"count"`
+	Staffs []*StaffHierarchy `json:"staffs"`
+}
+
+type ReassignStaff struct {
+	Staff_id int `json:"-"`
+	Store_id int `json:"store_id"`
+	// Manager_id is kept when it is not given, 0 makes the staff member a top level manager
+	Manager_id *int `json:"manager_id"`
+}
@@ -1,36 +1,76 @@
 package models
 
 type Stock struct {
-	Store_id    int         `json:"store_id"`
-	StoreData   *Store      `json:"store_data"`
-	Product_id  int         `json:"product_id"`
-	ProductData *Product    `json:"product_data"`
-	Quantity    interface{} `json:"quantity"`
+	Store_id    int      `json:"store_id"`
+	StoreData   *Store   `json:"store_data"`
+	Product_id  int      `json:"product_id"`
+	ProductData *Product `json:"product_data"`
+	Quantity    int      `json:"quantity"`
 }
 
 type StockPrimaryKey struct {
-	Store_id int `json:"store_id"`
+	Store_id   int `json:"store_id"`
+	Product_id int `json:"product_id"`
 }
 
 type CreateStock struct {
-	Store_id   int         `json:"store_id"`
-	Product_id int         `json:"product_id"`
-	Quantity   interface{} `json:"quantity"`
+	Store_id   int    `json:"store_id"`
+	Product_id int    `json:"product_id"`
+	Quantity   int    `json:"quantity"`
+	Actor      string `json:"-"`
 }
 
 type UpdateStock struct {
-	Store_id   int         `json:"store_id"`
-	Product_id int         `json:"product_id"`
-	Quantity   interface{} `json:"quantity"`
+	Store_id   int    `json:"store_id"`
+	Product_id int    `json:"product_id"`
+	Quantity   int    `json:"quantity"`
+	Actor      string `json:"-"`
+}
+
+type PatchStock struct {
+	Store_id   int `json:"-"`
+	Product_id int `json:"-"`
+	Fields     map[string]interface{}
+	Actor      string `json:"-"`
+}
+
+type DeleteStock struct {
+	Store_id   int    `json:"store_id"`
+	Product_id int    `json:"product_id"`
+	Actor      string `json:"-"`
+}
+
+type TransferStock struct {
+	From_store_id int    `json:"from_store_id"`
+	To_store_id   int    `json:"to_store_id"`
+	Product_id    int    `json:"product_id"`
+	Quantity      int    `json:"quantity"`
+	Reference_id  string `json:"reference_id"`
+	Actor         string `json:"-"`
 }
 
 type GetListStockRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+	Search     string `json:"search"`
+	Store_id   int    `json:"store_id"`
+	Product_id int    `json:"product_id"`
 }
 
 type GetListStockResponse struct {
 	Count  int      `json:"count"`
 	Stocks []*Stock `json:"stocks"`
 }
+
+type StoreAvailability struct {
+	Store_id   int    `json:"store_id"`
+	Store_name string `json:"store_name"`
+	Quantity   int    `json:"quantity"`
+}
+
+type ProductAvailability struct {
+	Product_id   int                  `json:"product_id"`
+	Product_name string               `json:"product_name"`
+	Quantity     int                  `json:"quantity"`
+	Stores       []*StoreAvailability `json:"stores"`
+}
@@ -0,0 +1,60 @@
+package models
+
+const (
+	StockMovementSale       = "sale"
+	StockMovementReturn     = "return"
+	StockMovementAdjustment = "adjustment"
+	StockMovementTransfer   = "transfer"
+	StockMovementReceiving  = "receiving"
+)
+
+type StockMovement struct {
+	Movement_id  int    `json:"movement_id"`
+	Store_id     int    `json:"store_id"`
+	Product_id   int    `json:"product_id"`
+	Quantity     int    `json:"quantity"`
+	Reason       string `json:"reason"`
+	Reference_id string `json:"reference_id"`
+	Actor        string `json:"actor"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type GetListStockMovementRequest struct {
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+	Store_id   int    `json:"store_id"`
+	Product_id int    `json:"product_id"`
+	Reason     string `json:"reason"`
+}
+
+type GetListStockMovementResponse struct {
+	Count          int              `json:"count"`
+	StockMovements []*StockMovement `json:"stock_movements"`
+}
+
+type StockReconciliation struct {
+	Store_id        int `json:"store_id"`
+	Product_id      int `json:"product_id"`
+	Quantity        int `json:"quantity"`
+	Ledger_quantity int `json:"ledger_quantity"`
+	Difference      int `json:"difference"`
+}
+
+type StockReconciliationRequest struct {
+	Store_id   int `json:"store_id"`
+	Product_id int `json:"product_id"`
+}
+
+type StockReconciliationResponse struct {
+	Consistent bool                   `json:"consistent"`
+	Count      int                    `json:"count"`
+	Mismatches []*StockReconciliation `json:"mismatches"`
+}
+
+func IsValidStockMovementReason(reason string) bool {
+	switch reason {
+	case StockMovementSale, StockMovementReturn, StockMovementAdjustment, StockMovementTransfer, StockMovementReceiving:
+		return true
+	}
+	return false
+}
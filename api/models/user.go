@@ -1,10 +1,18 @@
 package models
 
+// User roles
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
 type User struct {
-	Id        string `json:"id"`
-	Name      string `json:"name"`
-	Login     string `json:"login"`
-	Password  string `json:"password"`
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Login string `json:"login"`
+	// Password is never written to a response or a token
+	Password  string `json:"-"`
+	Role      string `json:"role"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -18,6 +26,8 @@ type CreateUser struct {
 	Name     string `json:"name"`
 	Login    string `json:"login"`
 	Password string `json:"password"`
+	// Role is user unless set by the admin CLI
+	Role string `json:"-"`
 }
 
 type UpdateUser struct {
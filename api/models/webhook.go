@@ -0,0 +1,116 @@
+package models
+
+import "time"
+
+// Webhook event types
+const (
+	WebhookOrderCreated       = "order.created"
+	WebhookOrderStatusChanged = "order.status_changed"
+	WebhookStockChanged       = "stock.changed"
+)
+
+var WebhookEventTypes = map[string]bool{
+	WebhookOrderCreated:       true,
+	WebhookOrderStatusChanged: true,
+	WebhookStockChanged:       true,
+}
+
+// Webhook delivery status
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription never returns its secret, the deliveries are signed with it.
+type WebhookSubscription struct {
+	Subscription_id int      `json:"subscription_id"`
+	Url             string   `json:"url"`
+	Secret          string   `json:"-"`
+	Event_types     []string `json:"event_types"`
+	Active          bool     `json:"active"`
+	CreatedAt       string   `json:"created_at"`
+}
+
+type WebhookSubscriptionPrimaryKey struct {
+	Subscription_id int `json:"subscription_id"`
+}
+
+type CreateWebhookSubscription struct {
+	Url         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	Event_types []string `json:"event_types"`
+}
+
+// UpdateWebhookSubscription keeps the secret when Secret is empty.
+type UpdateWebhookSubscription struct {
+	Subscription_id int      `json:"subscription_id"`
+	Url             string   `json:"url"`
+	Secret          string   `json:"secret"`
+	Event_types     []string `json:"event_types"`
+	Active          bool     `json:"active"`
+}
+
+type GetListWebhookSubscriptionRequest struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+type GetListWebhookSubscriptionResponse struct {
+	Count         int                    `json:"count"`
+	Subscriptions []*WebhookSubscription `json:"subscriptions"`
+}
+
+// WebhookDelivery is an event queued for a subscription and the outcome of its last attempt.
+type WebhookDelivery struct {
+	Delivery_id     int         `json:"delivery_id"`
+	Subscription_id int         `json:"subscription_id"`
+	Event_type      string      `json:"event_type"`
+	Payload         interface{} `json:"payload"`
+	Status          string      `json:"status"`
+	Attempts        int         `json:"attempts"`
+	Next_attempt_at string      `json:"next_attempt_at"`
+	Response_code   int         `json:"response_code"`
+	Last_error      string      `json:"last_error"`
+	Delivered_at    string      `json:"delivered_at"`
+	CreatedAt       string      `json:"created_at"`
+}
+
+type GetListWebhookDeliveryRequest struct {
+	Offset          int    `json:"offset"`
+	Limit           int    `json:"limit"`
+	Subscription_id int    `json:"subscription_id"`
+	Status          string `json:"status"`
+}
+
+type GetListWebhookDeliveryResponse struct {
+	Count      int                `json:"count"`
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+}
+
+// WebhookEvent is an outbox event queued for the subscriptions to its type.
+type WebhookEvent struct {
+	Event_id   int64
+	Event_type string
+	Payload    []byte
+}
+
+// DueWebhookDelivery is a delivery claimed by the webhook job with what it needs to send it.
+type DueWebhookDelivery struct {
+	Delivery_id int
+	Event_type  string
+	Payload     []byte
+	Attempts    int
+	CreatedAt   string
+	Url         string
+	Secret      string
+}
+
+// WebhookDeliveryAttempt is the outcome of sending a delivery, a pending Status retries it after Retry_after.
+type WebhookDeliveryAttempt struct {
+	Delivery_id   int
+	Status        string
+	Response_code int
+	Error         string
+	Retry_after   time.Duration
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"app/api/models"
+	"app/config"
+	"app/migrations"
+	"app/pkg/logger"
+	"app/storage"
+	"app/storage/cachesync"
+	"app/storage/postgresql"
+	"app/storage/redis"
+)
+
+const adminUsage = `usage: admin <command> [flags]
+
+  create-admin -name NAME -login LOGIN [-password PASSWORD]
+  reset-password -login LOGIN [-password PASSWORD]
+                   the password is read from stdin when the flag is not set
+  cache-flush      drop every cached response
+  cache-warm       cache every brand, category, store, product and stock
+  recompute-stock -from ledger|orders [-store ID] [-product ID] [-apply]
+                   print the stocks that drifted from the source, -apply fixes them
+  seed             load the demo data into a database without catalog data
+  check [-fix-sql]
+                   look for drifted data, -fix-sql adds the SQL fixing what has a mechanical fix
+
+Every command prints one JSON object: {"command", "ok", "result"} or {"command", "ok", "error"}.`
+
+// adminActor is the actor of the stock movements written by the admin CLI.
+const adminActor = "admin-cli"
+
+// adminResult is the output of an admin command.
+type adminResult struct {
+	Command string      `json:"command"`
+	Ok      bool        `json:"ok"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// admin holds the connections of the admin commands.
+type admin struct {
+	cfg      *config.Config
+	log      logger.LoggerI
+	storages storage.StorageI
+	cache    storage.CacheStorageI
+}
+
+// adminCommand runs the admin subcommand and returns the exit code.
+func adminCommand(cfg *config.Config, args []string) int {
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, adminUsage)
+		return 2
+	}
+
+	command, flags := args[0], args[1:]
+
+	// the output is the JSON result, the log only reports errors on stderr
+	log := logger.NewLogger("admin", logger.LevelError)
+	defer logger.Cleanup(log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := postgresql.NewConnectPostgresql(cfg, log)
+	if err != nil {
+		return adminOutput(command, nil, err)
+	}
+	defer store.CloseDB()
+
+	cache := redis.NewRedisCacheStorage(*cfg)
+	defer cache.CloseDB()
+
+	a := &admin{
+		cfg:      cfg,
+		log:      log,
+		storages: cachesync.NewStorage(store, cache.Cache(), log),
+		cache:    cache,
+	}
+
+	var result interface{}
+
+	switch command {
+	case "create-admin":
+		result, err = a.createAdmin(ctx, flags)
+	case "reset-password":
+		result, err = a.resetPassword(ctx, flags)
+	case "cache-flush":
+		result, err = a.cacheFlush(ctx)
+	case "cache-warm":
+		result, err = a.cacheWarm(ctx)
+	case "recompute-stock":
+		result, err = a.recomputeStock(ctx, flags)
+	case "seed":
+		result, err = a.seed(ctx)
+	case "check":
+		result, err = a.check(ctx, flags)
+	default:
+		fmt.Fprintln(os.Stderr, adminUsage)
+		return 2
+	}
+
+	return adminOutput(command, result, err)
+}
+
+// adminOutput prints the result of command as JSON and returns the exit code.
+func adminOutput(command string, result interface{}, err error) int {
+
+	output := adminResult{
+		Command: command,
+		Ok:      err == nil,
+		Result:  result,
+	}
+
+	if err != nil {
+		output.Error = err.Error()
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(output)
+
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+func (a *admin) createAdmin(ctx context.Context, args []string) (interface{}, error) {
+
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	name := flags.String("name", "", "name of the admin")
+	login := flags.String("login", "", "login of the admin")
+	password := flags.String("password", "", "password of the admin, read from stdin when empty")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(*name) == 0 || len(*login) == 0 {
+		return nil, errors.New("-name and -login are required")
+	}
+
+	hashedPassword, err := readPassword(*password)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := a.storages.User().Create(ctx, &models.CreateUser{
+		Name:     *name,
+		Login:    *login,
+		Password: hashedPassword,
+		Role:     models.UserRoleAdmin,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.storages.User().GetByID(ctx, &models.UserPrimaryKey{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+
+	return user, nil
+}
+
+func (a *admin) resetPassword(ctx context.Context, args []string) (interface{}, error) {
+
+	flags := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	login := flags.String("login", "", "login of the user")
+	password := flags.String("password", "", "new password, read from stdin when empty")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(*login) == 0 {
+		return nil, errors.New("-login is required")
+	}
+
+	hashedPassword, err := readPassword(*password)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := a.storages.User().SetPassword(ctx, &models.UserPrimaryKey{Login: *login}, hashedPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if rowsAffected <= 0 {
+		return nil, fmt.Errorf("user %q not found", *login)
+	}
+
+	// a locked out user can log in with the new password right away
+	err = a.cache.Limiter().ResetLogin(ctx, *login)
+	if err != nil {
+		a.log.Warn("admin: reset login lockout", logger.Error(err))
+	}
+
+	return map[string]string{"login": *login}, nil
+}
+
+func (a *admin) cacheFlush(ctx context.Context) (interface{}, error) {
+
+	err := a.cache.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cachesync.Flush(ctx, a.cache.Cache())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]string{"flushed": cachesync.Entities}, nil
+}
+
+func (a *admin) cacheWarm(ctx context.Context) (interface{}, error) {
+
+	err := a.cache.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	warmed, err := cachesync.Warm(ctx, a.storages, a.cache.Cache(), a.log, a.cfg.CacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]map[string]int{"warmed": warmed}, nil
+}
+
+func (a *admin) recomputeStock(ctx context.Context, args []string) (interface{}, error) {
+
+	flags := flag.NewFlagSet("recompute-stock", flag.ContinueOnError)
+	source := flags.String("from", "", "ledger or orders")
+	storeId := flags.Int("store", 0, "only the stocks of this store")
+	productId := flags.Int("product", 0, "only the stocks of this product")
+	apply := flags.Bool("apply", false, "fix the stocks, else they are only printed")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if *source != models.StockRecomputeLedger && *source != models.StockRecomputeOrders {
+		return nil, errors.New("-from must be ledger or orders")
+	}
+
+	return a.storages.Admin().RecomputeStock(ctx, &models.StockRecomputeRequest{
+		Source:     *source,
+		Store_id:   *storeId,
+		Product_id: *productId,
+		Apply:      *apply,
+		Actor:      adminActor,
+	})
+}
+
+func (a *admin) seed(ctx context.Context) (interface{}, error) {
+
+	script, err := migrations.Postgres.ReadFile("postgres/02_insert_tables.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.storages.Admin().Seed(ctx, string(script))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]bool{"seeded": true}, nil
+}
+
+func (a *admin) check(ctx context.Context, args []string) (interface{}, error) {
+
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	fixSQL := flags.Bool("fix-sql", false, "add the SQL fixing the issues")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := a.storages.Admin().CheckIntegrity(ctx, &models.IntegrityCheckRequest{Fix_sql: *fixSQL})
+	if err != nil {
+		return nil, err
+	}
+
+	if !report.Consistent {
+		return report, fmt.Errorf("%d integrity issues found", report.Count)
+	}
+
+	return report, nil
+}
+
+// readPassword hashes the password, or the first line of stdin when it is empty.
+func readPassword(password string) (string, error) {
+
+	if len(password) == 0 {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	if len(password) == 0 {
+		return "", errors.New("the password is empty")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 7)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashedPassword), nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"app/config"
+)
+
+const configUsage = `usage: config print [--redacted]
+
+  print  print every setting as YAML with where its value comes from: default, env or
+         the config file, --redacted hides the secrets`
+
+// configCommand runs the config subcommand and returns the exit code.
+func configCommand(cfg *config.Config, args []string) int {
+
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, configUsage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("config print", flag.ContinueOnError)
+	redacted := flags.Bool("redacted", false, "hide the secrets")
+
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return 2
+	}
+
+	err = cfg.Print(os.Stdout, *redacted)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "config:", err)
+		return 1
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
@@ -1,19 +1,57 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"app/api"
 	"app/config"
+	"app/pkg/events"
+	"app/pkg/jwtkey"
 	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/pkg/notify"
+	"app/pkg/tracing"
+	"app/pkg/webhook"
+	"app/storage/cachesync"
 	"app/storage/postgresql"
 	"app/storage/redis"
+	"app/worker"
 )
 
 func main() {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(configCommand(&cfg, os.Args[2:]))
+	}
+
+	// migrate only needs the database, the settings of the server do not stop it
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = cfg.ValidatePostgres()
+	} else {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		os.Exit(adminCommand(&cfg, os.Args[2:]))
+	}
 
 	var loggerLevel = new(string)
 
@@ -39,30 +77,154 @@ func main() {
 		}
 	}()
 
-	store, err := postgresql.NewConnectPostgresql(&cfg)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		code := migrateCommand(&cfg, log, os.Args[2:])
+		logger.Cleanup(log)
+		os.Exit(code)
+	}
+
+	if cfg.AutoMigrate {
+		err := migrateUp(&cfg, log)
+		if err != nil {
+			log.Panic("Error apply migrations: ", logger.Error(err))
+			return
+		}
+	}
+
+	shutdownTracing, err := tracing.Init(&cfg)
+	if err != nil {
+		log.Panic("Error init tracing: ", logger.Error(err))
+		return
+	}
+
+	store, err := postgresql.NewConnectPostgresql(&cfg, log)
 	if err != nil {
 		log.Panic("Error connect to postgresql: ", logger.Error(err))
 		return
 	}
-	defer store.CloseDB()
 
-	cache, err := redis.NewRedisCacheStorage(cfg)
+	prometheus.MustRegister(metrics.NewPoolCollector(store.Stats))
+
+	cache := redis.NewRedisCacheStorage(cfg)
+
+	err = cache.Ping()
 	if err != nil {
-		log.Panic("Error connect to redis: ", logger.Error(err))
+		log.Warn("Redis is unavailable, reads are served from postgresql: ", logger.Error(err))
+	}
+
+	storages := cachesync.NewStorage(store, cache.Cache(), log)
+
+	eventPublisher, err := events.NewPublisher(&cfg, log)
+	if err != nil {
+		log.Panic("Error create event publisher: ", logger.Error(err))
 		return
 	}
-	defer cache.CloseDB()
 
-	r := gin.New()
+	// the webhook deliveries are queued from the outbox events too
+	publisher := events.NewMultiPublisher(worker.NewWebhookPublisher(storages), eventPublisher)
 
-	r.Use(gin.Recovery(), gin.Logger())
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	api.NewApi(r, &cfg, store, cache, log)
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
 
-	fmt.Println("Listening Server", cfg.ServerHost+cfg.ServerPort)
-	err = r.Run(cfg.ServerHost + cfg.ServerPort)
+	var workers worker.Group
+	workers.Go(workersCtx, worker.NewOverdueJob(storages, notify.NewLogSink(log), log, cfg.OverdueCheckInterval).Run)
+	workers.Go(workersCtx, worker.NewOutboxRelay(storages, publisher, log, cfg.OutboxInterval, cfg.OutboxBatchSize, cfg.OutboxRetention).Run)
+	workers.Go(workersCtx, worker.NewIdempotencyPurgeJob(storages, log, cfg.IdempotencyKeyTTL).Run)
+	workers.Go(workersCtx, worker.NewWebhookJob(
+		storages,
+		webhook.NewSender(cfg.WebhookTimeout, cfg.WebhookAllowPrivateNetworks),
+		log,
+		cfg.WebhookInterval,
+		cfg.WebhookBatchSize,
+		cfg.WebhookTimeout*time.Duration(cfg.WebhookBatchSize)+time.Minute,
+		worker.WebhookBackoff{
+			Base:        cfg.WebhookBackoffBase,
+			Max:         cfg.WebhookBackoffMax,
+			MaxAttempts: cfg.WebhookMaxAttempts,
+		},
+	).Run)
+
+	keys, err := jwtkey.Load(&cfg)
 	if err != nil {
-		log.Panic("Error listening server:", logger.Error(err))
+		log.Panic("Error load token keys: ", logger.Error(err))
 		return
 	}
+
+	r := gin.New()
+
+	err = r.SetTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Panic("Error set trusted proxies: ", logger.Error(err))
+		return
+	}
+
+	r.Use(gin.Recovery())
+
+	api.NewApi(r, &cfg, storages, cache, keys, log)
+
+	server := &http.Server{
+		Addr:              cfg.ServerHost + cfg.ServerPort,
+		Handler:           r,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info("Listening Server", logger.String("addr", server.Addr))
+		serverErr <- server.ListenAndServe()
+	}()
+
+	// the server only returns before Shutdown when it failed, like on a port already in use
+	var listenErr error
+
+	select {
+	case <-ctx.Done():
+		log.Info("Shutting down")
+	case listenErr = <-serverErr:
+		log.Error("Error listening server:", logger.Error(listenErr))
+	}
+
+	// the server stops first so no request is left without the database,
+	// then the jobs and at last the connections they all use
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Error("Error shutting down server:", logger.Error(err))
+	}
+
+	stopWorkers()
+	err = workers.Wait(shutdownCtx)
+	if err != nil {
+		log.Error("Error stopping background jobs:", logger.Error(err))
+	}
+
+	err = publisher.Close()
+	if err != nil {
+		log.Error("Error closing event publisher:", logger.Error(err))
+	}
+
+	store.CloseDB()
+	cache.CloseDB()
+
+	// the spans of the last requests and queries are flushed once nothing records anymore
+	err = shutdownTracing(shutdownCtx)
+	if err != nil {
+		log.Error("Error flushing traces:", logger.Error(err))
+	}
+
+	log.Info("Server stopped")
+
+	// the supervisor restarts a server that did not listen
+	if listenErr != nil {
+		logger.Cleanup(log)
+		os.Exit(1)
+	}
 }
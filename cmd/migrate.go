@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"app/config"
+	"app/pkg/logger"
+	"app/storage/postgresql"
+)
+
+const migrateUsage = `usage: migrate <command>
+
+  up               apply every pending migration
+  down [N]         revert the last N migrations, 1 by default, "all" reverts every one
+  status           print the applied and the latest version
+  force <version>  set the version without migrating, after fixing a dirty migration by hand`
+
+// migrateCommand runs the migrate subcommand and returns the exit code.
+func migrateCommand(cfg *config.Config, log logger.LoggerI, args []string) int {
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, err := postgresql.NewMigrator(cfg, log)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if args[1] == "all" {
+				steps = 0
+			} else {
+				steps, err = strconv.Atoi(args[1])
+				if err != nil || steps <= 0 {
+					fmt.Fprintln(os.Stderr, "migrate: down takes a positive number of steps or all")
+					return 2
+				}
+			}
+		}
+		err = migrator.Down(steps)
+
+	case "force":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			fmt.Fprintln(os.Stderr, "migrate: invalid version", args[1])
+			return 2
+		}
+		err = migrator.Force(version)
+
+	case "status":
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+
+	fmt.Printf("version: %d\ndirty: %t\nlatest: %d\n", status.Version, status.Dirty, status.Latest)
+
+	if status.Dirty {
+		return 1
+	}
+
+	return 0
+}
+
+// migrateUp applies the pending migrations before the service starts.
+func migrateUp(cfg *config.Config, log logger.LoggerI) error {
+
+	migrator, err := postgresql.NewMigrator(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Up()
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -18,19 +19,43 @@ const (
 	ReleaseMode = "release"
 
 	TimeExpiredAt = time.Hour * 24
+
+	// the defaults of the secrets only fit a local setup, Validate refuses them in release mode
+	defaultSecretKey        = "topolmaysan"
+	defaultPostgresPassword = "db_password"
+	defaultRedisPassword    = "redis_password"
 )
 
+// RateLimit allows Requests per Window to every client, a zero Requests disables it.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
 type Config struct {
 	Environment string // debug, test, release
 
 	ServerHost string
 	ServerPort string
 
+	HTTPReadTimeout       time.Duration
+	HTTPReadHeaderTimeout time.Duration
+	HTTPWriteTimeout      time.Duration
+	HTTPIdleTimeout       time.Duration
+	// RequestTimeout bounds the storage calls of every request, 0 disables it
+	RequestTimeout time.Duration
+	// ShutdownTimeout bounds the draining of in flight requests and background jobs on shutdown
+	ShutdownTimeout time.Duration
+
 	PostgresHost     string
 	PostgresUser     string
 	PostgresDatabase string
 	PostgresPassword string
 	PostgresPort     string
+	// PostgresLogLevel is the pgx log level: trace, debug, info, warn, error or none
+	PostgresLogLevel string
+	// AutoMigrate applies the pending migrations on start
+	AutoMigrate bool
 
 	DefaultOffset int
 	DefaultLimit  int
@@ -38,49 +63,210 @@ type Config struct {
 	RedisAddr     string
 	RedisPassword string
 	RedisDB       int
+	RedisTimeout  time.Duration
+	// the cache is skipped for RedisBreakerCooldown after RedisBreakerThreshold consecutive failures
+	RedisBreakerThreshold int
+	RedisBreakerCooldown  time.Duration
 
+	// CacheTTL is the lifetime of cached GetByID responses, CacheListTTL of cached GetList responses
+	CacheTTL     time.Duration
+	CacheListTTL time.Duration
+
+	// SecretKey is the HS256 key with the kid "secret", empty leaves it out of the key set
 	SecretKey string
+	// JWTSigningKey is the kid of the key signing the new tokens. JWTKeys lists the other keys
+	// comma separated as kid:algorithm:file, the algorithm is HS256, RS256 or EdDSA
+	JWTSigningKey string
+	JWTKeys       string
+
+	// RateLimitAuth limits /login and /register per IP, RateLimitAPI limits every route
+	// per user for the authenticated requests and per IP for the rest
+	RateLimitAuth RateLimit
+	RateLimitAPI  RateLimit
+	// TrustedProxies are the addresses and CIDRs of the proxies whose X-Forwarded-For gives the
+	// client IP, none are trusted by default so the clients can not pick their IP
+	TrustedProxies []string
+
+	// LoginLockoutThreshold failed logins of a login name lock it for LoginLockoutBase,
+	// doubled by every further failure up to LoginLockoutMax. The failures are forgotten
+	// after LoginFailuresTTL without one, 0 threshold disables the lockout.
+	LoginLockoutThreshold int
+	LoginLockoutBase      time.Duration
+	LoginLockoutMax       time.Duration
+	LoginFailuresTTL      time.Duration
 
-	PostgresMaxConnections int32
+	// IdempotencyKeyTTL is how long the response of a request with an Idempotency-Key is replayed
+	IdempotencyKeyTTL time.Duration
+
+	// the pgxpool settings, a zero lifetime, idle time or health check period keeps the pgxpool default
+	// and a zero PostgresStatementTimeout lets the statements run without a limit
+	PostgresMaxConnections    int32
+	PostgresMinConnections    int32
+	PostgresMaxConnLifetime   time.Duration
+	PostgresMaxConnIdleTime   time.Duration
+	PostgresHealthCheckPeriod time.Duration
+	PostgresConnectTimeout    time.Duration
+	PostgresStatementTimeout  time.Duration
+
+	// OverdueCheckInterval is how often the overdue orders job runs, 0 disables the job
+	OverdueCheckInterval time.Duration
+	// OrderAtRiskDays is the window in days before required_date in which an open order is at risk
+	OrderAtRiskDays int
+
+	// WebhookInterval is how often the webhook job sends the due deliveries, 0 disables the job.
+	// Every run claims up to WebhookBatchSize deliveries, each one is sent within WebhookTimeout.
+	WebhookInterval  time.Duration
+	WebhookBatchSize int
+	WebhookTimeout   time.Duration
+	// a failed delivery is retried after WebhookBackoffBase, doubled by every further failure
+	// up to WebhookBackoffMax, and is failed after WebhookMaxAttempts attempts
+	WebhookBackoffBase time.Duration
+	WebhookBackoffMax  time.Duration
+	WebhookMaxAttempts int
+	// WebhookAllowPrivateNetworks lets the subscriptions reach the private, loopback and
+	// link-local addresses, for a local setup only
+	WebhookAllowPrivateNetworks bool
+
+	// OutboxInterval is how often the relay publishes the outbox events, 0 disables the relay
+	// and the webhooks it queues. Every run publishes up to OutboxBatchSize events through
+	// EventPublisher: inprocess, log or nats.
+	// The published events are deleted after OutboxRetention, 0 keeps them
+	OutboxInterval    time.Duration
+	OutboxBatchSize   int
+	OutboxRetention   time.Duration
+	EventPublisher    string
+	NATSURL           string
+	NATSSubjectPrefix string
+
+	// TracingExporter is where the spans go: file, stdout, otlp or none, the default. The file
+	// exporter grows its file without limit, it is meant for a local setup
+	TracingExporter string
+	// TracingFile is the file the spans are appended to by the file exporter
+	TracingFile string
+	// TracingOTLPEndpoint is the host:port of the OTLP/HTTP collector used by the otlp exporter
+	TracingOTLPEndpoint string
+	// TracingSampleRatio is the share of the new traces that are recorded, from 0 to 1
+	TracingSampleRatio float64
+
+	// settings are the loaded settings in the order of Load, with their sources
+	settings []Setting
+	// unknown are the keys of the config files no setting reads
+	unknown []string
 }
 
-func Load() Config {
+// Load reads every setting from the environment, else from the config files listed in
+// CONFIG_FILE, else it takes the default. The files are comma separated and the later ones
+// override the earlier ones.
+func Load() (Config, error) {
 
 	if err := godotenv.Load("./.env"); err != nil {
-		fmt.Println("No .env file found")
+		fmt.Fprintln(os.Stderr, "No .env file found")
+	}
+
+	l, err := newLoader(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		return Config{}, err
 	}
 
 	cfg := Config{}
 
-	cfg.ServerHost = cast.ToString(getOrReturnDefaultValue("SERVICE_HOST", "localhost"))
-	cfg.ServerPort = cast.ToString(getOrReturnDefaultValue("HTTP_PORT", ":8081"))
+	cfg.Environment = cast.ToString(l.get("ENVIRONMENT", ReleaseMode))
+
+	cfg.ServerHost = cast.ToString(l.get("SERVICE_HOST", "localhost"))
+	cfg.ServerPort = cast.ToString(l.get("HTTP_PORT", ":8081"))
+
+	cfg.HTTPReadTimeout = cast.ToDuration(l.get("HTTP_READ_TIMEOUT", "15s"))
+	cfg.HTTPReadHeaderTimeout = cast.ToDuration(l.get("HTTP_READ_HEADER_TIMEOUT", "5s"))
+	cfg.HTTPWriteTimeout = cast.ToDuration(l.get("HTTP_WRITE_TIMEOUT", "30s"))
+	cfg.HTTPIdleTimeout = cast.ToDuration(l.get("HTTP_IDLE_TIMEOUT", "60s"))
+	cfg.RequestTimeout = cast.ToDuration(l.get("REQUEST_TIMEOUT", "10s"))
+	cfg.ShutdownTimeout = cast.ToDuration(l.get("SHUTDOWN_TIMEOUT", "20s"))
+
+	cfg.PostgresHost = cast.ToString(l.get("POSTGRES_HOST", "localhost"))
+	cfg.PostgresPort = cast.ToString(l.get("POSTGRES_PORT", 5432))
+	cfg.PostgresUser = cast.ToString(l.get("POSTGRES_USER", "db_user"))
+	cfg.PostgresPassword = cast.ToString(l.get("POSTGRES_PASSWORD", defaultPostgresPassword))
+	cfg.PostgresDatabase = cast.ToString(l.get("POSTGRES_DATABASE", "store"))
+	cfg.PostgresLogLevel = cast.ToString(l.get("POSTGRES_LOG_LEVEL", "warn"))
+	cfg.AutoMigrate = cast.ToBool(l.get("AUTO_MIGRATE", false))
+
+	cfg.RedisAddr = cast.ToString(l.get("REDIS_ADD", "localhost:6379"))
+	cfg.RedisPassword = cast.ToString(l.get("REDIS_PASSWORD", defaultRedisPassword))
+	cfg.RedisDB = cast.ToInt(l.get("REDIS_DATABASE", 0))
+	cfg.RedisTimeout = cast.ToDuration(l.get("REDIS_TIMEOUT", "500ms"))
+	cfg.RedisBreakerThreshold = cast.ToInt(l.get("REDIS_BREAKER_THRESHOLD", 5))
+	cfg.RedisBreakerCooldown = cast.ToDuration(l.get("REDIS_BREAKER_COOLDOWN", "30s"))
+
+	cfg.CacheTTL = cast.ToDuration(l.get("CACHE_TTL", "5m"))
+	cfg.CacheListTTL = cast.ToDuration(l.get("CACHE_LIST_TTL", "1m"))
+
+	cfg.SecretKey = cast.ToString(l.get("SECRET_KEY", defaultSecretKey))
+	cfg.JWTSigningKey = cast.ToString(l.get("JWT_SIGNING_KEY", "secret"))
+	cfg.JWTKeys = cast.ToString(l.get("JWT_KEYS", ""))
 
-	cfg.PostgresHost = cast.ToString(getOrReturnDefaultValue("POSTGRES_HOST", "localhost"))
-	cfg.PostgresPort = cast.ToString(getOrReturnDefaultValue("POSTGRES_PORT", 5432))
-	cfg.PostgresUser = cast.ToString(getOrReturnDefaultValue("POSTGRES_USER", "db_user"))
-	cfg.PostgresPassword = cast.ToString(getOrReturnDefaultValue("POSTGRES_PASSWORD", "db_password"))
-	cfg.PostgresDatabase = cast.ToString(getOrReturnDefaultValue("POSTGRES_DATABASE", "store"))
+	cfg.RateLimitAuth.Requests = cast.ToInt(l.get("RATE_LIMIT_AUTH_REQUESTS", 10))
+	cfg.RateLimitAuth.Window = cast.ToDuration(l.get("RATE_LIMIT_AUTH_WINDOW", "1m"))
+	cfg.RateLimitAPI.Requests = cast.ToInt(l.get("RATE_LIMIT_API_REQUESTS", 300))
+	cfg.RateLimitAPI.Window = cast.ToDuration(l.get("RATE_LIMIT_API_WINDOW", "1m"))
+	cfg.TrustedProxies = splitList(cast.ToString(l.get("TRUSTED_PROXIES", "")))
 
-	cfg.RedisAddr = cast.ToString(getOrReturnDefaultValue("REDIS_ADD", "localhost:6379"))
-	cfg.RedisPassword = cast.ToString(getOrReturnDefaultValue("REDIS_PASSWORD", "redis_password"))
-	cfg.RedisDB = cast.ToInt(getOrReturnDefaultValue("REDIS_DATABASE", 0))
+	cfg.LoginLockoutThreshold = cast.ToInt(l.get("LOGIN_LOCKOUT_THRESHOLD", 5))
+	cfg.LoginLockoutBase = cast.ToDuration(l.get("LOGIN_LOCKOUT_BASE", "1m"))
+	cfg.LoginLockoutMax = cast.ToDuration(l.get("LOGIN_LOCKOUT_MAX", "1h"))
+	cfg.LoginFailuresTTL = cast.ToDuration(l.get("LOGIN_FAILURES_TTL", "24h"))
 
-	cfg.SecretKey = cast.ToString(getOrReturnDefaultValue("SECRET_KEY", "topolmaysan"))
+	cfg.IdempotencyKeyTTL = cast.ToDuration(l.get("IDEMPOTENCY_KEY_TTL", "24h"))
 
-	cfg.DefaultOffset = cast.ToInt(getOrReturnDefaultValue("OFFSET", 0))
-	cfg.DefaultLimit = cast.ToInt(getOrReturnDefaultValue("LIMIT", 10))
+	cfg.DefaultOffset = cast.ToInt(l.get("OFFSET", 0))
+	cfg.DefaultLimit = cast.ToInt(l.get("LIMIT", 10))
 
-	cfg.PostgresMaxConnections = cast.ToInt32(getOrReturnDefaultValue("PostgresMaxConnections", 20))
+	cfg.PostgresMaxConnections = cast.ToInt32(l.get("POSTGRES_MAX_CONNECTIONS", 20, "PostgresMaxConnections"))
+	cfg.PostgresMinConnections = cast.ToInt32(l.get("POSTGRES_MIN_CONNECTIONS", 0))
+	cfg.PostgresMaxConnLifetime = cast.ToDuration(l.get("POSTGRES_MAX_CONN_LIFETIME", "1h"))
+	cfg.PostgresMaxConnIdleTime = cast.ToDuration(l.get("POSTGRES_MAX_CONN_IDLE_TIME", "30m"))
+	cfg.PostgresHealthCheckPeriod = cast.ToDuration(l.get("POSTGRES_HEALTH_CHECK_PERIOD", "1m"))
+	cfg.PostgresConnectTimeout = cast.ToDuration(l.get("POSTGRES_CONNECT_TIMEOUT", "5s"))
+	cfg.PostgresStatementTimeout = cast.ToDuration(l.get("POSTGRES_STATEMENT_TIMEOUT", 0))
 
-	return cfg
+	cfg.OverdueCheckInterval = cast.ToDuration(l.get("OVERDUE_CHECK_INTERVAL", "10m"))
+	cfg.OrderAtRiskDays = cast.ToInt(l.get("ORDER_AT_RISK_DAYS", 2))
+
+	cfg.WebhookInterval = cast.ToDuration(l.get("WEBHOOK_INTERVAL", "5s"))
+	cfg.WebhookBatchSize = cast.ToInt(l.get("WEBHOOK_BATCH_SIZE", 20))
+	cfg.WebhookTimeout = cast.ToDuration(l.get("WEBHOOK_TIMEOUT", "10s"))
+	cfg.WebhookBackoffBase = cast.ToDuration(l.get("WEBHOOK_BACKOFF_BASE", "30s"))
+	cfg.WebhookBackoffMax = cast.ToDuration(l.get("WEBHOOK_BACKOFF_MAX", "6h"))
+	cfg.WebhookMaxAttempts = cast.ToInt(l.get("WEBHOOK_MAX_ATTEMPTS", 10))
+	cfg.WebhookAllowPrivateNetworks = cast.ToBool(l.get("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false))
+
+	cfg.OutboxInterval = cast.ToDuration(l.get("OUTBOX_INTERVAL", "1s"))
+	cfg.OutboxBatchSize = cast.ToInt(l.get("OUTBOX_BATCH_SIZE", 100))
+	cfg.OutboxRetention = cast.ToDuration(l.get("OUTBOX_RETENTION", "168h"))
+	cfg.EventPublisher = cast.ToString(l.get("EVENT_PUBLISHER", "log"))
+	cfg.NATSURL = cast.ToString(l.get("NATS_URL", "nats://localhost:4222"))
+	cfg.NATSSubjectPrefix = cast.ToString(l.get("NATS_SUBJECT_PREFIX", "store.events"))
+
+	cfg.TracingExporter = cast.ToString(l.get("TRACING_EXPORTER", "none"))
+	cfg.TracingFile = cast.ToString(l.get("TRACING_FILE", "traces.json"))
+	cfg.TracingOTLPEndpoint = cast.ToString(l.get("TRACING_OTLP_ENDPOINT", "localhost:4318"))
+	cfg.TracingSampleRatio = cast.ToFloat64(l.get("TRACING_SAMPLE_RATIO", 1))
+
+	cfg.settings = l.settings
+	cfg.unknown = l.unknown()
+
+	return cfg, nil
 }
 
-func getOrReturnDefaultValue(key string, defaultValue interface{}) interface{} {
-	val, exists := os.LookupEnv(key)
+// splitList splits the comma separated value, leaving the blank entries out.
+func splitList(value string) []string {
+
+	var list []string
 
-	if exists {
-		return val
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
 	}
 
-	return defaultValue
+	return list
 }
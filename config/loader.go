@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Setting sources
+const (
+	SourceDefault = "default"
+	SourceEnv     = "env"
+)
+
+// Setting is a loaded setting, Source is default, env or the path of the config file.
+type Setting struct {
+	Key    string
+	Value  interface{}
+	Source string
+}
+
+// loader looks the settings up in the environment, then in the config files.
+type loader struct {
+	// file holds the values of the config files by their env key
+	file     map[string]fileValue
+	read     map[string]bool
+	settings []Setting
+}
+
+type fileValue struct {
+	value interface{}
+	path  string
+}
+
+// newLoader reads the comma separated config files of paths, YAML or TOML by extension.
+// The nested keys are joined with underscores and upper cased, so POSTGRES_HOST can be
+// written as postgres_host or as host under postgres.
+func newLoader(paths string) (*loader, error) {
+
+	l := &loader{
+		file: map[string]fileValue{},
+		read: map[string]bool{},
+	}
+
+	for _, path := range strings.Split(paths, ",") {
+
+		path = strings.TrimSpace(path)
+		if len(path) == 0 {
+			continue
+		}
+
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config file: %w", err)
+		}
+
+		values := map[string]interface{}{}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(body, &values)
+		case ".toml":
+			err = toml.Unmarshal(body, &values)
+		default:
+			return nil, fmt.Errorf("config file %s: only .yaml, .yml and .toml files are supported", path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+
+		l.flatten("", values, path)
+	}
+
+	return l, nil
+}
+
+func (l *loader) flatten(prefix string, values map[string]interface{}, path string) {
+
+	for key, value := range values {
+
+		key = strings.ToUpper(prefix + key)
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			l.flatten(key+"_", nested, path)
+			continue
+		}
+
+		l.file[key] = fileValue{value: value, path: path}
+	}
+}
+
+// get returns the value of key, aliases are the former names of the key.
+func (l *loader) get(key string, defaultValue interface{}, aliases ...string) interface{} {
+
+	names := append([]string{key}, aliases...)
+
+	for _, name := range names {
+		l.read[strings.ToUpper(name)] = true
+	}
+
+	setting := l.lookup(names)
+	if setting == nil {
+		setting = &Setting{Value: defaultValue, Source: SourceDefault}
+	}
+
+	setting.Key = key
+	l.settings = append(l.settings, *setting)
+
+	return setting.Value
+}
+
+// lookup returns the value of the first of names set in the environment, else in the config files.
+func (l *loader) lookup(names []string) *Setting {
+
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			return &Setting{Value: value, Source: SourceEnv}
+		}
+	}
+
+	for _, name := range names {
+		if value, ok := l.file[strings.ToUpper(name)]; ok {
+			return &Setting{Value: value.value, Source: value.path}
+		}
+	}
+
+	return nil
+}
+
+// unknown returns the keys of the config files no setting read, most likely typos.
+func (l *loader) unknown() []string {
+
+	var keys []string
+	for key, value := range l.file {
+		if !l.read[key] {
+			keys = append(keys, fmt.Sprintf("%s in %s", key, value.path))
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// secretKeys are the settings print hides when redacted. NATS_URL may carry user:pass@,
+// the webhook secrets are not settings: every subscription keeps its own.
+var secretKeys = map[string]bool{
+	"SECRET_KEY":        true,
+	"POSTGRES_PASSWORD": true,
+	"REDIS_PASSWORD":    true,
+	"NATS_URL":          true,
+}
+
+// Validate reports every setting the service can not start with.
+func (c *Config) Validate() error {
+
+	var problems []string
+
+	switch c.Environment {
+	case DebugMode, TestMode, ReleaseMode:
+	default:
+		problems = append(problems, fmt.Sprintf("ENVIRONMENT must be debug, test or release, got %q", c.Environment))
+	}
+
+	if c.Environment == ReleaseMode {
+		for _, secret := range []struct {
+			key      string
+			insecure bool
+		}{
+			// an empty SECRET_KEY is fine once another key signs the tokens
+			{"SECRET_KEY", c.SecretKey == defaultSecretKey || (len(c.SecretKey) == 0 && c.JWTSigningKey == "secret")},
+			{"POSTGRES_PASSWORD", c.PostgresPassword == defaultPostgresPassword},
+			{"REDIS_PASSWORD", c.RedisPassword == defaultRedisPassword},
+		} {
+			if secret.insecure {
+				problems = append(problems, fmt.Sprintf("%s must be set to a non default value in release mode", secret.key))
+			}
+		}
+	}
+
+	if c.Environment == ReleaseMode && c.WebhookAllowPrivateNetworks {
+		problems = append(problems, "WEBHOOK_ALLOW_PRIVATE_NETWORKS must not be set in release mode")
+	}
+
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES must list IP addresses or CIDRs, got %q", proxy))
+		}
+	}
+
+	problems = append(problems, c.postgresProblems()...)
+
+	if len(c.JWTSigningKey) == 0 {
+		problems = append(problems, "JWT_SIGNING_KEY must be set")
+	}
+
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		problems = append(problems, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	for _, key := range c.unknown {
+		problems = append(problems, "unknown setting "+key)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// ValidatePostgres reports the postgres settings only, for the commands that need nothing
+// but the database, like migrate.
+func (c *Config) ValidatePostgres() error {
+
+	problems := c.postgresProblems()
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+func (c *Config) postgresProblems() (problems []string) {
+
+	if c.PostgresMaxConnections < 1 {
+		problems = append(problems, "POSTGRES_MAX_CONNECTIONS must be at least 1")
+	}
+
+	if c.PostgresMinConnections < 0 || c.PostgresMinConnections > c.PostgresMaxConnections {
+		problems = append(problems, "POSTGRES_MIN_CONNECTIONS must be between 0 and POSTGRES_MAX_CONNECTIONS")
+	}
+
+	for _, duration := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"POSTGRES_MAX_CONN_LIFETIME", c.PostgresMaxConnLifetime},
+		{"POSTGRES_MAX_CONN_IDLE_TIME", c.PostgresMaxConnIdleTime},
+		{"POSTGRES_HEALTH_CHECK_PERIOD", c.PostgresHealthCheckPeriod},
+		{"POSTGRES_CONNECT_TIMEOUT", c.PostgresConnectTimeout},
+		{"POSTGRES_STATEMENT_TIMEOUT", c.PostgresStatementTimeout},
+	} {
+		if duration.value < 0 {
+			problems = append(problems, fmt.Sprintf("%s must not be negative", duration.key))
+		}
+	}
+
+	return problems
+}
+
+// Print writes the loaded settings as YAML with their sources, redacted hides the secrets.
+// The output can be used as a config file.
+func (c *Config) Print(w io.Writer, redacted bool) error {
+
+	for _, setting := range c.settings {
+
+		value, err := json.Marshal(setting.Value)
+		if err != nil {
+			return err
+		}
+
+		if redacted && secretKeys[setting.Key] && setting.Value != "" {
+			value = []byte(`"[REDACTED]"`)
+		}
+
+		_, err = fmt.Fprintf(w, "%s: %s # %s\n", strings.ToLower(setting.Key), value, setting.Source)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL migrations, the service applies them with
+// "migrate up" or on start when AUTO_MIGRATE is set.
+package migrations
+
+import "embed"
+
+//go:embed postgres/*.sql
+var Postgres embed.FS
@@ -7,4 +7,6 @@ DROP TABLE IF EXISTS "categories";
 DROP TABLE IF EXISTS "brands";
 DROP TABLE IF EXISTS "customers";
 DROP TABLE IF EXISTS "staffs";
-DROP TABLE IF EXISTS "stores";
\ No newline at end of file
+DROP TABLE IF EXISTS "stores";
+DROP TABLE IF EXISTS "promo_code";
+DROP TABLE IF EXISTS "users";
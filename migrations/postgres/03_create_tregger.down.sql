@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS add_product_to_store_tg ON order_items;
+DROP FUNCTION IF EXISTS add_product_to_store();
+
+DROP TRIGGER IF EXISTS order_item_product_tg ON order_items;
+DROP FUNCTION IF EXISTS get_product_from_store();
@@ -0,0 +1,39 @@
+DROP TRIGGER IF EXISTS stock_movement_tg ON stocks;
+DROP FUNCTION IF EXISTS record_stock_movement();
+
+DROP TRIGGER IF EXISTS stock_movements_append_only_tg ON stock_movements;
+DROP FUNCTION IF EXISTS forbid_stock_movement_change();
+
+DROP TABLE IF EXISTS "stock_movements";
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS created_at;
+
+CREATE OR REPLACE FUNCTION get_product_from_store() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    DECLARE 
+        storeId integer;
+    BEGIN
+        
+        SELECT orders.store_id FROM orders INTO storeId WHERE orders.order_id = new.order_id;
+
+        UPDATE stocks SET quantity = quantity - new.quantity WHERE store_id = storeId AND product_id =  new.product_id;
+
+        return new;
+    END;
+$$;
+
+CREATE OR REPLACE FUNCTION add_product_to_store() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    DECLARE 
+        storeId integer;
+    BEGIN
+        
+        SELECT orders.store_id FROM orders INTO storeId WHERE orders.order_id = old.order_id;
+
+        UPDATE stocks SET quantity = quantity + old.quantity WHERE store_id = storeId AND product_id =  old.product_id;
+
+        return new;
+    END;
+$$;
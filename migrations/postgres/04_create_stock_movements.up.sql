@@ -0,0 +1,130 @@
+CREATE TABLE IF NOT EXISTS stock_movements (
+	movement_id SERIAL PRIMARY KEY,
+	store_id INT NOT NULL,
+	product_id INT NOT NULL,
+	quantity INT NOT NULL,
+	-- Movement reason: sale; return; adjustment; transfer; receiving
+	reason VARCHAR (20) NOT NULL CHECK(reason IN ('sale', 'return', 'adjustment', 'transfer', 'receiving')),
+	reference_id VARCHAR (255),
+	actor VARCHAR (255),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_stock_movements_store_product ON stock_movements(store_id, product_id);
+
+-- The items sold before the ledger are in its opening balance, the stock recompute tells them
+-- from the later ones by the time they were created. The existing items keep no time, their
+-- order date stands for it.
+ALTER TABLE order_items ADD COLUMN created_at TIMESTAMP;
+ALTER TABLE order_items ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+
+-- Opening balance so that the ledger reconciles with the already seeded stocks.
+INSERT INTO stock_movements(store_id, product_id, quantity, reason, reference_id, actor)
+SELECT store_id, product_id, quantity, 'adjustment', 'opening_balance', 'migration'
+FROM stocks WHERE COALESCE(quantity, 0) <> 0;
+
+
+-- The ledger is append-only: history can not be rewritten.
+CREATE OR REPLACE FUNCTION forbid_stock_movement_change() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    BEGIN
+        RAISE EXCEPTION 'stock_movements is append-only';
+    END;
+$$;
+
+CREATE TRIGGER stock_movements_append_only_tg
+BEFORE UPDATE OR DELETE ON stock_movements
+FOR EACH ROW EXECUTE PROCEDURE forbid_stock_movement_change();
+
+
+-- Every change of stocks.quantity is written to the ledger. The reason, reference and actor
+-- are taken from the transaction local settings app.stock_reason, app.stock_reference and app.stock_actor.
+-- A fix bringing the stocks back to their ledger must not be recorded as one more movement,
+-- the transaction sets app.stock_skip_ledger instead of disabling the trigger, which takes
+-- an ACCESS EXCLUSIVE lock on stocks and needs to own the table.
+CREATE OR REPLACE FUNCTION record_stock_movement() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    DECLARE 
+        reason varchar;
+        referenceId varchar;
+        actor varchar;
+    BEGIN
+
+        IF current_setting('app.stock_skip_ledger', true) = 'on' THEN
+            return null;
+        END IF;
+
+        reason := COALESCE(NULLIF(current_setting('app.stock_reason', true), ''), 'adjustment');
+        referenceId := NULLIF(current_setting('app.stock_reference', true), '');
+        actor := NULLIF(current_setting('app.stock_actor', true), '');
+
+        IF TG_OP IN ('UPDATE', 'DELETE') AND COALESCE(old.quantity, 0) <> 0 THEN
+            IF TG_OP = 'DELETE' OR old.store_id <> new.store_id OR old.product_id <> new.product_id THEN
+                INSERT INTO stock_movements(store_id, product_id, quantity, reason, reference_id, actor)
+                VALUES (old.store_id, old.product_id, -old.quantity, reason, referenceId, actor);
+            END IF;
+        END IF;
+
+        IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND (old.store_id <> new.store_id OR old.product_id <> new.product_id)) THEN
+            IF COALESCE(new.quantity, 0) <> 0 THEN
+                INSERT INTO stock_movements(store_id, product_id, quantity, reason, reference_id, actor)
+                VALUES (new.store_id, new.product_id, new.quantity, reason, referenceId, actor);
+            END IF;
+        ELSIF TG_OP = 'UPDATE' AND COALESCE(new.quantity, 0) <> COALESCE(old.quantity, 0) THEN
+            INSERT INTO stock_movements(store_id, product_id, quantity, reason, reference_id, actor)
+            VALUES (new.store_id, new.product_id, COALESCE(new.quantity, 0) - COALESCE(old.quantity, 0), reason, referenceId, actor);
+        END IF;
+
+        return null;
+    END;
+$$;
+
+CREATE TRIGGER stock_movement_tg
+AFTER INSERT OR UPDATE OR DELETE ON stocks
+FOR EACH ROW EXECUTE PROCEDURE record_stock_movement();
+
+
+CREATE OR REPLACE FUNCTION get_product_from_store() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    DECLARE 
+        storeId integer;
+    BEGIN
+        
+        SELECT orders.store_id FROM orders INTO storeId WHERE orders.order_id = new.order_id;
+
+        PERFORM set_config('app.stock_reason', 'sale', true);
+        PERFORM set_config('app.stock_reference', 'order:' || new.order_id || ':' || new.item_id, true);
+
+        UPDATE stocks SET quantity = quantity - new.quantity WHERE store_id = storeId AND product_id =  new.product_id;
+
+        PERFORM set_config('app.stock_reason', '', true);
+        PERFORM set_config('app.stock_reference', '', true);
+
+        return new;
+    END;
+$$;
+
+
+CREATE OR REPLACE FUNCTION add_product_to_store() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    DECLARE 
+        storeId integer;
+    BEGIN
+        
+        SELECT orders.store_id FROM orders INTO storeId WHERE orders.order_id = old.order_id;
+
+        PERFORM set_config('app.stock_reason', 'return', true);
+        PERFORM set_config('app.stock_reference', 'order:' || old.order_id || ':' || old.item_id, true);
+
+        UPDATE stocks SET quantity = quantity + old.quantity WHERE store_id = storeId AND product_id =  old.product_id;
+
+        PERFORM set_config('app.stock_reason', '', true);
+        PERFORM set_config('app.stock_reference', '', true);
+
+        return old;
+    END;
+$$;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS order_return_tg ON order_returns;
+DROP FUNCTION IF EXISTS return_product_to_store();
+
+DROP TABLE IF EXISTS "order_returns";
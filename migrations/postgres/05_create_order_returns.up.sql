@@ -0,0 +1,71 @@
+CREATE TABLE IF NOT EXISTS order_returns (
+	return_id SERIAL PRIMARY KEY,
+	order_id INT NOT NULL,
+	item_id INT NOT NULL,
+	quantity INT NOT NULL CHECK(quantity > 0),
+	reason VARCHAR (255),
+	refund_amount DECIMAL (10, 2) NOT NULL DEFAULT 0 CHECK(refund_amount >= 0),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	-- the returns keep the record of the refunds, an order item with returns can not be removed
+	FOREIGN KEY (order_id, item_id) REFERENCES order_items (order_id, item_id) ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_order_returns_order ON order_returns(order_id, item_id);
+
+
+-- A return puts the product back into the store of the order and is recorded in the ledger.
+CREATE OR REPLACE FUNCTION return_product_to_store() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    DECLARE 
+        storeId integer;
+        productId integer;
+        soldQuantity integer;
+        paidAmount numeric;
+        returnedQuantity integer;
+        refundedAmount numeric;
+    BEGIN
+
+        SELECT
+            o.store_id,
+            oi.product_id,
+            oi.quantity,
+            oi.quantity * oi.list_price * (1 - oi.discount)
+        INTO storeId, productId, soldQuantity, paidAmount
+        FROM order_items AS oi
+        JOIN orders AS o ON o.order_id = oi.order_id
+        WHERE oi.order_id = new.order_id AND oi.item_id = new.item_id
+        FOR UPDATE OF oi;
+
+        SELECT
+            COALESCE(SUM(quantity), 0),
+            COALESCE(SUM(refund_amount), 0)
+        INTO returnedQuantity, refundedAmount
+        FROM order_returns WHERE order_id = new.order_id AND item_id = new.item_id;
+
+        IF returnedQuantity + new.quantity > soldQuantity THEN
+            RAISE EXCEPTION 'returned quantity % exceeds sold quantity %', returnedQuantity + new.quantity, soldQuantity;
+        END IF;
+
+        IF refundedAmount + new.refund_amount > paidAmount THEN
+            RAISE EXCEPTION 'refund amount % exceeds paid amount %', refundedAmount + new.refund_amount, paidAmount;
+        END IF;
+
+        PERFORM set_config('app.stock_reason', 'return', true);
+        PERFORM set_config('app.stock_reference', 'return:' || new.return_id, true);
+
+        UPDATE stocks SET quantity = quantity + new.quantity WHERE store_id = storeId AND product_id = productId;
+        IF NOT FOUND THEN
+            INSERT INTO stocks(store_id, product_id, quantity) VALUES (storeId, productId, new.quantity);
+        END IF;
+
+        PERFORM set_config('app.stock_reason', '', true);
+        PERFORM set_config('app.stock_reference', '', true);
+
+        return new;
+    END;
+$$;
+
+CREATE TRIGGER order_return_tg
+BEFORE INSERT ON order_returns
+FOR EACH ROW EXECUTE PROCEDURE return_product_to_store();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS staff_manager_cycle_tg ON staffs;
+DROP FUNCTION IF EXISTS check_staff_manager_cycle();
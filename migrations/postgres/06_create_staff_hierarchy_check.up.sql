@@ -0,0 +1,34 @@
+-- A staff member can not be managed by himself or by anyone from his own reports.
+CREATE OR REPLACE FUNCTION check_staff_manager_cycle() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    DECLARE 
+        cycleFound boolean;
+    BEGIN
+
+        IF new.manager_id IS NULL THEN
+            return new;
+        END IF;
+
+        WITH RECURSIVE chain AS (
+            SELECT staff_id, manager_id, ARRAY[staff_id] AS path
+            FROM staffs WHERE staff_id = new.manager_id
+            UNION ALL
+            SELECT s.staff_id, s.manager_id, chain.path || s.staff_id
+            FROM staffs AS s
+            JOIN chain ON s.staff_id = chain.manager_id
+            WHERE NOT s.staff_id = ANY(chain.path)
+        )
+        SELECT EXISTS (SELECT 1 FROM chain WHERE staff_id = new.staff_id) INTO cycleFound;
+
+        IF new.manager_id = new.staff_id OR cycleFound THEN
+            RAISE EXCEPTION 'manager % of staff % creates a cycle in the manager tree', new.manager_id, new.staff_id;
+        END IF;
+
+        return new;
+    END;
+$$;
+
+CREATE TRIGGER staff_manager_cycle_tg
+BEFORE INSERT OR UPDATE OF manager_id ON staffs
+FOR EACH ROW EXECUTE PROCEDURE check_staff_manager_cycle();
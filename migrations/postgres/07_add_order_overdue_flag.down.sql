@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS orders_unshipped_required_date_idx;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS overdue_flagged_at;
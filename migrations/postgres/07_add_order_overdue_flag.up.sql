@@ -0,0 +1,4 @@
+-- overdue_flagged_at is set once by the overdue job when the order passes its required_date unshipped.
+ALTER TABLE orders ADD COLUMN overdue_flagged_at TIMESTAMP;
+
+CREATE INDEX orders_unshipped_required_date_idx ON orders (required_date) WHERE shipped_date IS NULL;
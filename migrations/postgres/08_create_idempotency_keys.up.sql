@@ -0,0 +1,13 @@
+-- idempotency_keys keeps the response of the requests sent with an Idempotency-Key header,
+-- status is 0 while the first request is still running.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	key VARCHAR (255) PRIMARY KEY,
+	request_hash VARCHAR (64) NOT NULL,
+	status INT NOT NULL DEFAULT 0,
+	content_type VARCHAR (255) NOT NULL DEFAULT '',
+	body BYTEA,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- the expired keys are purged by their reservation time
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+	subscription_id SERIAL PRIMARY KEY,
+	url VARCHAR (2048) NOT NULL,
+	secret VARCHAR (255) NOT NULL,
+	-- Event types: order.created; order.status_changed; stock.changed
+	event_types VARCHAR (64)[] NOT NULL,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- webhook_deliveries is the delivery queue and the delivery log of the subscriptions,
+-- a pending delivery is sent again at next_attempt_at until it is delivered or failed.
+-- The deliveries are queued by the outbox relay from the events it publishes.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	delivery_id SERIAL PRIMARY KEY,
+	subscription_id INT NOT NULL,
+	-- the outbox event of the delivery, an event published again is not queued twice
+	event_id BIGINT NOT NULL,
+	event_type VARCHAR (64) NOT NULL,
+	payload JSONB NOT NULL,
+	-- Delivery status: pending; delivered; failed
+	status VARCHAR (20) NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'delivered', 'failed')),
+	attempts INT NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	response_code INT,
+	-- the error of the last attempt, a receiver's response is never kept: only its status code
+	last_error TEXT,
+	delivered_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions (subscription_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(subscription_id, event_id);
+CREATE INDEX idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, delivery_id);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
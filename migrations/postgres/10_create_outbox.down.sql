@@ -0,0 +1,15 @@
+DROP TRIGGER IF EXISTS product_outbox_tg ON products;
+DROP FUNCTION IF EXISTS product_outbox();
+
+DROP TRIGGER IF EXISTS stock_movement_outbox_tg ON stock_movements;
+DROP FUNCTION IF EXISTS stock_movement_outbox();
+
+DROP TRIGGER IF EXISTS order_item_outbox_tg ON order_items;
+DROP FUNCTION IF EXISTS order_item_outbox();
+
+DROP TRIGGER IF EXISTS order_outbox_tg ON orders;
+DROP FUNCTION IF EXISTS order_outbox();
+
+DROP FUNCTION IF EXISTS add_outbox_event(VARCHAR, VARCHAR, VARCHAR, JSONB);
+
+DROP TABLE IF EXISTS "outbox";
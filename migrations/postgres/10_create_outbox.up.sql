@@ -0,0 +1,124 @@
+-- outbox holds the domain events written in the transaction of the change that causes them,
+-- the relay publishes them in event_id order and sets published_at.
+CREATE TABLE IF NOT EXISTS outbox (
+	event_id BIGSERIAL PRIMARY KEY,
+	-- Event types: OrderCreated; OrderItemAdded; OrderStatusChanged; StockAdjusted; ProductPriceChanged
+	event_type VARCHAR (64) NOT NULL,
+	aggregate_type VARCHAR (64) NOT NULL,
+	aggregate_id VARCHAR (255) NOT NULL,
+	payload JSONB NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	published_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_unpublished ON outbox(event_id) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_published_at ON outbox(published_at) WHERE published_at IS NOT NULL;
+
+
+CREATE OR REPLACE FUNCTION add_outbox_event(eventType VARCHAR, aggregateType VARCHAR, aggregateId VARCHAR, payload JSONB) RETURNS VOID LANGUAGE PLPGSQL
+    AS
+$$
+    BEGIN
+
+        INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload)
+        VALUES (eventType, aggregateType, aggregateId, payload);
+
+    END;
+$$;
+
+CREATE OR REPLACE FUNCTION order_outbox() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    BEGIN
+
+        IF TG_OP = 'INSERT' THEN
+            PERFORM add_outbox_event('OrderCreated', 'order', new.order_id::VARCHAR, jsonb_build_object(
+                'order_id', new.order_id,
+                'customer_id', new.customer_id,
+                'order_status', new.order_status,
+                'order_date', new.order_date,
+                'required_date', new.required_date,
+                'store_id', new.store_id,
+                'staff_id', new.staff_id
+            ));
+        ELSIF old.order_status IS DISTINCT FROM new.order_status THEN
+            PERFORM add_outbox_event('OrderStatusChanged', 'order', new.order_id::VARCHAR, jsonb_build_object(
+                'order_id', new.order_id,
+                'old_status', old.order_status,
+                'order_status', new.order_status,
+                'store_id', new.store_id
+            ));
+        END IF;
+
+        return new;
+    END;
+$$;
+
+CREATE TRIGGER order_outbox_tg
+AFTER INSERT OR UPDATE OF order_status ON orders
+FOR EACH ROW EXECUTE PROCEDURE order_outbox();
+
+CREATE OR REPLACE FUNCTION order_item_outbox() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    BEGIN
+
+        PERFORM add_outbox_event('OrderItemAdded', 'order', new.order_id::VARCHAR, jsonb_build_object(
+            'order_id', new.order_id,
+            'item_id', new.item_id,
+            'product_id', new.product_id,
+            'quantity', new.quantity,
+            'list_price', new.list_price,
+            'discount', new.discount
+        ));
+
+        return new;
+    END;
+$$;
+
+CREATE TRIGGER order_item_outbox_tg
+AFTER INSERT ON order_items
+FOR EACH ROW EXECUTE PROCEDURE order_item_outbox();
+
+CREATE OR REPLACE FUNCTION stock_movement_outbox() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    BEGIN
+
+        PERFORM add_outbox_event('StockAdjusted', 'stock', new.store_id || ':' || new.product_id, jsonb_build_object(
+            'movement_id', new.movement_id,
+            'store_id', new.store_id,
+            'product_id', new.product_id,
+            'quantity', new.quantity,
+            'reason', new.reason,
+            'reference_id', new.reference_id
+        ));
+
+        return new;
+    END;
+$$;
+
+CREATE TRIGGER stock_movement_outbox_tg
+AFTER INSERT ON stock_movements
+FOR EACH ROW EXECUTE PROCEDURE stock_movement_outbox();
+
+CREATE OR REPLACE FUNCTION product_outbox() RETURNS TRIGGER LANGUAGE PLPGSQL
+    AS
+$$
+    BEGIN
+
+        IF old.list_price IS DISTINCT FROM new.list_price THEN
+            PERFORM add_outbox_event('ProductPriceChanged', 'product', new.product_id::VARCHAR, jsonb_build_object(
+                'product_id', new.product_id,
+                'old_price', old.list_price,
+                'list_price', new.list_price
+            ));
+        END IF;
+
+        return new;
+    END;
+$$;
+
+CREATE TRIGGER product_outbox_tg
+AFTER UPDATE OF list_price ON products
+FOR EACH ROW EXECUTE PROCEDURE product_outbox();
@@ -0,0 +1,2 @@
+-- role is user for the registered users, the admins are created with the admin CLI.
+ALTER TABLE users ADD COLUMN role VARCHAR (20) NOT NULL DEFAULT 'user' CHECK(role IN ('user', 'admin'));
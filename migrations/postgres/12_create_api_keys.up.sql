@@ -0,0 +1,19 @@
+-- api_keys authenticate the integrations, like the POS terminals and the ERP, instead of a
+-- user login. The key itself is only returned by its creation, key_hash is its SHA-256 and
+-- prefix is its start, which tells the keys apart in the lists.
+CREATE TABLE IF NOT EXISTS api_keys (
+	api_key_id SERIAL PRIMARY KEY,
+	name VARCHAR (255) NOT NULL,
+	prefix VARCHAR (32) NOT NULL,
+	key_hash CHAR (64) NOT NULL UNIQUE,
+	-- Scopes: <resource>:read; <resource>:write
+	scopes VARCHAR (64)[] NOT NULL,
+	-- store_id restricts the key to one store, NULL lets it reach every store
+	store_id INT,
+	expires_at TIMESTAMP,
+	last_used_at TIMESTAMP,
+	revoked_at TIMESTAMP,
+	created_by VARCHAR (255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	FOREIGN KEY (store_id) REFERENCES stores (store_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,63 @@
+// Package events publishes the domain events relayed from the outbox table, the
+// publisher is chosen by config: in-process, log or NATS.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"app/config"
+	"app/pkg/logger"
+)
+
+// Publishers
+const (
+	PublisherInProcess = "inprocess"
+	PublisherLog       = "log"
+	PublisherNATS      = "nats"
+)
+
+// Event types
+const (
+	OrderCreated        = "OrderCreated"
+	OrderItemAdded      = "OrderItemAdded"
+	OrderStatusChanged  = "OrderStatusChanged"
+	StockAdjusted       = "StockAdjusted"
+	ProductPriceChanged = "ProductPriceChanged"
+)
+
+// Event is a domain event. ID is unique, the consumers drop the events published again
+// after a relay crash by keeping the ids they have seen. The ids are not a high-water mark:
+// they are taken when the event is written and the transactions writing them commit
+// in any order, so an event may be published after one with a higher id.
+type Event struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// EventPublisherI publishes the events, an error leaves the event in the outbox to be retried.
+type EventPublisherI interface {
+	Publish(ctx context.Context, event *Event) error
+	Close() error
+}
+
+// NewPublisher returns the publisher of cfg.EventPublisher.
+func NewPublisher(cfg *config.Config, log logger.LoggerI) (EventPublisherI, error) {
+
+	switch cfg.EventPublisher {
+	case PublisherInProcess:
+		return NewInProcessPublisher(), nil
+	case PublisherLog:
+		return NewLogPublisher(log), nil
+	case PublisherNATS:
+		return NewNATSPublisher(cfg.NATSURL, cfg.NATSSubjectPrefix)
+	default:
+		return nil, fmt.Errorf("events: unknown publisher %q", cfg.EventPublisher)
+	}
+}
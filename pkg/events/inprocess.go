@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// HandlerFunc consumes an event of the in-process publisher.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// InProcessPublisher calls the handlers subscribed to the event type in the relay goroutine,
+// the first handler error fails the publish.
+type InProcessPublisher struct {
+	mu       sync.RWMutex
+	handlers map[string][]HandlerFunc
+}
+
+func NewInProcessPublisher() *InProcessPublisher {
+	return &InProcessPublisher{
+		handlers: map[string][]HandlerFunc{},
+	}
+}
+
+// Subscribe calls handler for every published event of eventType.
+func (p *InProcessPublisher) Subscribe(eventType string, handler HandlerFunc) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+}
+
+func (p *InProcessPublisher) Publish(ctx context.Context, event *Event) error {
+
+	p.mu.RLock()
+	handlers := p.handlers[event.Type]
+	p.mu.RUnlock()
+
+	for _, handler := range handlers {
+		err := handler(ctx, event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *InProcessPublisher) Close() error {
+	return nil
+}
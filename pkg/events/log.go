@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+
+	"app/pkg/logger"
+)
+
+// LogPublisher writes every event to the service log.
+type LogPublisher struct {
+	log logger.LoggerI
+}
+
+func NewLogPublisher(log logger.LoggerI) *LogPublisher {
+	return &LogPublisher{
+		log: log,
+	}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event *Event) error {
+
+	p.log.Info("event "+event.Type,
+		logger.Any("id", event.ID),
+		logger.String("aggregate_type", event.AggregateType),
+		logger.String("aggregate_id", event.AggregateID),
+		logger.Any("payload", event.Payload),
+	)
+
+	return nil
+}
+
+func (p *LogPublisher) Close() error {
+	return nil
+}
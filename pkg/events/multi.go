@@ -0,0 +1,41 @@
+package events
+
+import "context"
+
+// MultiPublisher publishes every event through its publishers in order, the first error fails
+// the publish and the event is published again through all of them, so each publisher has to
+// drop the events it already has.
+type MultiPublisher struct {
+	publishers []EventPublisherI
+}
+
+func NewMultiPublisher(publishers ...EventPublisherI) *MultiPublisher {
+	return &MultiPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p *MultiPublisher) Publish(ctx context.Context, event *Event) error {
+
+	for _, publisher := range p.publishers {
+		err := publisher.Publish(ctx, event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes every publisher and returns the first error.
+func (p *MultiPublisher) Close() (err error) {
+
+	for _, publisher := range p.publishers {
+		closeErr := publisher.Close()
+		if closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsAckTimeout bounds waiting for the stream's ack when the publish context has no deadline
+const natsAckTimeout = 5 * time.Second
+
+// NATSPublisher publishes every event on "<prefix>.<type>" to JetStream with the Nats-Msg-Id
+// header set to the event id, the stream drops the events published twice within its
+// duplicate window. A stream must capture the subjects, an event no stream acks stays in the
+// outbox.
+type NATSPublisher struct {
+	conn   *nats.Conn
+	js     nats.JetStreamContext
+	prefix string
+}
+
+func NewNATSPublisher(url, prefix string) (*NATSPublisher, error) {
+
+	conn, err := nats.Connect(url, nats.Name("store"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &NATSPublisher{
+		conn:   conn,
+		js:     js,
+		prefix: prefix,
+	}, nil
+}
+
+// Publish returns once the stream acked the event, so an event is only marked published when
+// it is stored.
+func (p *NATSPublisher) Publish(ctx context.Context, event *Event) error {
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(p.prefix + "." + event.Type)
+	msg.Header.Set(nats.MsgIdHdr, strconv.FormatInt(event.ID, 10))
+	msg.Data = body
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, natsAckTimeout)
+		defer cancel()
+	}
+
+	_, err = p.js.PublishMsg(msg, nats.Context(ctx))
+
+	return err
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,47 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// apiKeyPrefix starts every API key, so a leaked key is recognized by the secret scanners.
+const apiKeyPrefix = "sk_"
+
+// GenerateAPIKey returns a new API key, its prefix kept to tell it apart in the lists and
+// the hash stored instead of it.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+
+	id := make([]byte, 4)
+	secret := make([]byte, 32)
+
+	_, err = rand.Read(id)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	_, err = rand.Read(secret)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	prefix = apiKeyPrefix + hex.EncodeToString(id)
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the SHA-256 of key in hex. The keys are random, a slow hash like bcrypt
+// adds nothing to them and the hash is looked up on every request.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey reports whether key looks like a key of GenerateAPIKey.
+func IsAPIKey(key string) bool {
+	return strings.HasPrefix(key, apiKeyPrefix)
+}
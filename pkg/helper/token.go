@@ -2,46 +2,35 @@ package helper
 
 import (
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/spf13/cast"
+
+	"app/pkg/jwtkey"
 )
 
+// TokenInfo is the authorized caller, a user of a token or an integration of an API key.
+// The API key fields are zero for the tokens.
 type TokenInfo struct {
 	UserID string `json:"user_id"`
 	Name   string `json:"name"`
-}
-
-// GenerateJWT ...
-func GenerateJWT(m map[string]interface{}, tokenExpireTime time.Duration, tokenSecretKey string) (tokenString string, err error) {
-
-	token := jwt.New(jwt.SigningMethodHS256)
-
-	claims := token.Claims.(jwt.MapClaims)
+	Role   string `json:"role"`
 
-	for key, value := range m {
-		claims[key] = value
-	}
-
-	claims["iat"] = time.Now().Unix()
-	claims["exp"] = time.Now().Add(tokenExpireTime).Unix()
-
-	tokenString, err = token.SignedString([]byte(tokenSecretKey))
-	if err != nil {
-		return "", err
-	}
+	APIKeyID int      `json:"api_key_id"`
+	StoreID  int      `json:"store_id"`
+	Scopes   []string `json:"scopes"`
+}
 
-	return tokenString, nil
+// GenerateJWT signs the claims m with the signing key of keys, valid for tokenExpireTime.
+func GenerateJWT(m map[string]interface{}, tokenExpireTime time.Duration, keys *jwtkey.KeySet) (tokenString string, err error) {
+	return keys.Sign(m, tokenExpireTime)
 }
 
-func ParseClaims(token string, secretKey string) (result TokenInfo, err error) {
-	var claims jwt.MapClaims
+func ParseClaims(token string, keys *jwtkey.KeySet) (result TokenInfo, err error) {
+	var claims map[string]interface{}
 
-	claims, err = ExtractClaims(token, secretKey)
-	fmt.Println("---------------------->", claims)
+	claims, err = ExtractClaims(token, keys)
 	if err != nil {
 		return result, err
 	}
@@ -52,32 +41,14 @@ func ParseClaims(token string, secretKey string) (result TokenInfo, err error) {
 	}
 
 	result.Name = cast.ToString(claims["name"])
+	result.Role = cast.ToString(claims["role"])
 
 	return
 }
 
-// ExtractClaims extracts claims from given token
-func ExtractClaims(tokenString string, tokenSecretKey string) (jwt.MapClaims, error) {
-	var (
-		token *jwt.Token
-		err   error
-	)
-
-	token, err = jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// check token signing method etc
-		return []byte(tokenSecretKey), nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !(ok && token.Valid) {
-		return nil, errors.New("invalid token")
-	}
-
-	return claims, nil
+// ExtractClaims verifies the token with the key of its kid and returns its claims
+func ExtractClaims(tokenString string, keys *jwtkey.KeySet) (map[string]interface{}, error) {
+	return keys.Parse(tokenString)
 }
 
 // ExtractToken checks and returns token part of input string
@@ -0,0 +1,160 @@
+// Package jwtkey signs and verifies the access tokens with a set of keys told apart by the
+// kid header. One key signs the new tokens, every key of the set verifies the tokens it
+// signed, and the public keys are published as a JWKS so other services can verify too.
+//
+// Rotating the signing key:
+//
+//  1. Create the new key, for example `openssl genpkey -algorithm ed25519 -out 2024-06.pem`,
+//     add it to JWT_KEYS and deploy. Every instance now verifies it and /.well-known/jwks.json
+//     publishes it, the old key still signs.
+//  2. Set JWT_SIGNING_KEY to the kid of the new key and deploy. The new tokens are signed
+//     with it, the tokens of the old key keep verifying.
+//  3. Once the last token of the old key expired (config.TimeExpiredAt after step 2), drop
+//     the old key from JWT_KEYS, or from SECRET_KEY for the "secret" key, and deploy.
+package jwtkey
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Algorithms
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+// SecretKeyID is the kid of the HS256 key made of SECRET_KEY. The tokens without a kid were
+// signed with it before the key set, they are verified with it while it is configured.
+const SecretKeyID = "secret"
+
+// Key is a key of the set. The sign key is nil for the keys that only verify: the public
+// keys and the keys being retired.
+type Key struct {
+	ID        string
+	Algorithm string
+
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// CanSign reports whether the key holds its private part.
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// KeySet holds the keys by kid and the one signing the new tokens.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	// order is the kid order of the config, the JWKS lists the keys in it
+	order []string
+}
+
+// NewKeySet returns a set of keys, signingID is the kid of the key signing the new tokens.
+func NewKeySet(signingID string, keys ...*Key) (*KeySet, error) {
+
+	set := &KeySet{
+		keys: map[string]*Key{},
+	}
+
+	for _, key := range keys {
+
+		if len(key.ID) == 0 {
+			return nil, errors.New("jwtkey: a key has no kid")
+		}
+
+		if _, ok := set.keys[key.ID]; ok {
+			return nil, fmt.Errorf("jwtkey: kid %q is used twice", key.ID)
+		}
+
+		if key.method() == nil {
+			return nil, fmt.Errorf("jwtkey: key %q: unknown algorithm %q", key.ID, key.Algorithm)
+		}
+
+		set.keys[key.ID] = key
+		set.order = append(set.order, key.ID)
+	}
+
+	signing, ok := set.keys[signingID]
+	if !ok {
+		return nil, fmt.Errorf("jwtkey: signing key %q is not in the key set", signingID)
+	}
+
+	if !signing.CanSign() {
+		return nil, fmt.Errorf("jwtkey: signing key %q has no private key", signingID)
+	}
+
+	set.signing = signing
+
+	return set, nil
+}
+
+// Sign returns a token of claims signed with the signing key, with iat and exp set from ttl.
+func (s *KeySet) Sign(claims map[string]interface{}, ttl time.Duration) (string, error) {
+
+	mapClaims := jwt.MapClaims{}
+	for key, value := range claims {
+		mapClaims[key] = value
+	}
+
+	now := time.Now()
+	mapClaims["iat"] = now.Unix()
+	mapClaims["exp"] = now.Add(ttl).Unix()
+
+	token := jwt.NewWithClaims(s.signing.method(), mapClaims)
+	token.Header["kid"] = s.signing.ID
+
+	return token.SignedString(s.signing.signKey)
+}
+
+// Parse verifies token and returns its claims. The key is picked by the kid header and the
+// alg header must be the algorithm of that key, so a token can not be verified with a key of
+// another kind, like an HS256 token signed with the text of a public RSA key.
+func (s *KeySet) Parse(tokenString string) (map[string]interface{}, error) {
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			if _, set := token.Header["kid"]; set {
+				return nil, errors.New("kid is not a string")
+			}
+			kid = SecretKeyID
+		}
+
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("key %q does not verify %s tokens", kid, token.Method.Alg())
+		}
+
+		return key.verifyKey, nil
+	}, jwt.WithValidMethods([]string{HS256, RS256, EdDSA}))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !(ok && token.Valid) {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// Signing returns the key signing the new tokens.
+func (s *KeySet) Signing() *Key {
+	return s.signing
+}
@@ -0,0 +1,141 @@
+package jwtkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestRotationKeepsOldTokensValid(t *testing.T) {
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := NewKey("2024-01", RS256, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := NewKey("2024-06", EdDSA, edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := NewKeySet(old.ID, old, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldToken, err := before.Sign(map[string]interface{}{"Id": "1"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := NewKeySet(next.ID, old, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newToken, err := after.Sign(map[string]interface{}{"Id": "2"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for token, id := range map[string]string{oldToken: "1", newToken: "2"} {
+		claims, err := after.Parse(token)
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		if claims["Id"] != id {
+			t.Errorf("Parse: Id = %v, want %s", claims["Id"], id)
+		}
+	}
+
+	// once the old key is dropped its tokens stop verifying
+	retired, err := NewKeySet(next.ID, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = retired.Parse(oldToken); err == nil {
+		t.Error("Parse: the token of a dropped key verified")
+	}
+
+	jwks := after.JWKS()
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Kty != "RSA" || jwks.Keys[1].Crv != "Ed25519" {
+		t.Errorf("JWKS = %+v, want the RSA and the Ed25519 key", jwks.Keys)
+	}
+}
+
+func TestParseRejectsAlgorithmConfusion(t *testing.T) {
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := NewKey("rsa", RS256, &rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := NewKeySet(SecretKeyID, NewHMACKey(SecretKeyID, []byte("secret-key")), public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if jwks := set.JWKS(); len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "rsa" {
+		t.Errorf("JWKS = %+v, want only the RSA key", jwks.Keys)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	sign := func(method jwt.SigningMethod, kid interface{}, key interface{}) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"Id": "1", "exp": time.Now().Add(time.Hour).Unix()})
+		if kid != nil {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	for name, token := range map[string]string{
+		"HS256 with the public RSA key": sign(jwt.SigningMethodHS256, "rsa", publicPEM),
+		"HS256 with the public RSA der": sign(jwt.SigningMethodHS256, "rsa", der),
+		"unknown kid":                   sign(jwt.SigningMethodHS256, "other", []byte("secret-key")),
+		"none":                          sign(jwt.SigningMethodNone, SecretKeyID, jwt.UnsafeAllowNoneSignatureType),
+		"wrong secret":                  sign(jwt.SigningMethodHS256, nil, []byte("other-key")),
+	} {
+		if _, err := set.Parse(token); err == nil {
+			t.Errorf("Parse %s: the token verified", name)
+		}
+	}
+
+	// the tokens signed before the key set have no kid, they are verified with SECRET_KEY
+	claims, err := set.Parse(sign(jwt.SigningMethodHS256, nil, []byte("secret-key")))
+	if err != nil || claims["Id"] != "1" {
+		t.Errorf("Parse token without kid = %v, %v", claims, err)
+	}
+
+	_, err = NewKeySet("rsa", public)
+	if err == nil || !strings.Contains(err.Error(), "no private key") {
+		t.Errorf("NewKeySet with a public signing key: err = %v", err)
+	}
+}
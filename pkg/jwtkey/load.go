@@ -0,0 +1,142 @@
+package jwtkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"app/config"
+)
+
+// Load returns the key set of the config: the HS256 key of SECRET_KEY, when it is set, and
+// the keys of JWT_KEYS, signed with the key of JWT_SIGNING_KEY.
+func Load(cfg *config.Config) (*KeySet, error) {
+
+	var keys []*Key
+
+	if len(cfg.SecretKey) > 0 {
+		keys = append(keys, NewHMACKey(SecretKeyID, []byte(cfg.SecretKey)))
+	}
+
+	for _, entry := range strings.Split(cfg.JWTKeys, ",") {
+
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("jwtkey: JWT_KEYS entry %q is not kid:algorithm:file", entry)
+		}
+
+		key, err := ReadKey(parts[0], parts[1], parts[2])
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(cfg.JWTSigningKey, keys...)
+}
+
+// NewHMACKey returns an HS256 key, it signs and verifies with the same secret.
+func NewHMACKey(id string, secret []byte) *Key {
+	return &Key{
+		ID:        id,
+		Algorithm: HS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}
+}
+
+// NewKey returns an RS256 or EdDSA key from a private key, which signs, or a public key,
+// which only verifies.
+func NewKey(id string, algorithm string, key interface{}) (*Key, error) {
+
+	k := &Key{
+		ID:        id,
+		Algorithm: algorithm,
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		k.signKey, k.verifyKey = key, &key.PublicKey
+	case *rsa.PublicKey:
+		k.verifyKey = key
+	case ed25519.PrivateKey:
+		k.signKey, k.verifyKey = key, key.Public()
+	case ed25519.PublicKey:
+		k.verifyKey = key
+	default:
+		return nil, fmt.Errorf("jwtkey: key %q: unsupported key type %T", id, key)
+	}
+
+	var matches bool
+	switch algorithm {
+	case RS256:
+		_, matches = k.verifyKey.(*rsa.PublicKey)
+	case EdDSA:
+		_, matches = k.verifyKey.(ed25519.PublicKey)
+	}
+
+	if !matches {
+		return nil, fmt.Errorf("jwtkey: key %q is not a %s key", id, algorithm)
+	}
+
+	return k, nil
+}
+
+// ReadKey reads the key of algorithm from file. An HS256 file holds the secret, an RS256 or
+// EdDSA file holds a PEM private key or, for the keys that only verify, a PEM public key.
+func ReadKey(id string, algorithm string, file string) (*Key, error) {
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkey: key %q: %w", id, err)
+	}
+
+	if algorithm == HS256 {
+
+		secret := []byte(strings.TrimSpace(string(data)))
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("jwtkey: key %q: %s is empty", id, file)
+		}
+
+		return NewHMACKey(id, secret), nil
+	}
+
+	key, err := parsePEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkey: key %q: %s: %w", id, file, err)
+	}
+
+	return NewKey(id, algorithm, key)
+}
+
+func parsePEM(data []byte) (interface{}, error) {
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
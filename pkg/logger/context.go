@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"context"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request id.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request id carried by ctx, empty when there is none.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// RequestIDField is the request_id field of a log line.
+func RequestIDField(ctx context.Context) Field {
+	return String("request_id", RequestID(ctx))
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are masked wherever they appear, keys containing "password" are masked too.
+// key is the API key in the response of its creation.
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"token":         true,
+	"access_token":  true,
+	"secret":        true,
+	"secret_key":    true,
+	"x-api-key":     true,
+	"key":           true,
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	return sensitiveKeys[key] || strings.Contains(key, "password")
+}
+
+// Redact returns a copy of v, as decoded JSON, with the values of the sensitive keys masked.
+func Redact(v interface{}) interface{} {
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var decoded interface{}
+
+	err = json.Unmarshal(body, &decoded)
+	if err != nil {
+		return v
+	}
+
+	return redactValue(decoded)
+}
+
+func redactValue(v interface{}) interface{} {
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if isSensitive(key) {
+				value[key] = redacted
+				continue
+			}
+			value[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+
+	return v
+}
+
+// RedactHeaders returns the headers with the values of the sensitive ones masked.
+func RedactHeaders(header http.Header) map[string]string {
+
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		if isSensitive(key) {
+			headers[key] = redacted
+			continue
+		}
+		headers[key] = strings.Join(values, ", ")
+	}
+
+	return headers
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input interface{}
+		Want  interface{}
+	}{
+		{
+			Name:  "top level keys",
+			Input: map[string]interface{}{"login": "admin", "token": "abc", "Secret": "s"},
+			Want:  map[string]interface{}{"login": "admin", "token": redacted, "Secret": redacted},
+		},
+		{
+			Name:  "password-like keys",
+			Input: map[string]interface{}{"password": "p", "NewPassword": "p", "old_password_hash": "h", "pass": "kept"},
+			Want:  map[string]interface{}{"password": redacted, "NewPassword": redacted, "old_password_hash": redacted, "pass": "kept"},
+		},
+		{
+			Name: "nested maps and lists",
+			Input: map[string]interface{}{
+				"Data": map[string]interface{}{
+					"key":     "sk_live",
+					"api_key": map[string]interface{}{"prefix": "sk_li", "name": "erp"},
+					"users":   []interface{}{map[string]interface{}{"name": "a", "Password": "p"}},
+				},
+			},
+			Want: map[string]interface{}{
+				"Data": map[string]interface{}{
+					"key":     redacted,
+					"api_key": map[string]interface{}{"prefix": "sk_li", "name": "erp"},
+					"users":   []interface{}{map[string]interface{}{"name": "a", "Password": redacted}},
+				},
+			},
+		},
+		{
+			Name: "structs by their JSON names",
+			Input: struct {
+				Name  string `json:"name"`
+				Token string `json:"access_token"`
+			}{Name: "a", Token: "t"},
+			Want: map[string]interface{}{"name": "a", "access_token": redacted},
+		},
+		{
+			Name:  "not an object",
+			Input: "password",
+			Want:  "password",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := Redact(test.Input); !reflect.DeepEqual(got, test.Want) {
+				t.Errorf("Redact = %#v, want %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer t")
+	header.Set("X-Api-Key", "sk_live")
+	header["cookie"] = []string{"session=1"}
+	header.Add("Accept", "text/plain")
+	header.Add("Accept", "application/json")
+
+	want := map[string]string{
+		"Authorization": redacted,
+		"X-Api-Key":     redacted,
+		"cookie":        redacted,
+		"Accept":        "text/plain, application/json",
+	}
+
+	if got := RedactHeaders(header); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactHeaders = %v, want %v", got, want)
+	}
+}
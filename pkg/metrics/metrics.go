@@ -0,0 +1,108 @@
+// Package metrics holds the Prometheus collectors of the service, they are served by GET /metrics.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "store"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// CacheRequests counts the cached reads by entity and result, hit or miss.
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cached reads by entity and result.",
+	}, []string{"entity", "result"})
+
+	// OrdersCreated counts the created orders.
+	OrdersCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Created orders.",
+	})
+
+	// OrderItemsSold counts the sold product units.
+	OrderItemsSold = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_items_sold_total",
+		Help:      "Product units sold through order items.",
+	})
+
+	// OrderReturns counts the order returns.
+	OrderReturns = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_returns_total",
+		Help:      "Order returns.",
+	})
+
+	// OrdersOverdue counts the orders flagged overdue and notified by the overdue job.
+	OrdersOverdue = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_overdue_total",
+		Help:      "Orders flagged overdue.",
+	})
+
+	// StockChanges counts the stock changes by ledger reason.
+	StockChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stock_changes_total",
+		Help:      "Stock changes by stock movement reason.",
+	}, []string{"reason"})
+
+	// WebhookDeliveries counts the webhook delivery attempts by the status they left the delivery in.
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook delivery attempts by resulting status: delivered, pending or failed.",
+	}, []string{"status"})
+
+	// OutboxPublished counts the outbox events published by the relay.
+	OutboxPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_published_total",
+		Help:      "Outbox events published.",
+	})
+)
+
+// Cache results
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// Middleware records the count and latency of every request by gin route.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		httpRequests.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(route, c.Request.Method).Observe(time.Since(start).Seconds())
+	}
+}
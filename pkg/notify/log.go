@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+
+	"app/pkg/logger"
+)
+
+// LogSink writes every notification to the service log.
+type LogSink struct {
+	log logger.LoggerI
+}
+
+func NewLogSink(log logger.LoggerI) *LogSink {
+	return &LogSink{
+		log: log,
+	}
+}
+
+func (s *LogSink) Notify(ctx context.Context, notification *Notification) error {
+
+	s.log.Warn(notification.Subject,
+		logger.String("kind", notification.Kind),
+		logger.Any("payload", notification.Payload),
+	)
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Notification kinds
+const (
+	KindOrderOverdue = "order.overdue"
+)
+
+type Notification struct {
+	Kind      string      `json:"kind"`
+	Subject   string      `json:"subject"`
+	Payload   interface{} `json:"payload"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// SinkI delivers notifications, implementations decide where they end up.
+type SinkI interface {
+	Notify(ctx context.Context, notification *Notification) error
+}
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartClient starts the span of a call to postgres or redis, attrs describe the call.
+func StartClient(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// RecordClient records a call that already ended after elapsed, for the hooks that
+// only learn about a call once it is done like the pgx logger.
+func RecordClient(ctx context.Context, name string, elapsed time.Duration, err error, attrs ...attribute.KeyValue) {
+
+	end := time.Now()
+
+	_, span := Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(end.Add(-elapsed)),
+		trace.WithAttributes(attrs...),
+	)
+
+	Fail(span, err)
+	span.End(trace.WithTimestamp(end))
+}
+
+// Fail marks span failed when err is not nil.
+func Fail(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
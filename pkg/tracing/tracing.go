@@ -0,0 +1,110 @@
+// Package tracing sets up the OpenTelemetry tracer provider of the service and starts
+// the spans of the HTTP requests, the postgres queries and the redis calls.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"app/config"
+)
+
+const (
+	ExporterFile   = "file"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+	ExporterNone   = "none"
+
+	serviceName = "store"
+	tracerName  = "app"
+)
+
+// Enabled reports whether the spans are exported, with the none exporter they are never recorded.
+func Enabled(cfg *config.Config) bool {
+	return cfg.TracingExporter != ExporterNone
+}
+
+// Tracer returns the tracer of the service, it is a no-op until Init has set the provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Init sets the global tracer provider and propagator, the returned shutdown flushes
+// the buffered spans and closes the exporter.
+func Init(cfg *config.Config) (shutdown func(context.Context) error, err error) {
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !Enabled(cfg) {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeErr := closeOutput(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
+// newExporter returns the exporter of cfg.TracingExporter and the closer of the file it writes to.
+func newExporter(cfg *config.Config) (sdktrace.SpanExporter, func() error, error) {
+
+	noClose := func() error { return nil }
+
+	switch cfg.TracingExporter {
+	case ExporterFile:
+		file, err := os.OpenFile(cfg.TracingFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+
+		return exporter, file.Close, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, noClose, err
+	case ExporterOTLP:
+		exporter, err := otlptracehttp.New(context.Background(),
+			otlptracehttp.WithEndpoint(cfg.TracingOTLPEndpoint),
+			otlptracehttp.WithInsecure(),
+		)
+		return exporter, noClose, err
+	default:
+		return nil, nil, fmt.Errorf("tracing: unknown exporter %q", cfg.TracingExporter)
+	}
+}
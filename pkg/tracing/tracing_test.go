@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"app/config"
+)
+
+func TestInit(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "traces.json")
+
+	tests := []struct {
+		Name     string
+		Exporter string
+		WantErr  bool
+	}{
+		{Name: "none", Exporter: ExporterNone},
+		{Name: "file", Exporter: ExporterFile},
+		{Name: "unknown", Exporter: "jaeger", WantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			cfg := &config.Config{TracingExporter: test.Exporter, TracingFile: file, TracingSampleRatio: 1}
+
+			shutdown, err := Init(cfg)
+			if test.WantErr {
+				if err == nil {
+					t.Error("Init: want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Init: %v", err)
+			}
+
+			_, span := Tracer().Start(context.Background(), "span of "+test.Name)
+			span.End()
+
+			err = shutdown(context.Background())
+			if err != nil {
+				t.Fatalf("shutdown: %v", err)
+			}
+
+			if Enabled(cfg) != (test.Exporter != ExporterNone) {
+				t.Errorf("Enabled = %v", Enabled(cfg))
+			}
+		})
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "span of file") || strings.Contains(string(data), "span of none") {
+		t.Errorf("the file has the spans of the file exporter only, got:\n%s", data)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+
+	// Init sets the propagator whatever the exporter
+	_, err := Init(&config.Config{TracingExporter: ExporterNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otel.SetTracerProvider(provider)
+
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/order/:id", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/order/7", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	span := spans[0]
+
+	if span.Name() != "GET /order/:id" {
+		t.Errorf("name = %q, want the route", span.Name())
+	}
+
+	if span.SpanContext().TraceID().String() != traceID || span.Parent().SpanID().String() != parentID {
+		t.Errorf("the span does not continue the traceparent: trace %s, parent %s", span.SpanContext().TraceID(), span.Parent().SpanID())
+	}
+
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %v, want an error for the 500", span.Status())
+	}
+}
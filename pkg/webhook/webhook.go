@@ -0,0 +1,152 @@
+// Package webhook sends the webhook deliveries and signs them so the receivers can
+// check that they come from the service.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+
+	// drainLimit is how much of the receiver response is read, and thrown away, so the
+	// connection is reused
+	drainLimit = 4096
+)
+
+// Event is the body of a delivery.
+type Event struct {
+	Delivery_id int             `json:"delivery_id"`
+	Event_type  string          `json:"event_type"`
+	CreatedAt   string          `json:"created_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Sign returns the signature header of body sent at timestamp, "t=<unix>,v1=<hex>" where
+// v1 is the HMAC-SHA256 of "<unix>.<body>" keyed with the subscription secret.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	return "t=" + unix + ",v1=" + mac(secret, unix, body)
+}
+
+// Verify checks a signature header made by Sign, receivers written in Go can use it.
+func Verify(secret, signature string, body []byte, tolerance time.Duration) error {
+
+	var unix, sum string
+	for _, part := range strings.Split(signature, ",") {
+		switch {
+		case strings.HasPrefix(part, "t="):
+			unix = strings.TrimPrefix(part, "t=")
+		case strings.HasPrefix(part, "v1="):
+			sum = strings.TrimPrefix(part, "v1=")
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return fmt.Errorf("webhook: invalid signature timestamp")
+	}
+
+	if tolerance > 0 && time.Since(time.Unix(seconds, 0)).Abs() > tolerance {
+		return fmt.Errorf("webhook: signature timestamp out of tolerance")
+	}
+
+	if !hmac.Equal([]byte(sum), []byte(mac(secret, unix, body))) {
+		return fmt.Errorf("webhook: signature mismatch")
+	}
+
+	return nil
+}
+
+func mac(secret, unix string, body []byte) string {
+
+	hash := hmac.New(sha256.New, []byte(secret))
+	hash.Write([]byte(unix + "."))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Sender posts the deliveries to the subscription URLs. It does not follow redirects and,
+// unless it allows the private networks, does not connect to the private, loopback and
+// link-local addresses.
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(timeout time.Duration, allowPrivate bool) *Sender {
+
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = dialControl
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				// no proxy: the dialled address is the receiver's, the one dialControl checks
+				Proxy:                 nil,
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   timeout,
+				ResponseHeaderTimeout: timeout,
+				MaxIdleConnsPerHost:   2,
+				IdleConnTimeout:       90 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts event signed with secret and returns the response status code, any status
+// other than 2xx is an error.
+func (s *Sender) Send(ctx context.Context, url, secret string, event *Event) (int, error) {
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Event_type)
+	req.Header.Set(DeliveryHeader, strconv.Itoa(event.Delivery_id))
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// the response is not kept, the delivery log would hand it to whoever reads the log
+	io.Copy(io.Discard, io.LimitReader(resp.Body, drainLimit))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook: receiver answered %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckURL(t *testing.T) {
+
+	tests := []struct {
+		Name  string
+		Url   string
+		Valid bool
+	}{
+		{Name: "public address", Url: "https://93.184.216.34/hook", Valid: true},
+		{Name: "not http", Url: "ftp://93.184.216.34/hook"},
+		{Name: "relative", Url: "/hook"},
+		{Name: "loopback", Url: "http://127.0.0.1:8080/hook"},
+		{Name: "localhost", Url: "http://localhost/hook"},
+		{Name: "ipv6 loopback", Url: "http://[::1]/hook"},
+		{Name: "private", Url: "http://10.0.0.5/hook"},
+		{Name: "metadata", Url: "http://169.254.169.254/latest/meta-data"},
+		{Name: "unspecified", Url: "http://0.0.0.0/hook"},
+		{Name: "shared address space", Url: "http://100.64.0.1/hook"},
+		{Name: "ipv4 mapped loopback", Url: "http://[::ffff:127.0.0.1]/hook"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			err := CheckURL(context.Background(), test.Url, false)
+			if test.Valid != (err == nil) {
+				t.Errorf("CheckURL(%s) = %v", test.Url, err)
+			}
+		})
+	}
+}
+
+func TestSenderRefusesPrivateAddressesAndRedirects(t *testing.T) {
+
+	var hits int
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		hits++
+
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/internal", http.StatusFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("internal secret"))
+	}))
+	defer receiver.Close()
+
+	event := &Event{Delivery_id: 1, Event_type: "order.created", Data: []byte(`{}`)}
+
+	_, err := NewSender(time.Second, false).Send(context.Background(), receiver.URL, "secret", event)
+	if !errors.Is(err, ErrForbiddenAddress) || hits != 0 {
+		t.Errorf("loopback receiver: err %v after %d requests, want ErrForbiddenAddress before any", err, hits)
+	}
+
+	sender := NewSender(time.Second, true)
+
+	code, err := sender.Send(context.Background(), receiver.URL+"/redirect", "secret", event)
+	if code != http.StatusFound || hits != 1 {
+		t.Errorf("redirect: got %d after %d requests, want 302 without following it", code, hits)
+	}
+
+	code, err = sender.Send(context.Background(), receiver.URL, "secret", event)
+	if code != http.StatusInternalServerError || err == nil || strings.Contains(err.Error(), "internal secret") {
+		t.Errorf("error response: got %d, %v, want 500 without the body", code, err)
+	}
+}
@@ -1,17 +1,45 @@
 package storage
 
 import (
-	"app/api/models"
+	"context"
+	"errors"
+	"time"
 )
 
+// ErrCacheUnavailable is returned by the cache while it is not reachable.
+var ErrCacheUnavailable = errors.New("cache is unavailable")
+
 type CacheStorageI interface {
 	CloseDB()
-	ProductCache() ProductCachaRepoI
+	Ping() error
+	// Status reports the state of the cache connection: up, down or recovering.
+	Status() string
+	Cache() CacheRepoI
+	Limiter() LimiterRepoI
+}
+
+// CacheRepoI stores JSON encoded values by key.
+type CacheRepoI interface {
+	// Get decodes the value of key into dest and reports whether the key exists.
+	Get(ctx context.Context, key string, dest interface{}) (bool, error)
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// Version returns the current cache version of the entity, 0 when it was never bumped.
+	Version(ctx context.Context, entity string) (int64, error)
+	// BumpVersion increments the cache version of every entity.
+	BumpVersion(ctx context.Context, entities ...string) error
 }
 
-type ProductCachaRepoI interface {
-	Create(req *models.GetListProductResponse) error
-	GetList() (*models.GetListProductResponse, error)
-	Delete() error
-	Exists() (bool, error)
+// LimiterRepoI counts the requests of the rate limiter and the failed logins of the lockout.
+type LimiterRepoI interface {
+	// Hit counts a request of key in the current fixed window and returns the count so far
+	// and the time left until the window resets.
+	Hit(ctx context.Context, key string, window time.Duration) (count int64, reset time.Duration, err error)
+	// AddLoginFailure counts a failed login of login, the count is forgotten after ttl without failures.
+	AddLoginFailure(ctx context.Context, login string, ttl time.Duration) (int64, error)
+	LockLogin(ctx context.Context, login string, duration time.Duration) error
+	// LoginLock returns how long login stays locked, 0 when it is not locked.
+	LoginLock(ctx context.Context, login string) (time.Duration, error)
+	// ResetLogin forgets the failed logins of login after a successful one.
+	ResetLogin(ctx context.Context, login string) error
 }
@@ -0,0 +1,174 @@
+// Package cachesync keeps the cached read responses in step with the data.
+//
+// Every cache key carries the version of its entity. The Storage wrapper bumps the
+// versions after each successful mutating repo call, so the next read misses and
+// loads fresh data while the entries of older versions expire by their TTL.
+package cachesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/storage"
+)
+
+// Cached entities
+const (
+	Product  = "product"
+	Brand    = "brand"
+	Category = "category"
+	Store    = "store"
+	Stock    = "stock"
+)
+
+// Entities are all the cached entities.
+var Entities = []string{Product, Brand, Category, Store, Stock}
+
+// dependents lists the entities whose cached responses embed the data of an entity,
+// a change of the entity invalidates them too.
+var dependents = map[string][]string{
+	Brand:    {Product},
+	Category: {Product},
+	Product:  {Stock},
+	Store:    {Stock},
+}
+
+// affected returns the entity with all the entities depending on it.
+func affected(entity string) []string {
+
+	entities := []string{entity}
+	for i := 0; i < len(entities); i++ {
+		entities = append(entities, dependents[entities[i]]...)
+	}
+
+	return entities
+}
+
+// Key builds the cache key of the entity at the given version.
+func Key(entity string, version int64, key string) string {
+	return fmt.Sprintf("%s:v%d:%s", entity, version, key)
+}
+
+// KeyByID builds the key of a GetByID response, parts are the primary key columns.
+func KeyByID(parts ...interface{}) string {
+
+	key := "id"
+	for _, part := range parts {
+		key += fmt.Sprintf(":%v", part)
+	}
+
+	return key
+}
+
+// ReadThrough decodes the cached value of key into dest, on a miss it calls load, which must
+// fill dest, and caches the result for ttl. Cache errors are logged and never fail the read,
+// when the version can not be read the cache is skipped.
+func ReadThrough(ctx context.Context, cache storage.CacheRepoI, log logger.LoggerI, entity, key string, ttl time.Duration, dest interface{}, load func() error) error {
+
+	version, err := cache.Version(ctx, entity)
+	if err != nil {
+		logCacheError(log, "cache.version", entity, err)
+		metrics.CacheRequests.WithLabelValues(entity, metrics.CacheMiss).Inc()
+		return load()
+	}
+
+	key = Key(entity, version, key)
+
+	ok, err := cache.Get(ctx, key, dest)
+	if err != nil {
+		logCacheError(log, "cache.get", key, err)
+	}
+
+	if ok && err == nil {
+		metrics.CacheRequests.WithLabelValues(entity, metrics.CacheHit).Inc()
+		return nil
+	}
+
+	metrics.CacheRequests.WithLabelValues(entity, metrics.CacheMiss).Inc()
+
+	err = load()
+	if err != nil {
+		return err
+	}
+
+	err = cache.Set(ctx, key, dest, ttl)
+	if err != nil {
+		logCacheError(log, "cache.set", key, err)
+	}
+
+	return nil
+}
+
+// logCacheError logs at debug level while the cache is known to be down, to not flood the log on every read.
+func logCacheError(log logger.LoggerI, msg, key string, err error) {
+
+	if errors.Is(err, storage.ErrCacheUnavailable) {
+		log.Debug(msg, logger.String("key", key), logger.Error(err))
+		return
+	}
+
+	log.Warn(msg, logger.String("key", key), logger.Error(err))
+}
+
+// Storage wraps the storage and invalidates the cached responses after every successful mutation.
+type Storage struct {
+	storage.StorageI
+	cache storage.CacheRepoI
+	log   logger.LoggerI
+}
+
+func NewStorage(store storage.StorageI, cache storage.CacheRepoI, log logger.LoggerI) *Storage {
+	return &Storage{
+		StorageI: store,
+		cache:    cache,
+		log:      log,
+	}
+}
+
+// invalidate bumps the version of the entity and of its dependents when the mutation succeeded.
+// When the bump fails the stale entries are still dropped once their TTL expires.
+func (s *Storage) invalidate(ctx context.Context, err error, entity string) {
+
+	if err != nil {
+		return
+	}
+
+	entities := affected(entity)
+
+	err = s.cache.BumpVersion(ctx, entities...)
+	if err != nil {
+		logCacheError(s.log, "cache.invalidate", entity, err)
+	}
+}
+
+func (s *Storage) Category() storage.CategoryRepoI {
+	return &categoryRepo{CategoryRepoI: s.StorageI.Category(), store: s}
+}
+
+func (s *Storage) Brand() storage.BrandRepoI {
+	return &brandRepo{BrandRepoI: s.StorageI.Brand(), store: s}
+}
+
+func (s *Storage) Product() storage.ProductRepoI {
+	return &productRepo{ProductRepoI: s.StorageI.Product(), store: s}
+}
+
+func (s *Storage) Store() storage.StoreRepoI {
+	return &storeRepo{StoreRepoI: s.StorageI.Store(), store: s}
+}
+
+func (s *Storage) Stock() storage.StockRepoI {
+	return &stockRepo{StockRepoI: s.StorageI.Stock(), store: s}
+}
+
+func (s *Storage) Order() storage.OrderRepoI {
+	return &orderRepo{OrderRepoI: s.StorageI.Order(), store: s}
+}
+
+func (s *Storage) Admin() storage.AdminRepoI {
+	return &adminRepo{AdminRepoI: s.StorageI.Admin(), store: s}
+}
@@ -0,0 +1,311 @@
+package cachesync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"app/api/models"
+	"app/pkg/logger"
+	"app/storage"
+)
+
+const testTTL = time.Minute
+
+type fakeEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// fakeCache is an in memory CacheRepoI with a manual clock.
+type fakeCache struct {
+	now      time.Time
+	entries  map[string]fakeEntry
+	versions map[string]int64
+	bumpErr  error
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{
+		now:      time.Now(),
+		entries:  map[string]fakeEntry{},
+		versions: map[string]int64{},
+	}
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now.Before(entry.expiresAt) {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(entry.body, dest)
+}
+
+func (c *fakeCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.entries[key] = fakeEntry{body: body, expiresAt: c.now.Add(ttl)}
+
+	return nil
+}
+
+func (c *fakeCache) Delete(ctx context.Context, keys ...string) error {
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+
+	return nil
+}
+
+func (c *fakeCache) Version(ctx context.Context, entity string) (int64, error) {
+	return c.versions[entity], nil
+}
+
+func (c *fakeCache) BumpVersion(ctx context.Context, entities ...string) error {
+
+	if c.bumpErr != nil {
+		return c.bumpErr
+	}
+
+	for _, entity := range entities {
+		c.versions[entity]++
+	}
+
+	return nil
+}
+
+// fakeDB holds the rows the fake repos read and write.
+type fakeDB struct {
+	brandName   string
+	productName string
+	quantity    int
+	updateErr   error
+}
+
+type fakeStorage struct {
+	storage.StorageI
+	db *fakeDB
+}
+
+func (s *fakeStorage) Brand() storage.BrandRepoI     { return &fakeBrandRepo{db: s.db} }
+func (s *fakeStorage) Product() storage.ProductRepoI { return &fakeProductRepo{db: s.db} }
+func (s *fakeStorage) Stock() storage.StockRepoI     { return &fakeStockRepo{db: s.db} }
+func (s *fakeStorage) Order() storage.OrderRepoI     { return &fakeOrderRepo{db: s.db} }
+
+type fakeBrandRepo struct {
+	storage.BrandRepoI
+	db *fakeDB
+}
+
+func (r *fakeBrandRepo) Update(ctx context.Context, req *models.UpdateBrand) (int64, error) {
+	r.db.brandName = req.Brand_name
+	return 1, nil
+}
+
+type fakeProductRepo struct {
+	storage.ProductRepoI
+	db *fakeDB
+}
+
+func (r *fakeProductRepo) GetByID(ctx context.Context, req *models.ProductPrimaryKey) (*models.Product, error) {
+	return &models.Product{
+		Product_id:   req.Product_id,
+		Product_name: r.db.productName,
+		BrandData:    &models.Brand{Brand_name: r.db.brandName},
+	}, nil
+}
+
+func (r *fakeProductRepo) Update(ctx context.Context, req *models.UpdateProduct) (int64, error) {
+
+	if r.db.updateErr != nil {
+		return 0, r.db.updateErr
+	}
+
+	r.db.productName = req.Product_name
+
+	return 1, nil
+}
+
+type fakeStockRepo struct {
+	storage.StockRepoI
+	db *fakeDB
+}
+
+func (r *fakeStockRepo) GetByID(ctx context.Context, req *models.StockPrimaryKey) (*models.Stock, error) {
+	return &models.Stock{Store_id: req.Store_id, Product_id: req.Product_id, Quantity: r.db.quantity}, nil
+}
+
+type fakeOrderRepo struct {
+	storage.OrderRepoI
+	db *fakeDB
+}
+
+func (r *fakeOrderRepo) AddOrderItem(ctx context.Context, req *models.OrderItem) (string, error) {
+	r.db.quantity -= int(req.Quantity)
+	return "1", nil
+}
+
+type testEnv struct {
+	cache *fakeCache
+	db    *fakeDB
+	store *Storage
+	loads int
+}
+
+func newTestEnv() *testEnv {
+
+	env := &testEnv{
+		cache: newFakeCache(),
+		db:    &fakeDB{brandName: "Trek", productName: "Bike", quantity: 10},
+	}
+	env.store = NewStorage(&fakeStorage{db: env.db}, env.cache, logger.NewLogger("test", logger.LevelError))
+
+	return env
+}
+
+func (env *testEnv) getProduct(t *testing.T) *models.Product {
+
+	var resp *models.Product
+
+	err := ReadThrough(context.Background(), env.cache, env.store.log, Product, "id:1", testTTL, &resp, func() (err error) {
+		env.loads++
+		resp, err = env.store.Product().GetByID(context.Background(), &models.ProductPrimaryKey{Product_id: 1})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("read product: %v", err)
+	}
+
+	return resp
+}
+
+func (env *testEnv) getStock(t *testing.T) *models.Stock {
+
+	var resp *models.Stock
+
+	err := ReadThrough(context.Background(), env.cache, env.store.log, Stock, "id:1:1", testTTL, &resp, func() (err error) {
+		env.loads++
+		resp, err = env.store.Stock().GetByID(context.Background(), &models.StockPrimaryKey{Store_id: 1, Product_id: 1})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("read stock: %v", err)
+	}
+
+	return resp
+}
+
+func TestReadThroughServesCachedValue(t *testing.T) {
+
+	env := newTestEnv()
+
+	env.getProduct(t)
+	env.db.productName = "changed behind the cache"
+
+	got := env.getProduct(t)
+	if got.Product_name != "Bike" || env.loads != 1 {
+		t.Errorf("got: %s after %d loads, expected: Bike after 1 load", got.Product_name, env.loads)
+	}
+}
+
+func TestMutationInvalidatesEntity(t *testing.T) {
+
+	env := newTestEnv()
+
+	env.getProduct(t)
+
+	_, err := env.store.Product().Update(context.Background(), &models.UpdateProduct{Product_id: 1, Product_name: "Road Bike"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := env.getProduct(t)
+	if got.Product_name != "Road Bike" {
+		t.Errorf("got: %s, expected: Road Bike", got.Product_name)
+	}
+}
+
+func TestMutationInvalidatesDependents(t *testing.T) {
+
+	env := newTestEnv()
+
+	env.getProduct(t)
+
+	_, err := env.store.Brand().Update(context.Background(), &models.UpdateBrand{Brand_id: 1, Brand_name: "Giant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := env.getProduct(t)
+	if got.BrandData.Brand_name != "Giant" {
+		t.Errorf("got: %s, expected: Giant", got.BrandData.Brand_name)
+	}
+
+	if env.cache.versions[Stock] != 1 {
+		t.Errorf("stock version got: %d, expected: 1", env.cache.versions[Stock])
+	}
+}
+
+func TestOrderItemInvalidatesStock(t *testing.T) {
+
+	env := newTestEnv()
+
+	env.getStock(t)
+
+	_, err := env.store.Order().AddOrderItem(context.Background(), &models.OrderItem{Order_id: 1, Product_id: 1, Quantity: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := env.getStock(t)
+	if got.Quantity != 7 {
+		t.Errorf("got: %d, expected: 7", got.Quantity)
+	}
+}
+
+func TestFailedMutationKeepsVersion(t *testing.T) {
+
+	env := newTestEnv()
+	env.db.updateErr = errors.New("update failed")
+
+	_, err := env.store.Product().Update(context.Background(), &models.UpdateProduct{Product_id: 1, Product_name: "Road Bike"})
+	if err == nil {
+		t.Fatal("expected the update error")
+	}
+
+	if env.cache.versions[Product] != 0 {
+		t.Errorf("product version got: %d, expected: 0", env.cache.versions[Product])
+	}
+}
+
+func TestStalenessBoundedByTTL(t *testing.T) {
+
+	env := newTestEnv()
+	env.cache.bumpErr = errors.New("redis is down")
+
+	env.getProduct(t)
+
+	_, err := env.store.Product().Update(context.Background(), &models.UpdateProduct{Product_id: 1, Product_name: "Road Bike"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env.cache.now = env.cache.now.Add(testTTL - time.Second)
+	if got := env.getProduct(t); got.Product_name != "Bike" {
+		t.Errorf("within ttl got: %s, expected the stale Bike", got.Product_name)
+	}
+
+	env.cache.now = env.cache.now.Add(time.Second)
+	if got := env.getProduct(t); got.Product_name != "Road Bike" {
+		t.Errorf("after ttl got: %s, expected: Road Bike", got.Product_name)
+	}
+}
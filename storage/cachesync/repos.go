@@ -0,0 +1,210 @@
+package cachesync
+
+import (
+	"context"
+
+	"app/api/models"
+	"app/storage"
+)
+
+type categoryRepo struct {
+	storage.CategoryRepoI
+	store *Storage
+}
+
+func (r *categoryRepo) Create(ctx context.Context, req *models.CreateCategory) (string, error) {
+	id, err := r.CategoryRepoI.Create(ctx, req)
+	r.store.invalidate(ctx, err, Category)
+	return id, err
+}
+
+func (r *categoryRepo) Update(ctx context.Context, req *models.UpdateCategory) (int64, error) {
+	rows, err := r.CategoryRepoI.Update(ctx, req)
+	r.store.invalidate(ctx, err, Category)
+	return rows, err
+}
+
+func (r *categoryRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64, error) {
+	rows, err := r.CategoryRepoI.Patch(ctx, req)
+	r.store.invalidate(ctx, err, Category)
+	return rows, err
+}
+
+func (r *categoryRepo) Delete(ctx context.Context, req *models.CategoryPrimaryKey) (int64, error) {
+	rows, err := r.CategoryRepoI.Delete(ctx, req)
+	r.store.invalidate(ctx, err, Category)
+	return rows, err
+}
+
+type brandRepo struct {
+	storage.BrandRepoI
+	store *Storage
+}
+
+func (r *brandRepo) Create(ctx context.Context, req *models.CreateBrand) (string, error) {
+	id, err := r.BrandRepoI.Create(ctx, req)
+	r.store.invalidate(ctx, err, Brand)
+	return id, err
+}
+
+func (r *brandRepo) Update(ctx context.Context, req *models.UpdateBrand) (int64, error) {
+	rows, err := r.BrandRepoI.Update(ctx, req)
+	r.store.invalidate(ctx, err, Brand)
+	return rows, err
+}
+
+func (r *brandRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64, error) {
+	rows, err := r.BrandRepoI.Patch(ctx, req)
+	r.store.invalidate(ctx, err, Brand)
+	return rows, err
+}
+
+func (r *brandRepo) Delete(ctx context.Context, req *models.BrandPrimaryKey) (int64, error) {
+	rows, err := r.BrandRepoI.Delete(ctx, req)
+	r.store.invalidate(ctx, err, Brand)
+	return rows, err
+}
+
+type productRepo struct {
+	storage.ProductRepoI
+	store *Storage
+}
+
+func (r *productRepo) Create(ctx context.Context, req *models.CreateProduct) (string, error) {
+	id, err := r.ProductRepoI.Create(ctx, req)
+	r.store.invalidate(ctx, err, Product)
+	return id, err
+}
+
+func (r *productRepo) Update(ctx context.Context, req *models.UpdateProduct) (int64, error) {
+	rows, err := r.ProductRepoI.Update(ctx, req)
+	r.store.invalidate(ctx, err, Product)
+	return rows, err
+}
+
+func (r *productRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64, error) {
+	rows, err := r.ProductRepoI.Patch(ctx, req)
+	r.store.invalidate(ctx, err, Product)
+	return rows, err
+}
+
+func (r *productRepo) Delete(ctx context.Context, req *models.ProductPrimaryKey) (int64, error) {
+	rows, err := r.ProductRepoI.Delete(ctx, req)
+	r.store.invalidate(ctx, err, Product)
+	return rows, err
+}
+
+type storeRepo struct {
+	storage.StoreRepoI
+	store *Storage
+}
+
+func (r *storeRepo) Create(ctx context.Context, req *models.CreateStore) (string, error) {
+	id, err := r.StoreRepoI.Create(ctx, req)
+	r.store.invalidate(ctx, err, Store)
+	return id, err
+}
+
+func (r *storeRepo) Update(ctx context.Context, req *models.UpdateStore) (int64, error) {
+	rows, err := r.StoreRepoI.Update(ctx, req)
+	r.store.invalidate(ctx, err, Store)
+	return rows, err
+}
+
+func (r *storeRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64, error) {
+	rows, err := r.StoreRepoI.Patch(ctx, req)
+	r.store.invalidate(ctx, err, Store)
+	return rows, err
+}
+
+func (r *storeRepo) Delete(ctx context.Context, req *models.StorePrimaryKey) (int64, error) {
+	rows, err := r.StoreRepoI.Delete(ctx, req)
+	r.store.invalidate(ctx, err, Store)
+	return rows, err
+}
+
+type stockRepo struct {
+	storage.StockRepoI
+	store *Storage
+}
+
+func (r *stockRepo) Create(ctx context.Context, req *models.CreateStock) (*models.StockPrimaryKey, error) {
+	key, err := r.StockRepoI.Create(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return key, err
+}
+
+func (r *stockRepo) Update(ctx context.Context, req *models.UpdateStock) (int64, error) {
+	rows, err := r.StockRepoI.Update(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return rows, err
+}
+
+func (r *stockRepo) Patch(ctx context.Context, req *models.PatchStock) (int64, error) {
+	rows, err := r.StockRepoI.Patch(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return rows, err
+}
+
+func (r *stockRepo) Delete(ctx context.Context, req *models.DeleteStock) (int64, error) {
+	rows, err := r.StockRepoI.Delete(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return rows, err
+}
+
+func (r *stockRepo) Transfer(ctx context.Context, req *models.TransferStock) error {
+	err := r.StockRepoI.Transfer(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return err
+}
+
+// orderRepo invalidates the stock, the order item triggers move the stock quantities.
+type orderRepo struct {
+	storage.OrderRepoI
+	store *Storage
+}
+
+func (r *orderRepo) Delete(ctx context.Context, req *models.OrderPrimaryKey) (int64, error) {
+	rows, err := r.OrderRepoI.Delete(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return rows, err
+}
+
+func (r *orderRepo) AddOrderItem(ctx context.Context, req *models.OrderItem) (string, error) {
+	id, err := r.OrderRepoI.AddOrderItem(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return id, err
+}
+
+func (r *orderRepo) RemoveOrderItem(ctx context.Context, req *models.OrderItemPrimaryKey) (int64, error) {
+	rows, err := r.OrderRepoI.RemoveOrderItem(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return rows, err
+}
+
+func (r *orderRepo) AddReturn(ctx context.Context, req *models.CreateOrderReturn) (string, error) {
+	id, err := r.OrderRepoI.AddReturn(ctx, req)
+	r.store.invalidate(ctx, err, Stock)
+	return id, err
+}
+
+type adminRepo struct {
+	storage.AdminRepoI
+	store *Storage
+}
+
+func (r *adminRepo) Seed(ctx context.Context, script string) error {
+	err := r.AdminRepoI.Seed(ctx, script)
+	for _, entity := range []string{Brand, Category, Store} {
+		r.store.invalidate(ctx, err, entity)
+	}
+	return err
+}
+
+func (r *adminRepo) RecomputeStock(ctx context.Context, req *models.StockRecomputeRequest) (*models.StockRecomputeResponse, error) {
+	resp, err := r.AdminRepoI.RecomputeStock(ctx, req)
+	if err == nil && resp.Applied {
+		r.store.invalidate(ctx, err, Stock)
+	}
+	return resp, err
+}
@@ -0,0 +1,174 @@
+package cachesync
+
+import (
+	"context"
+	"time"
+
+	"app/api/models"
+	"app/pkg/logger"
+	"app/storage"
+)
+
+// warmPageSize is how many rows a list query of Warm reads at once.
+const warmPageSize = 100
+
+// Flush drops every cached response by bumping the versions of all the entities.
+func Flush(ctx context.Context, cache storage.CacheRepoI) error {
+	return cache.BumpVersion(ctx, Entities...)
+}
+
+// Warm caches the GetByID response of every row of the cached entities under the keys the
+// handlers read, the entries already cached are kept. It returns how many rows of each entity
+// were warmed.
+func Warm(ctx context.Context, store storage.StorageI, cache storage.CacheRepoI, log logger.LoggerI, ttl time.Duration) (map[string]int, error) {
+
+	warmed := map[string]int{}
+
+	warm := func(entity, key string, dest interface{}, load func() error) error {
+
+		err := ReadThrough(ctx, cache, log, entity, key, ttl, dest, load)
+		if err != nil {
+			return err
+		}
+
+		warmed[entity]++
+		return nil
+	}
+
+	err := pages(func(offset int) (int, error) {
+
+		list, err := store.Brand().GetList(ctx, &models.GetListBrandRequest{Offset: offset, Limit: warmPageSize})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, brand := range list.Brands {
+			var resp *models.Brand
+			err = warm(Brand, KeyByID(brand.Brand_id), &resp, func() (err error) {
+				resp, err = store.Brand().GetByID(ctx, &models.BrandPrimaryKey{Brand_id: brand.Brand_id})
+				return err
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return len(list.Brands), nil
+	})
+	if err != nil {
+		return warmed, err
+	}
+
+	err = pages(func(offset int) (int, error) {
+
+		list, err := store.Category().GetList(ctx, &models.GetListCategoryRequest{Offset: offset, Limit: warmPageSize})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, category := range list.Categories {
+			var resp *models.Category
+			err = warm(Category, KeyByID(category.Category_id), &resp, func() (err error) {
+				resp, err = store.Category().GetByID(ctx, &models.CategoryPrimaryKey{Category_id: category.Category_id})
+				return err
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return len(list.Categories), nil
+	})
+	if err != nil {
+		return warmed, err
+	}
+
+	err = pages(func(offset int) (int, error) {
+
+		list, err := store.Store().GetList(ctx, &models.GetListStoreRequest{Offset: offset, Limit: warmPageSize})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, s := range list.Stores {
+			var resp *models.Store
+			err = warm(Store, KeyByID(s.Store_id), &resp, func() (err error) {
+				resp, err = store.Store().GetByID(ctx, &models.StorePrimaryKey{Store_id: s.Store_id})
+				return err
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return len(list.Stores), nil
+	})
+	if err != nil {
+		return warmed, err
+	}
+
+	err = pages(func(offset int) (int, error) {
+
+		list, err := store.Product().GetList(ctx, &models.GetListProductRequest{Offset: offset, Limit: warmPageSize})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, product := range list.Products {
+			var resp *models.Product
+			err = warm(Product, KeyByID(product.Product_id), &resp, func() (err error) {
+				resp, err = store.Product().GetByID(ctx, &models.ProductPrimaryKey{Product_id: product.Product_id})
+				return err
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return len(list.Products), nil
+	})
+	if err != nil {
+		return warmed, err
+	}
+
+	err = pages(func(offset int) (int, error) {
+
+		list, err := store.Stock().GetList(ctx, &models.GetListStockRequest{Offset: offset, Limit: warmPageSize})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, stock := range list.Stocks {
+			key := &models.StockPrimaryKey{Store_id: stock.Store_id, Product_id: stock.Product_id}
+
+			var resp *models.Stock
+			err = warm(Stock, KeyByID(key.Store_id, key.Product_id), &resp, func() (err error) {
+				resp, err = store.Stock().GetByID(ctx, key)
+				return err
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return len(list.Stocks), nil
+	})
+
+	return warmed, err
+}
+
+// pages calls page with growing offsets until it returns less than a full page.
+func pages(page func(offset int) (int, error)) error {
+
+	for offset := 0; ; offset += warmPageSize {
+
+		n, err := page(offset)
+		if err != nil {
+			return err
+		}
+
+		if n < warmPageSize {
+			return nil
+		}
+	}
+}
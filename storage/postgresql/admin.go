@@ -0,0 +1,308 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+// seededSequences are the serial columns the demo data fills with explicit ids.
+var seededSequences = [][2]string{
+	{"customers", "customer_id"},
+	{"stores", "store_id"},
+}
+
+type AdminRepo struct {
+	db *DB
+}
+
+func NewAdminRepo(db *pgxpool.Pool) *AdminRepo {
+	return &AdminRepo{
+		db: NewDB(db),
+	}
+}
+
+func (r *AdminRepo) Seed(ctx context.Context, script string) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var seeded bool
+
+	err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM brands) OR EXISTS (SELECT 1 FROM stores)").Scan(&seeded)
+	if err != nil {
+		return err
+	}
+
+	if seeded {
+		return errors.New("the database already has catalog data")
+	}
+
+	_, err = tx.Exec(ctx, script)
+	if err != nil {
+		return err
+	}
+
+	// the ids of the script are explicit, the sequences continue after them
+	for _, sequence := range seededSequences {
+		_, err = tx.Exec(ctx, fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), COALESCE(MAX(%[2]s), 0) + 1, false) FROM %[1]s",
+			sequence[0], sequence[1],
+		))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *AdminRepo) RecomputeStock(ctx context.Context, req *models.StockRecomputeRequest) (*models.StockRecomputeResponse, error) {
+
+	switch req.Source {
+	case models.StockRecomputeLedger:
+		return r.recomputeFromLedger(ctx, req)
+	case models.StockRecomputeOrders:
+		return r.recomputeFromOrders(ctx, req)
+	}
+
+	return nil, fmt.Errorf("unknown stock source %q", req.Source)
+}
+
+// recomputeFromLedger sets the drifted stocks to their ledger sum. The ledger already holds
+// the truth, so the transaction sets app.stock_skip_ledger for the stock_movement_tg trigger
+// to not record the fix as one more movement.
+func (r *AdminRepo) recomputeFromLedger(ctx context.Context, req *models.StockRecomputeRequest) (*models.StockRecomputeResponse, error) {
+
+	resp := &models.StockRecomputeResponse{Source: req.Source}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// the stocks stay readable, so the reconciliation below still runs, but no stock and
+	// no ledger row changes until the fix is committed
+	if req.Apply {
+		_, err = tx.Exec(ctx, "LOCK TABLE stocks IN EXCLUSIVE MODE")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reconciliation, err := NewStockMovementRepo(r.db.Pool).Reconcile(ctx, &models.StockReconciliationRequest{
+		Store_id:   req.Store_id,
+		Product_id: req.Product_id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mismatch := range reconciliation.Mismatches {
+		resp.Changes = append(resp.Changes, &models.StockRecompute{
+			Store_id:     mismatch.Store_id,
+			Product_id:   mismatch.Product_id,
+			Quantity:     mismatch.Quantity,
+			New_quantity: mismatch.Ledger_quantity,
+		})
+	}
+
+	resp.Count = len(resp.Changes)
+
+	if !req.Apply || resp.Count == 0 {
+		return resp, nil
+	}
+
+	// transaction local, the other sessions keep recording their movements
+	_, err = tx.Exec(ctx, "SELECT set_config('app.stock_skip_ledger', 'on', true)")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range resp.Changes {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO stocks(
+				store_id,
+				product_id,
+				quantity
+			)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (store_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity
+		`, change.Store_id, change.Product_id, change.New_quantity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Applied = true
+
+	return resp, nil
+}
+
+// recomputeFromOrders takes every order item without a sale in the ledger off the stock of
+// its order's store. The sale is recorded with the reference of the item, like the
+// get_product_from_store trigger does, so a second run finds nothing left to fix. The items
+// created before the ledger are left out, their sales are in its opening balance already.
+func (r *AdminRepo) recomputeFromOrders(ctx context.Context, req *models.StockRecomputeRequest) (*models.StockRecomputeResponse, error) {
+
+	resp := &models.StockRecomputeResponse{Source: req.Source}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// no order item reaches the stocks while the missing sales are read and recorded
+	if req.Apply {
+		_, err = tx.Exec(ctx, "LOCK TABLE stocks IN EXCLUSIVE MODE")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		query  string
+		args   []interface{}
+		filter = `
+			WHERE NOT EXISTS (
+				SELECT 1 FROM stock_movements AS m
+				WHERE m.reference_id = 'order:' || oi.order_id || ':' || oi.item_id
+			)
+			AND COALESCE(oi.created_at, o.order_date) > COALESCE(
+				(SELECT MIN(created_at) FROM stock_movements),
+				'-infinity'
+			)
+		`
+	)
+
+	query = `
+		SELECT
+			o.store_id,
+			oi.product_id,
+			oi.order_id,
+			oi.item_id,
+			oi.quantity,
+			COALESCE(s.quantity, 0)
+		FROM order_items AS oi
+		JOIN orders AS o ON o.order_id = oi.order_id
+		LEFT JOIN stocks AS s ON s.store_id = o.store_id AND s.product_id = oi.product_id
+	`
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		filter += fmt.Sprintf(" AND o.store_id = $%d ", len(args))
+	}
+
+	if req.Product_id > 0 {
+		args = append(args, req.Product_id)
+		filter += fmt.Sprintf(" AND oi.product_id = $%d ", len(args))
+	}
+
+	query += filter + " ORDER BY 1, 2, 3, 4"
+
+	type missingSale struct {
+		change   *models.StockRecompute
+		orderId  int
+		itemId   int
+		quantity int
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sales   []missingSale
+		changes = map[[2]int]*models.StockRecompute{}
+	)
+
+	for rows.Next() {
+
+		var (
+			sale      missingSale
+			recompute models.StockRecompute
+		)
+		err = rows.Scan(
+			&recompute.Store_id,
+			&recompute.Product_id,
+			&sale.orderId,
+			&sale.itemId,
+			&sale.quantity,
+			&recompute.Quantity,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		key := [2]int{recompute.Store_id, recompute.Product_id}
+
+		change, ok := changes[key]
+		if !ok {
+			change = &recompute
+			change.New_quantity = change.Quantity
+			changes[key] = change
+			resp.Changes = append(resp.Changes, change)
+		}
+		change.New_quantity -= sale.quantity
+
+		sale.change = change
+		sales = append(sales, sale)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp.Count = len(resp.Changes)
+
+	if !req.Apply || resp.Count == 0 {
+		return resp, nil
+	}
+
+	for _, sale := range sales {
+
+		err = setStockMovementContext(ctx, tx, models.StockMovementSale, fmt.Sprintf("order:%d:%d", sale.orderId, sale.itemId), req.Actor)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO stocks(
+				store_id,
+				product_id,
+				quantity
+			)
+			VALUES ($1, $2, -$3::INT)
+			ON CONFLICT (store_id, product_id) DO UPDATE SET quantity = COALESCE(stocks.quantity, 0) - $3::INT
+		`, sale.change.Store_id, sale.change.Product_id, sale.quantity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Applied = true
+
+	return resp, nil
+}
@@ -0,0 +1,258 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+// apiKeyTouchInterval is how stale last_used_at gets before a request of the key updates it,
+// so a busy integration does not write the row on every request.
+const apiKeyTouchInterval = "1 minute"
+
+type APIKeyRepo struct {
+	db *DB
+}
+
+func NewAPIKeyRepo(db *pgxpool.Pool) *APIKeyRepo {
+	return &APIKeyRepo{
+		db: NewDB(db),
+	}
+}
+
+func (r *APIKeyRepo) Create(ctx context.Context, req *models.CreateAPIKey) (string, error) {
+
+	var id int
+
+	query := `
+		INSERT INTO api_keys(
+			name,
+			prefix,
+			key_hash,
+			scopes,
+			store_id,
+			expires_at,
+			created_by
+		)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), NULLIF($6, '')::TIMESTAMPTZ, $7) RETURNING api_key_id
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		req.Name,
+		req.Prefix,
+		req.Key_hash,
+		req.Scopes,
+		req.Store_id,
+		req.Expires_at,
+		req.Created_by,
+	).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d", id), nil
+}
+
+func (r *APIKeyRepo) GetByID(ctx context.Context, req *models.APIKeyPrimaryKey) (resp *models.APIKey, err error) {
+
+	resp = &models.APIKey{}
+
+	query := `
+		SELECT
+			api_key_id,
+			name,
+			prefix,
+			scopes,
+			COALESCE(store_id, 0),
+			COALESCE(CAST(expires_at::timestamp AS VARCHAR), ''),
+			COALESCE(CAST(last_used_at::timestamp AS VARCHAR), ''),
+			COALESCE(CAST(revoked_at::timestamp AS VARCHAR), ''),
+			created_by,
+			CAST(created_at::timestamp AS VARCHAR)
+		FROM api_keys
+		WHERE api_key_id = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, req.Api_key_id).Scan(
+		&resp.Api_key_id,
+		&resp.Name,
+		&resp.Prefix,
+		&resp.Scopes,
+		&resp.Store_id,
+		&resp.Expires_at,
+		&resp.Last_used_at,
+		&resp.Revoked_at,
+		&resp.Created_by,
+		&resp.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *APIKeyRepo) GetList(ctx context.Context, req *models.GetListAPIKeyRequest) (resp *models.GetListAPIKeyResponse, err error) {
+
+	resp = &models.GetListAPIKeyResponse{}
+
+	var (
+		query  string
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			api_key_id,
+			name,
+			prefix,
+			scopes,
+			COALESCE(store_id, 0),
+			COALESCE(CAST(expires_at::timestamp AS VARCHAR), ''),
+			COALESCE(CAST(last_used_at::timestamp AS VARCHAR), ''),
+			COALESCE(CAST(revoked_at::timestamp AS VARCHAR), ''),
+			created_by,
+			CAST(created_at::timestamp AS VARCHAR)
+		FROM api_keys
+	`
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	query += " ORDER BY api_key_id " + offset + limit
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var key models.APIKey
+		err = rows.Scan(
+			&resp.Count,
+			&key.Api_key_id,
+			&key.Name,
+			&key.Prefix,
+			&key.Scopes,
+			&key.Store_id,
+			&key.Expires_at,
+			&key.Last_used_at,
+			&key.Revoked_at,
+			&key.Created_by,
+			&key.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.Api_keys = append(resp.Api_keys, &key)
+	}
+
+	return resp, rows.Err()
+}
+
+// Update changes the keys not revoked yet, a revoked key stays revoked.
+func (r *APIKeyRepo) Update(ctx context.Context, req *models.UpdateAPIKey) (int64, error) {
+
+	query := `
+		UPDATE
+			api_keys
+		SET
+			name = $2,
+			scopes = $3,
+			store_id = NULLIF($4, 0),
+			expires_at = NULLIF($5, '')::TIMESTAMPTZ
+		WHERE api_key_id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		req.Api_key_id,
+		req.Name,
+		req.Scopes,
+		req.Store_id,
+		req.Expires_at,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Revoke keeps the row, so the lists still show who used the key and until when.
+func (r *APIKeyRepo) Revoke(ctx context.Context, req *models.APIKeyPrimaryKey) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE api_key_id = $1 AND revoked_at IS NULL", req.Api_key_id)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *APIKeyRepo) Authenticate(ctx context.Context, keyHash string) (resp *models.APIKey, err error) {
+
+	resp = &models.APIKey{}
+
+	query := `
+		WITH key AS (
+			SELECT
+				api_key_id,
+				name,
+				prefix,
+				scopes,
+				COALESCE(store_id, 0) AS store_id,
+				COALESCE(CAST(expires_at::timestamp AS VARCHAR), '') AS expires_at,
+				COALESCE(CAST(last_used_at::timestamp AS VARCHAR), '') AS last_used_at,
+				created_by,
+				CAST(created_at::timestamp AS VARCHAR) AS created_at
+			FROM api_keys
+			WHERE key_hash = $1
+				AND revoked_at IS NULL
+				AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+		), touched AS (
+			UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP
+			WHERE api_key_id = (SELECT api_key_id FROM key)
+				AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '` + apiKeyTouchInterval + `')
+		)
+		SELECT
+			api_key_id,
+			name,
+			prefix,
+			scopes,
+			store_id,
+			expires_at,
+			last_used_at,
+			created_by,
+			created_at
+		FROM key
+	`
+
+	err = r.db.QueryRow(ctx, query, keyHash).Scan(
+		&resp.Api_key_id,
+		&resp.Name,
+		&resp.Prefix,
+		&resp.Scopes,
+		&resp.Store_id,
+		&resp.Expires_at,
+		&resp.Last_used_at,
+		&resp.Created_by,
+		&resp.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
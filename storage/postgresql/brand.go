@@ -14,12 +14,12 @@ import (
 )
 
 type BrandRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewBrandRepo(db *pgxpool.Pool) *BrandRepo {
 	return &BrandRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
@@ -190,8 +190,6 @@ func (r *BrandRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64,
 
 	req.Fields["id"] = req.ID
 
-	fmt.Println(req.Fields)
-
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
 
 	result, err := r.db.Exec(ctx, query, args...)
@@ -14,12 +14,12 @@ import (
 )
 
 type CategoryRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewCategoryRepo(db *pgxpool.Pool) *CategoryRepo {
 	return &CategoryRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
@@ -192,8 +192,6 @@ func (r *CategoryRepo) Patch(ctx context.Context, req *models.PatchRequest) (int
 
 	req.Fields["id"] = req.ID
 
-	fmt.Println(req.Fields)
-
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
 
 	result, err := r.db.Exec(ctx, query, args...)
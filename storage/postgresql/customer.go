@@ -12,12 +12,12 @@ import (
 )
 
 type CustomerRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewCustomerRepo(db *pgxpool.Pool) *CustomerRepo {
 	return &CustomerRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
@@ -228,8 +228,6 @@ func (r *CustomerRepo) Patch(ctx context.Context, req *models.PatchRequest) (int
 
 	req.Fields["id"] = req.ID
 
-	fmt.Println(req.Fields)
-
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
 
 	result, err := r.db.Exec(ctx, query, args...)
@@ -252,3 +250,56 @@ func (r *CustomerRepo) Delete(ctx context.Context, req *models.CustomerPrimaryKe
 
 	return rows.RowsAffected(), err
 }
+
+// GetSummary computes the order aggregates of the customer, rejected orders are not counted in the spend.
+func (r *CustomerRepo) GetSummary(ctx context.Context, req *models.CustomerPrimaryKey) (*models.CustomerSummary, error) {
+
+	var (
+		query string
+		resp  models.CustomerSummary
+	)
+
+	query = `
+		SELECT
+			c.customer_id,
+			COUNT(o.order_id),
+			COALESCE(SUM(totals.net_total) FILTER (WHERE o.order_status <> 3), 0),
+			MAX(o.order_date),
+			COALESCE(fav.store_id, 0),
+			COALESCE(fav.store_name, '')
+		FROM customers AS c
+		LEFT JOIN orders AS o ON o.customer_id = c.customer_id
+		LEFT JOIN LATERAL (
+			SELECT
+				COALESCE((SELECT SUM(oi.quantity * oi.list_price * (1 - oi.discount)) FROM order_items AS oi WHERE oi.order_id = o.order_id), 0) -
+				COALESCE((SELECT SUM(ret.refund_amount) FROM order_returns AS ret WHERE ret.order_id = o.order_id), 0) AS net_total
+		) AS totals ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT
+				st.store_id,
+				st.store_name
+			FROM orders AS fo
+			JOIN stores AS st ON st.store_id = fo.store_id
+			WHERE fo.customer_id = c.customer_id
+			GROUP BY st.store_id, st.store_name
+			ORDER BY COUNT(*) DESC, MAX(fo.order_date) DESC
+			LIMIT 1
+		) AS fav ON TRUE
+		WHERE c.customer_id = $1
+		GROUP BY c.customer_id, fav.store_id, fav.store_name
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Customer_id).Scan(
+		&resp.Customer_id,
+		&resp.Orders_count,
+		&resp.Lifetime_spend,
+		&resp.Last_order_date,
+		&resp.Favourite_store_id,
+		&resp.Favourite_store_name,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
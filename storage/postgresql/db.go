@@ -0,0 +1,122 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/storage"
+)
+
+// DB is the pool used by the repos, it reports queries stopped by the
+// deadline of their context or by the statement_timeout as storage.ErrTimeout.
+type DB struct {
+	*pgxpool.Pool
+}
+
+func NewDB(pool *pgxpool.Pool) *DB {
+	return &DB{
+		Pool: pool,
+	}
+}
+
+// queryCanceled is the SQLSTATE of the statements stopped by the statement_timeout.
+const queryCanceled = "57014"
+
+// mapError converts the timeouts of pgx and of the server into storage.ErrTimeout.
+func mapError(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return storage.ErrTimeout
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == queryCanceled {
+		return storage.ErrTimeout
+	}
+
+	return err
+}
+
+func (db *DB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	tag, err := db.Pool.Exec(ctx, sql, args...)
+	return tag, mapError(err)
+}
+
+func (db *DB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+
+	r, err := db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &rows{Rows: r}, nil
+}
+
+func (db *DB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return &row{Row: db.Pool.QueryRow(ctx, sql, args...)}
+}
+
+func (db *DB) Begin(ctx context.Context) (pgx.Tx, error) {
+
+	t, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &tx{Tx: t}, nil
+}
+
+type tx struct {
+	pgx.Tx
+}
+
+func (t *tx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	tag, err := t.Tx.Exec(ctx, sql, args...)
+	return tag, mapError(err)
+}
+
+func (t *tx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+
+	r, err := t.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &rows{Rows: r}, nil
+}
+
+func (t *tx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return &row{Row: t.Tx.QueryRow(ctx, sql, args...)}
+}
+
+func (t *tx) Commit(ctx context.Context) error {
+	return mapError(t.Tx.Commit(ctx))
+}
+
+type rows struct {
+	pgx.Rows
+}
+
+func (r *rows) Scan(dest ...interface{}) error {
+	return mapError(r.Rows.Scan(dest...))
+}
+
+func (r *rows) Err() error {
+	return mapError(r.Rows.Err())
+}
+
+type row struct {
+	pgx.Row
+}
+
+func (r *row) Scan(dest ...interface{}) error {
+	return mapError(r.Row.Scan(dest...))
+}
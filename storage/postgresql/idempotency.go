@@ -0,0 +1,115 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type IdempotencyRepo struct {
+	db *DB
+}
+
+func NewIdempotencyRepo(db *pgxpool.Pool) *IdempotencyRepo {
+	return &IdempotencyRepo{
+		db: NewDB(db),
+	}
+}
+
+// Reserve inserts the key as running, a key older than ttl, or still running after lease, is
+// taken over as if it did not exist.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, key *models.IdempotencyKey, ttl, lease time.Duration) (*models.IdempotencyKey, bool, error) {
+
+	query := `
+		INSERT INTO idempotency_keys (key, request_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status = 0,
+			content_type = '',
+			body = NULL,
+			created_at = CURRENT_TIMESTAMP
+		WHERE idempotency_keys.created_at < CURRENT_TIMESTAMP - $3::interval
+			OR (idempotency_keys.status = 0 AND idempotency_keys.created_at < CURRENT_TIMESTAMP - $4::interval)
+		RETURNING key
+	`
+
+	err := r.db.QueryRow(ctx, query, key.Key, key.Request_hash, ttl, lease).Scan(&key.Key)
+	if err == nil {
+		return nil, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	var existing models.IdempotencyKey
+
+	query = `
+		SELECT
+			key,
+			request_hash,
+			status,
+			content_type,
+			body
+		FROM idempotency_keys
+		WHERE key = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, key.Key).Scan(
+		&existing.Key,
+		&existing.Request_hash,
+		&existing.Status,
+		&existing.Content_type,
+		&existing.Body,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &existing, false, nil
+}
+
+// Complete saves the response of the request that reserved the key.
+func (r *IdempotencyRepo) Complete(ctx context.Context, key *models.IdempotencyKey) error {
+
+	query := `
+		UPDATE idempotency_keys
+		SET
+			status = $2,
+			content_type = $3,
+			body = $4
+		WHERE key = $1
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		key.Key,
+		key.Status,
+		key.Content_type,
+		key.Body,
+	)
+
+	return err
+}
+
+// Release drops a running key so the request can be retried with it.
+func (r *IdempotencyRepo) Release(ctx context.Context, key string) error {
+
+	_, err := r.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND status = 0", key)
+
+	return err
+}
+
+func (r *IdempotencyRepo) Purge(ctx context.Context, ttl time.Duration) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE created_at < CURRENT_TIMESTAMP - $1::interval", ttl)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
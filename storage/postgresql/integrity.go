@@ -0,0 +1,325 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"app/api/models"
+)
+
+// integrityActor is the actor of the stock movements written by the fix SQL.
+const integrityActor = "integrity-check"
+
+// CheckIntegrity runs every check and, when asked, joins the fixes into one transaction.
+// Orders sold by staff of another store, orphaned ledger rows and ledger mismatches have
+// no mechanical fix: the ledger is append-only and the other store's staff is a decision.
+func (r *AdminRepo) CheckIntegrity(ctx context.Context, req *models.IntegrityCheckRequest) (*models.IntegrityReport, error) {
+
+	report := &models.IntegrityReport{
+		Checks: map[string]int{},
+	}
+
+	checks := []func(context.Context) ([]*models.IntegrityIssue, error){
+		r.negativeStock,
+		r.missingStockRows,
+		r.orderStaffStore,
+		r.managerCycles,
+		r.orphanedMovements,
+		r.ledgerMismatches,
+	}
+
+	for _, check := range checks {
+
+		issues, err := check(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			report.Checks[issue.Check]++
+
+			if !req.Fix_sql {
+				issue.Fix = ""
+			}
+		}
+
+		report.Issues = append(report.Issues, issues...)
+	}
+
+	report.Count = len(report.Issues)
+	report.Consistent = report.Count == 0
+
+	if req.Fix_sql {
+		report.Fix_sql = fixSQL(report.Issues)
+	}
+
+	return report, nil
+}
+
+// fixSQL joins the fixes of issues into a transaction, the stock changes are recorded in
+// the ledger as adjustments of the integrity check.
+func fixSQL(issues []*models.IntegrityIssue) string {
+
+	var fixes []string
+	for _, issue := range issues {
+		if len(issue.Fix) > 0 {
+			fixes = append(fixes, issue.Fix)
+		}
+	}
+
+	if len(fixes) == 0 {
+		return ""
+	}
+
+	return "BEGIN;\n" +
+		fmt.Sprintf(
+			"SELECT set_config('app.stock_reason', '%s', true), set_config('app.stock_reference', 'integrity', true), set_config('app.stock_actor', '%s', true);\n",
+			models.StockMovementAdjustment, integrityActor,
+		) +
+		strings.Join(fixes, "\n") +
+		"\nCOMMIT;\n"
+}
+
+func (r *AdminRepo) negativeStock(ctx context.Context) ([]*models.IntegrityIssue, error) {
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			store_id,
+			product_id,
+			quantity
+		FROM stocks
+		WHERE quantity < 0
+		ORDER BY store_id, product_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []*models.IntegrityIssue
+
+	for rows.Next() {
+
+		var (
+			issue    = models.IntegrityIssue{Check: models.IntegrityNegativeStock}
+			quantity int
+		)
+		err = rows.Scan(&issue.Store_id, &issue.Product_id, &quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		issue.Message = fmt.Sprintf("stock of product %d in store %d is %d", issue.Product_id, issue.Store_id, quantity)
+		issue.Fix = fmt.Sprintf("UPDATE stocks SET quantity = 0 WHERE store_id = %d AND product_id = %d;", issue.Store_id, issue.Product_id)
+		issues = append(issues, &issue)
+	}
+
+	return issues, rows.Err()
+}
+
+// missingStockRows finds the products sold by a store without a stock row for them, the
+// get_product_from_store trigger then updates no row and the sale never reaches the stock.
+func (r *AdminRepo) missingStockRows(ctx context.Context) ([]*models.IntegrityIssue, error) {
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			o.store_id,
+			oi.product_id,
+			COUNT(*),
+			SUM(oi.quantity)
+		FROM order_items AS oi
+		JOIN orders AS o ON o.order_id = oi.order_id
+		LEFT JOIN stocks AS s ON s.store_id = o.store_id AND s.product_id = oi.product_id
+		WHERE s.product_id IS NULL
+		GROUP BY o.store_id, oi.product_id
+		ORDER BY o.store_id, oi.product_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []*models.IntegrityIssue
+
+	for rows.Next() {
+
+		var (
+			issue    = models.IntegrityIssue{Check: models.IntegrityMissingStockRow}
+			items    int
+			quantity int
+		)
+		err = rows.Scan(&issue.Store_id, &issue.Product_id, &items, &quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		issue.Message = fmt.Sprintf("store %d has no stock row of product %d, sold %d times by %d order items; after the fix run admin recompute-stock -from orders", issue.Store_id, issue.Product_id, quantity, items)
+		issue.Fix = fmt.Sprintf("INSERT INTO stocks(store_id, product_id, quantity) VALUES (%d, %d, 0) ON CONFLICT DO NOTHING;", issue.Store_id, issue.Product_id)
+		issues = append(issues, &issue)
+	}
+
+	return issues, rows.Err()
+}
+
+func (r *AdminRepo) orderStaffStore(ctx context.Context) ([]*models.IntegrityIssue, error) {
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			o.order_id,
+			o.store_id,
+			o.staff_id,
+			s.store_id
+		FROM orders AS o
+		JOIN staffs AS s ON s.staff_id = o.staff_id
+		WHERE s.store_id <> o.store_id
+		ORDER BY o.order_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []*models.IntegrityIssue
+
+	for rows.Next() {
+
+		var (
+			issue        = models.IntegrityIssue{Check: models.IntegrityOrderStaffStore}
+			staffStoreId int
+		)
+		err = rows.Scan(&issue.Order_id, &issue.Store_id, &issue.Staff_id, &staffStoreId)
+		if err != nil {
+			return nil, err
+		}
+
+		issue.Message = fmt.Sprintf("order %d of store %d is sold by staff %d of store %d", issue.Order_id, issue.Store_id, issue.Staff_id, staffStoreId)
+		issues = append(issues, &issue)
+	}
+
+	return issues, rows.Err()
+}
+
+// managerCycles finds the manager chains that come back to where they started, the
+// staff_manager_cycle_tg trigger only stops the cycles created after it. Every cycle
+// is reported once, from its lowest staff_id, and the fix cuts it there.
+func (r *AdminRepo) managerCycles(ctx context.Context) ([]*models.IntegrityIssue, error) {
+
+	rows, err := r.db.Query(ctx, `
+		WITH RECURSIVE chain AS (
+			SELECT staff_id AS start_id, manager_id, ARRAY[staff_id] AS path, false AS cycle
+			FROM staffs
+			WHERE manager_id IS NOT NULL
+			UNION ALL
+			SELECT chain.start_id, s.manager_id, chain.path || s.staff_id, s.staff_id = ANY(chain.path)
+			FROM staffs AS s
+			JOIN chain ON s.staff_id = chain.manager_id
+			WHERE NOT chain.cycle
+		)
+		SELECT
+			start_id,
+			array_to_string(path, ' -> ')
+		FROM chain
+		WHERE cycle
+			AND path[array_length(path, 1)] = start_id
+			AND start_id = (SELECT MIN(id) FROM unnest(path) AS id)
+		ORDER BY start_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []*models.IntegrityIssue
+
+	for rows.Next() {
+
+		var (
+			issue = models.IntegrityIssue{Check: models.IntegrityManagerCycle}
+			path  string
+		)
+		err = rows.Scan(&issue.Staff_id, &path)
+		if err != nil {
+			return nil, err
+		}
+
+		issue.Message = fmt.Sprintf("manager cycle %s", path)
+		issue.Fix = fmt.Sprintf("UPDATE staffs SET manager_id = NULL WHERE staff_id = %d;", issue.Staff_id)
+		issues = append(issues, &issue)
+	}
+
+	return issues, rows.Err()
+}
+
+// orphanedMovements finds the ledger rows of deleted stores and products, stock_movements
+// has no foreign keys so the cascading deletes leave them behind.
+func (r *AdminRepo) orphanedMovements(ctx context.Context) ([]*models.IntegrityIssue, error) {
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			m.store_id,
+			m.product_id,
+			st.store_id IS NULL,
+			p.product_id IS NULL,
+			COUNT(*)
+		FROM stock_movements AS m
+		LEFT JOIN stores AS st ON st.store_id = m.store_id
+		LEFT JOIN products AS p ON p.product_id = m.product_id
+		WHERE st.store_id IS NULL OR p.product_id IS NULL
+		GROUP BY m.store_id, m.product_id, st.store_id, p.product_id
+		ORDER BY m.store_id, m.product_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []*models.IntegrityIssue
+
+	for rows.Next() {
+
+		var (
+			issue                        = models.IntegrityIssue{Check: models.IntegrityOrphanMovement}
+			storeMissing, productMissing bool
+			count                        int
+		)
+		err = rows.Scan(&issue.Store_id, &issue.Product_id, &storeMissing, &productMissing, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		var missing []string
+		if storeMissing {
+			missing = append(missing, fmt.Sprintf("store %d", issue.Store_id))
+		}
+		if productMissing {
+			missing = append(missing, fmt.Sprintf("product %d", issue.Product_id))
+		}
+
+		issue.Message = fmt.Sprintf("%d ledger rows of deleted %s", count, strings.Join(missing, " and "))
+		issues = append(issues, &issue)
+	}
+
+	return issues, rows.Err()
+}
+
+func (r *AdminRepo) ledgerMismatches(ctx context.Context) ([]*models.IntegrityIssue, error) {
+
+	reconciliation, err := NewStockMovementRepo(r.db.Pool).Reconcile(ctx, &models.StockReconciliationRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []*models.IntegrityIssue
+
+	for _, mismatch := range reconciliation.Mismatches {
+		issues = append(issues, &models.IntegrityIssue{
+			Check:      models.IntegrityLedgerMismatch,
+			Message:    fmt.Sprintf("stock of product %d in store %d is %d, its ledger sums to %d; run admin recompute-stock -from ledger", mismatch.Product_id, mismatch.Store_id, mismatch.Quantity, mismatch.Ledger_quantity),
+			Store_id:   mismatch.Store_id,
+			Product_id: mismatch.Product_id,
+		})
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,81 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+
+	"app/pkg/logger"
+	"app/pkg/tracing"
+)
+
+// pgxLogger writes the pgx log through the service logger with the request id of the query context,
+// and records a span for every finished query when tracing is on.
+// The query arguments are never logged, they can hold passwords.
+type pgxLogger struct {
+	log   logger.LoggerI
+	level pgx.LogLevel
+	trace bool
+}
+
+func newPgxLogger(log logger.LoggerI, level pgx.LogLevel, trace bool) *pgxLogger {
+	return &pgxLogger{
+		log:   log,
+		level: level,
+		trace: trace,
+	}
+}
+
+func (l *pgxLogger) Log(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+
+	if l.trace {
+		l.span(ctx, msg, data)
+	}
+
+	// the pool log level may be raised for the spans, the rest stays out of the log
+	if level > l.level {
+		return
+	}
+
+	fields := []logger.Field{logger.RequestIDField(ctx)}
+	for key, value := range data {
+		if key == "args" {
+			continue
+		}
+		fields = append(fields, logger.Any(key, value))
+	}
+
+	msg = "postgres: " + msg
+
+	switch level {
+	case pgx.LogLevelError:
+		l.log.Error(msg, fields...)
+	case pgx.LogLevelWarn:
+		l.log.Warn(msg, fields...)
+	case pgx.LogLevelInfo:
+		l.log.Info(msg, fields...)
+	default:
+		l.log.Debug(msg, fields...)
+	}
+}
+
+// span records the queries pgx reports once they are done, "time" is their duration.
+func (l *pgxLogger) span(ctx context.Context, msg string, data map[string]interface{}) {
+
+	switch msg {
+	case "Query", "Exec", "SendBatch", "CopyFrom":
+	default:
+		return
+	}
+
+	elapsed, _ := data["time"].(time.Duration)
+	err, _ := data["err"].(error)
+	sql, _ := data["sql"].(string)
+
+	tracing.RecordClient(ctx, "postgres "+msg, elapsed, err,
+		semconv.DBSystemPostgreSQL,
+		semconv.DBStatement(sql),
+	)
+}
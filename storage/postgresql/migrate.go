@@ -0,0 +1,162 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratepgx "github.com/golang-migrate/migrate/v4/database/pgx"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+
+	"app/config"
+	"app/migrations"
+	"app/pkg/logger"
+)
+
+// MigrationStatus is the schema version of the database and the latest embedded migration.
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+}
+
+// Migrator applies the embedded migrations. The version is kept in schema_migrations
+// like the migrate CLI does, so both work on the same database.
+type Migrator struct {
+	db     *sql.DB
+	m      *migrate.Migrate
+	latest uint
+}
+
+func NewMigrator(cfg *config.Config, log logger.LoggerI) (*Migrator, error) {
+
+	src, err := iofs.New(migrations.Postgres, "postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := latestVersion(src)
+	if err != nil {
+		return nil, err
+	}
+
+	connConfig, err := pgx.ParseConfig(connString(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	db := stdlib.OpenDB(*connConfig)
+
+	driver, err := migratepgx.WithInstance(db, &migratepgx.Config{})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, cfg.PostgresDatabase, driver)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	m.Log = &migrateLogger{log: log}
+
+	return &Migrator{
+		db:     db,
+		m:      m,
+		latest: latest,
+	}, nil
+}
+
+// Up applies every migration not applied yet.
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.m.Up())
+}
+
+// Down reverts the last steps migrations, all of them when steps is 0.
+func (m *Migrator) Down(steps int) error {
+
+	if steps <= 0 {
+		return ignoreNoChange(m.m.Down())
+	}
+
+	return ignoreNoChange(m.m.Steps(-steps))
+}
+
+// Force sets the version without running any migration, to recover from a dirty version
+// once the failed migration was fixed by hand.
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+func (m *Migrator) Status() (*MigrationStatus, error) {
+
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+
+	return &MigrationStatus{
+		Version: version,
+		Dirty:   dirty,
+		Latest:  m.latest,
+	}, nil
+}
+
+func (m *Migrator) Close() error {
+
+	sourceErr, dbErr := m.m.Close()
+	m.db.Close()
+
+	if sourceErr != nil {
+		return sourceErr
+	}
+
+	return dbErr
+}
+
+func ignoreNoChange(err error) error {
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+func latestVersion(src source.Driver) (uint, error) {
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("migrations: %w", err)
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// migrateLogger writes the applied migrations to the service log.
+type migrateLogger struct {
+	log logger.LoggerI
+}
+
+func (l *migrateLogger) Printf(format string, v ...interface{}) {
+	l.log.Info(fmt.Sprintf("migrate: "+format, v...))
+}
+
+func (l *migrateLogger) Verbose() bool {
+	return false
+}
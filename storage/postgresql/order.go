@@ -6,19 +6,24 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"app/api/models"
 	"app/pkg/helper"
+	"app/storage"
 )
 
+// openOrderCondition matches the orders that are still pending or processing and not shipped yet.
+const openOrderCondition = " o.shipped_date IS NULL AND o.order_status IN (1, 2) "
+
 type OrderRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewOrderRepo(db *pgxpool.Pool) *OrderRepo {
 	return &OrderRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
@@ -141,7 +146,10 @@ func (r *OrderRepo) GetByID(ctx context.Context, req *models.OrderPrimaryKey) (r
 			COALESCE(sta.phone, ''),
 			COALESCE(sta.active, 0),
 			COALESCE(sta.store_id, 0),
-			COALESCE(sta.manager_id, 0)
+			COALESCE(sta.manager_id, 0),
+
+			COALESCE((SELECT SUM(oi.quantity * oi.list_price * (1 - oi.discount)) FROM order_items AS oi WHERE oi.order_id = o.order_id), 0),
+			COALESCE((SELECT SUM(ret.refund_amount) FROM order_returns AS ret WHERE ret.order_id = o.order_id), 0)
 		FROM orders as o join customers as c 
 		ON o.customer_id = c.customer_id join stores as sto 
 		ON o.store_id = sto.store_id join staffs as sta
@@ -185,12 +193,22 @@ func (r *OrderRepo) GetByID(ctx context.Context, req *models.OrderPrimaryKey) (r
 		&resp.StaffData.Active,
 		&resp.StaffData.Store_id,
 		&resp.StaffData.Manager_id,
+		&resp.Total,
+		&resp.Refunded,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	resp.Net_total = resp.Total - resp.Refunded
+
+	returns, err := r.GetListReturn(ctx, &models.GetListOrderReturnRequest{Order_id: req.Order_id, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+	resp.Returns = returns.OrderReturns
+
 	return resp, nil
 }
 
@@ -200,6 +218,7 @@ func (r *OrderRepo) GetList(ctx context.Context, req *models.GetListOrderRequest
 
 	var (
 		query  string
+		args   []interface{}
 		filter = " WHERE TRUE "
 		offset = " OFFSET 0"
 		limit  = " LIMIT 10"
@@ -245,7 +264,10 @@ func (r *OrderRepo) GetList(ctx context.Context, req *models.GetListOrderRequest
 			COALESCE(sta.phone, ''),
 			COALESCE(sta.active, 0),
 			COALESCE(sta.store_id, 0),
-			COALESCE(sta.manager_id, 0)
+			COALESCE(sta.manager_id, 0),
+
+			COALESCE((SELECT SUM(oi.quantity * oi.list_price * (1 - oi.discount)) FROM order_items AS oi WHERE oi.order_id = o.order_id), 0),
+			COALESCE((SELECT SUM(ret.refund_amount) FROM order_returns AS ret WHERE ret.order_id = o.order_id), 0)
 		FROM orders as o join customers as c 
 		ON o.customer_id = c.customer_id join stores as sto 
 		ON o.store_id = sto.store_id join staffs as sta
@@ -256,6 +278,29 @@ func (r *OrderRepo) GetList(ctx context.Context, req *models.GetListOrderRequest
 		filter += " AND order_name ILIKE '%' || '" + req.Search + "' || '%' "
 	}
 
+	if req.Customer_id > 0 {
+		args = append(args, req.Customer_id)
+		filter += fmt.Sprintf(" AND o.customer_id = $%d ", len(args))
+	}
+
+	if len(req.Statuses) > 0 {
+		args = append(args, req.Statuses)
+		filter += fmt.Sprintf(" AND o.order_status = ANY($%d) ", len(args))
+	}
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		filter += fmt.Sprintf(" AND o.store_id = $%d ", len(args))
+	}
+
+	switch req.Delivery {
+	case models.OrderDeliveryOverdue:
+		filter += " AND " + openOrderCondition + " AND o.required_date < CURRENT_DATE "
+	case models.OrderDeliveryAtRisk:
+		args = append(args, req.At_risk_days)
+		filter += " AND " + openOrderCondition + fmt.Sprintf(" AND o.required_date >= CURRENT_DATE AND o.required_date <= CURRENT_DATE + $%d::int ", len(args))
+	}
+
 	if req.Offset > 0 {
 		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
 	}
@@ -264,9 +309,9 @@ func (r *OrderRepo) GetList(ctx context.Context, req *models.GetListOrderRequest
 		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
 	}
 
-	query += filter + offset + limit
+	query += filter + " ORDER BY o.order_date DESC, o.order_id DESC " + offset + limit
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -315,7 +360,10 @@ func (r *OrderRepo) GetList(ctx context.Context, req *models.GetListOrderRequest
 			&staff.Active,
 			&staff.Store_id,
 			&staff.Manager_id,
+			&order.Total,
+			&order.Refunded,
 		)
+		order.Net_total = order.Total - order.Refunded
 		order.CustomerData = &customer
 		order.StoreData = &store
 		order.StaffData = &staff
@@ -395,8 +443,6 @@ func (r *OrderRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64,
 
 	req.Fields["id"] = req.ID
 
-	fmt.Println(req.Fields)
-
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
 
 	result, err := r.db.Exec(ctx, query, args...)
@@ -414,7 +460,7 @@ func (r *OrderRepo) Delete(ctx context.Context, req *models.OrderPrimaryKey) (in
 	)
 
 	if err != nil {
-		return rows.RowsAffected(), err
+		return rows.RowsAffected(), returnsError(err)
 	}
 
 	return rows.RowsAffected(), nil
@@ -444,7 +490,19 @@ func (r *OrderRepo) AddOrderItem(ctx context.Context, req *models.OrderItem) (st
 			$2, $3, $4, $5) returning item_id
 	`
 
-	err := r.db.QueryRow(ctx, query,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// the sale is written to stock_movements by get_product_from_store trigger
+	err = setStockMovementContext(ctx, tx, models.StockMovementSale, "", req.Actor)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.QueryRow(ctx, query,
 		req.Order_id,
 		req.Product_id,
 		req.Quantity,
@@ -456,6 +514,11 @@ func (r *OrderRepo) AddOrderItem(ctx context.Context, req *models.OrderItem) (st
 		return "", err
 	}
 
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	return fmt.Sprintf("%d", id), nil
 }
 
@@ -466,8 +529,260 @@ func (r *OrderRepo) RemoveOrderItem(ctx context.Context, req *models.OrderItemPr
 	)
 
 	if err != nil {
-		return rows.RowsAffected(), err
+		return rows.RowsAffected(), returnsError(err)
 	}
 
 	return rows.RowsAffected(), nil
 }
+
+func (r *OrderRepo) AddReturn(ctx context.Context, req *models.CreateOrderReturn) (string, error) {
+
+	var (
+		query string
+		id    int
+	)
+
+	// when refund_amount is not given the price paid for the returned quantity is refunded
+	query = `
+		INSERT INTO order_returns(
+			order_id,
+			item_id,
+			quantity,
+			reason,
+			refund_amount
+		)
+		SELECT
+			oi.order_id,
+			oi.item_id,
+			$3::int,
+			$4::varchar,
+			COALESCE($5::numeric, ROUND($3::int * oi.list_price * (1 - oi.discount), 2))
+		FROM order_items AS oi
+		WHERE oi.order_id = $1 AND oi.item_id = $2
+		returning return_id
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// the restock is written to stock_movements by return_product_to_store trigger
+	err = setStockMovementContext(ctx, tx, models.StockMovementReturn, "", req.Actor)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.QueryRow(ctx, query,
+		req.Order_id,
+		req.Item_id,
+		req.Quantity,
+		helper.NewNullString(req.Reason),
+		req.Refund_amount,
+	).Scan(&id)
+
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d", id), nil
+}
+
+func (r *OrderRepo) GetReturnByID(ctx context.Context, req *models.OrderReturnPrimaryKey) (*models.OrderReturn, error) {
+
+	var (
+		query string
+		resp  models.OrderReturn
+	)
+
+	query = `
+		SELECT
+			ret.return_id,
+			ret.order_id,
+			ret.item_id,
+			oi.product_id,
+			ret.quantity,
+			COALESCE(ret.reason, ''),
+			ret.refund_amount,
+			CAST(ret.created_at::timestamp AS VARCHAR)
+		FROM order_returns AS ret
+		JOIN order_items AS oi ON oi.order_id = ret.order_id AND oi.item_id = ret.item_id
+		WHERE ret.return_id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Return_id).Scan(
+		&resp.Return_id,
+		&resp.Order_id,
+		&resp.Item_id,
+		&resp.Product_id,
+		&resp.Quantity,
+		&resp.Reason,
+		&resp.Refund_amount,
+		&resp.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (r *OrderRepo) GetListReturn(ctx context.Context, req *models.GetListOrderReturnRequest) (resp *models.GetListOrderReturnResponse, err error) {
+
+	resp = &models.GetListOrderReturnResponse{}
+
+	var (
+		query  string
+		args   []interface{}
+		filter = " WHERE TRUE "
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			ret.return_id,
+			ret.order_id,
+			ret.item_id,
+			oi.product_id,
+			ret.quantity,
+			COALESCE(ret.reason, ''),
+			ret.refund_amount,
+			CAST(ret.created_at::timestamp AS VARCHAR)
+		FROM order_returns AS ret
+		JOIN order_items AS oi ON oi.order_id = ret.order_id AND oi.item_id = ret.item_id
+	`
+
+	if req.Order_id > 0 {
+		args = append(args, req.Order_id)
+		filter += fmt.Sprintf(" AND ret.order_id = $%d ", len(args))
+	}
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	// negative limit returns all the rows
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	} else if req.Limit < 0 {
+		limit = ""
+	}
+
+	query += filter + " ORDER BY ret.return_id " + offset + limit
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var orderReturn models.OrderReturn
+		err = rows.Scan(
+			&resp.Count,
+			&orderReturn.Return_id,
+			&orderReturn.Order_id,
+			&orderReturn.Item_id,
+			&orderReturn.Product_id,
+			&orderReturn.Quantity,
+			&orderReturn.Reason,
+			&orderReturn.Refund_amount,
+			&orderReturn.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.OrderReturns = append(resp.OrderReturns, &orderReturn)
+	}
+
+	return resp, nil
+}
+
+// FlagOverdue marks the open orders past their required_date that were not flagged before
+// and returns them, so every overdue order is reported only once.
+func (r *OrderRepo) FlagOverdue(ctx context.Context) ([]*models.OverdueOrder, error) {
+
+	var (
+		resp  []*models.OverdueOrder
+		query string
+	)
+
+	query = `
+		UPDATE
+			orders AS o
+		SET
+			overdue_flagged_at = NOW()
+		WHERE ` + openOrderCondition + ` AND o.required_date < CURRENT_DATE AND o.overdue_flagged_at IS NULL
+		RETURNING
+			o.order_id,
+			COALESCE(o.customer_id, 0),
+			COALESCE(o.store_id, 0),
+			COALESCE(o.staff_id, 0),
+			o.order_status,
+			o.order_date,
+			o.required_date,
+			CURRENT_DATE - o.required_date
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var order models.OverdueOrder
+		err = rows.Scan(
+			&order.Order_id,
+			&order.Customer_id,
+			&order.Store_id,
+			&order.Staff_id,
+			&order.Order_status,
+			&order.Order_date,
+			&order.Required_date,
+			&order.Days_overdue,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, &order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *OrderRepo) UnflagOverdue(ctx context.Context, req *models.OrderPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx,
+		"UPDATE orders SET overdue_flagged_at = NULL WHERE order_id = $1", req.Order_id,
+	)
+
+	return err
+}
+
+// returnsError reports the returns keeping an order item from being removed as
+// storage.ErrHasReturns.
+func returnsError(err error) error {
+
+	// 23503 is foreign_key_violation
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.TableName == "order_returns" {
+		return storage.ErrHasReturns
+	}
+
+	return err
+}
@@ -0,0 +1,114 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type OutboxRepo struct {
+	db *DB
+}
+
+func NewOutboxRepo(db *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{
+		db: NewDB(db),
+	}
+}
+
+// Relay locks the batch while it is published, so a second relay skips it, and marks the
+// published events in the same transaction. An event published right before a crash is
+// published again, the consumers drop it by its event_id.
+func (r *OutboxRepo) Relay(ctx context.Context, limit int, publish func(context.Context, *models.OutboxEvent) error) (int, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT
+			event_id,
+			event_type,
+			aggregate_type,
+			aggregate_id,
+			payload,
+			created_at
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY event_id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []*models.OutboxEvent
+
+	for rows.Next() {
+
+		var event models.OutboxEvent
+		err = rows.Scan(
+			&event.Event_id,
+			&event.Event_type,
+			&event.Aggregate_type,
+			&event.Aggregate_id,
+			&event.Payload,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, &event)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var (
+		published  []int64
+		publishErr error
+	)
+
+	for _, event := range events {
+		publishErr = publish(ctx, event)
+		if publishErr != nil {
+			break
+		}
+		published = append(published, event.Event_id)
+	}
+
+	if len(published) > 0 {
+		_, err = tx.Exec(ctx, "UPDATE outbox SET published_at = CURRENT_TIMESTAMP WHERE event_id = ANY($1)", published)
+		if err != nil {
+			return 0, err
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(published), publishErr
+}
+
+func (r *OutboxRepo) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "DELETE FROM outbox WHERE published_at < CURRENT_TIMESTAMP - $1::interval", retention)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -3,10 +3,14 @@ package postgresql
 import (
 	"context"
 	"fmt"
+	"strconv"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"app/config"
+	"app/pkg/logger"
+	"app/pkg/tracing"
 	"app/storage"
 )
 
@@ -20,23 +24,57 @@ type Store struct {
 	staff    storage.StaffRepoI
 	order    storage.OrderRepoI
 	stock    storage.StockRepoI
+	movement storage.StockMovementRepoI
 	user     storage.UserRepoI
+	report   storage.ReportRepoI
+	idem     storage.IdempotencyRepoI
+	webhook  storage.WebhookRepoI
+	outbox   storage.OutboxRepoI
+	admin    storage.AdminRepoI
+	apiKey   storage.APIKeyRepoI
 }
 
-func NewConnectPostgresql(cfg *config.Config) (storage.StorageI, error) {
+func NewConnectPostgresql(cfg *config.Config, log logger.LoggerI) (storage.StorageI, error) {
 
-	config, err := pgxpool.ParseConfig(fmt.Sprintf(
-		"host=%s user=%s dbname=%s password=%s port=%s sslmode=disable",
-		cfg.PostgresHost,
-		cfg.PostgresUser,
-		cfg.PostgresDatabase,
-		cfg.PostgresPassword,
-		cfg.PostgresPort,
-	))
+	config, err := pgxpool.ParseConfig(connString(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	logLevel, err := pgx.LogLevelFromString(cfg.PostgresLogLevel)
 	if err != nil {
 		return nil, err
 	}
 
+	config.MaxConns = cfg.PostgresMaxConnections
+	config.MinConns = cfg.PostgresMinConnections
+	config.ConnConfig.ConnectTimeout = cfg.PostgresConnectTimeout
+
+	if cfg.PostgresMaxConnLifetime > 0 {
+		config.MaxConnLifetime = cfg.PostgresMaxConnLifetime
+	}
+
+	if cfg.PostgresMaxConnIdleTime > 0 {
+		config.MaxConnIdleTime = cfg.PostgresMaxConnIdleTime
+	}
+
+	if cfg.PostgresHealthCheckPeriod > 0 {
+		config.HealthCheckPeriod = cfg.PostgresHealthCheckPeriod
+	}
+
+	// the server stops the statements running longer, the request timeout only stops waiting for them
+	if cfg.PostgresStatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.PostgresStatementTimeout.Milliseconds(), 10)
+	}
+
+	config.ConnConfig.Logger = newPgxLogger(log, logLevel, tracing.Enabled(cfg))
+	config.ConnConfig.LogLevel = logLevel
+
+	// pgx reports the finished queries at the info level, the logger needs them for the spans
+	if tracing.Enabled(cfg) && logLevel < pgx.LogLevelInfo {
+		config.ConnConfig.LogLevel = pgx.LogLevelInfo
+	}
+
 	pgpool, err := pgxpool.ConnectConfig(context.Background(), config)
 	if err != nil {
 		return nil, err
@@ -52,14 +90,51 @@ func NewConnectPostgresql(cfg *config.Config) (storage.StorageI, error) {
 		staff:    NewStaffRepo(pgpool),
 		order:    NewOrderRepo(pgpool),
 		stock:    NewStockRepo(pgpool),
+		movement: NewStockMovementRepo(pgpool),
 		user:     NewUserRepo(pgpool),
+		report:   NewReportRepo(pgpool),
+		idem:     NewIdempotencyRepo(pgpool),
+		webhook:  NewWebhookRepo(pgpool),
+		outbox:   NewOutboxRepo(pgpool),
+		admin:    NewAdminRepo(pgpool),
 	}, nil
 }
 
+func connString(cfg *config.Config) string {
+	return fmt.Sprintf(
+		"host=%s user=%s dbname=%s password=%s port=%s sslmode=disable",
+		cfg.PostgresHost,
+		cfg.PostgresUser,
+		cfg.PostgresDatabase,
+		cfg.PostgresPassword,
+		cfg.PostgresPort,
+	)
+}
+
 func (s *Store) CloseDB() {
 	s.db.Close()
 }
 
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.Ping(ctx)
+}
+
+func (s *Store) Stats() storage.PoolStats {
+
+	stat := s.db.Stat()
+
+	return storage.PoolStats{
+		AcquiredConns:        stat.AcquiredConns(),
+		IdleConns:            stat.IdleConns(),
+		TotalConns:           stat.TotalConns(),
+		MaxConns:             stat.MaxConns(),
+		AcquireCount:         stat.AcquireCount(),
+		AcquireDuration:      stat.AcquireDuration(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+	}
+}
+
 func (s *Store) Category() storage.CategoryRepoI {
 
 	if s.category == nil {
@@ -132,6 +207,15 @@ func (s *Store) Stock() storage.StockRepoI {
 	return s.stock
 }
 
+func (s *Store) StockMovement() storage.StockMovementRepoI {
+
+	if s.movement == nil {
+		s.movement = NewStockMovementRepo(s.db)
+	}
+
+	return s.movement
+}
+
 func (s *Store) User() storage.UserRepoI {
 
 	if s.user == nil {
@@ -141,8 +225,62 @@ func (s *Store) User() storage.UserRepoI {
 	return s.user
 }
 
+func (s *Store) Report() storage.ReportRepoI {
+
+	if s.report == nil {
+		s.report = NewReportRepo(s.db)
+	}
+
+	return s.report
+}
+
+func (s *Store) Idempotency() storage.IdempotencyRepoI {
+
+	if s.idem == nil {
+		s.idem = NewIdempotencyRepo(s.db)
+	}
+
+	return s.idem
+}
+
+func (s *Store) Webhook() storage.WebhookRepoI {
+
+	if s.webhook == nil {
+		s.webhook = NewWebhookRepo(s.db)
+	}
+
+	return s.webhook
+}
+
+func (s *Store) Outbox() storage.OutboxRepoI {
+
+	if s.outbox == nil {
+		s.outbox = NewOutboxRepo(s.db)
+	}
+
+	return s.outbox
+}
+
 // GORM
 // ROW
 // SQLBUILDER
 // SQLX
 // PGXPOOL
+
+func (s *Store) Admin() storage.AdminRepoI {
+
+	if s.admin == nil {
+		s.admin = NewAdminRepo(s.db)
+	}
+
+	return s.admin
+}
+
+func (s *Store) APIKey() storage.APIKeyRepoI {
+
+	if s.apiKey == nil {
+		s.apiKey = NewAPIKeyRepo(s.db)
+	}
+
+	return s.apiKey
+}
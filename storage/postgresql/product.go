@@ -12,12 +12,12 @@ import (
 )
 
 type ProductRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewProductRepo(db *pgxpool.Pool) *ProductRepo {
 	return &ProductRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
@@ -251,8 +251,6 @@ func (r *ProductRepo) Patch(ctx context.Context, req *models.PatchRequest) (int6
 
 	req.Fields["id"] = req.ID
 
-	fmt.Println(req.Fields)
-
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
 
 	result, err := r.db.Exec(ctx, query, args...)
@@ -0,0 +1,299 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type ReportRepo struct {
+	db *DB
+}
+
+func NewReportRepo(db *pgxpool.Pool) *ReportRepo {
+	return &ReportRepo{
+		db: NewDB(db),
+	}
+}
+
+// Sales returns gross sales by order_date and refunds by return date for every store.
+func (r *ReportRepo) Sales(ctx context.Context, req *models.SalesReportRequest) (resp *models.SalesReportResponse, err error) {
+
+	resp = &models.SalesReportResponse{
+		From: req.From,
+		To:   req.To,
+	}
+
+	var (
+		query  string
+		args   = []interface{}{req.From, req.To}
+		filter = " WHERE TRUE "
+	)
+
+	query = `
+		SELECT
+			st.store_id,
+			st.store_name,
+			COALESCE(sales.orders_count, 0),
+			COALESCE(sales.gross_sales, 0),
+			COALESCE(refunds.refunds, 0)
+		FROM stores AS st
+		LEFT JOIN (
+			SELECT
+				o.store_id,
+				COUNT(DISTINCT o.order_id) AS orders_count,
+				SUM(oi.quantity * oi.list_price * (1 - oi.discount)) AS gross_sales
+			FROM orders AS o
+			JOIN order_items AS oi ON oi.order_id = o.order_id
+			WHERE o.order_date >= $1::date AND o.order_date <= $2::date
+			GROUP BY o.store_id
+		) AS sales ON sales.store_id = st.store_id
+		LEFT JOIN (
+			SELECT
+				o.store_id,
+				SUM(ret.refund_amount) AS refunds
+			FROM order_returns AS ret
+			JOIN orders AS o ON o.order_id = ret.order_id
+			WHERE ret.created_at::date >= $1::date AND ret.created_at::date <= $2::date
+			GROUP BY o.store_id
+		) AS refunds ON refunds.store_id = st.store_id
+	`
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		filter += fmt.Sprintf(" AND st.store_id = $%d ", len(args))
+	}
+
+	query += filter + " ORDER BY st.store_id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var store models.StoreSales
+		err = rows.Scan(
+			&store.Store_id,
+			&store.Store_name,
+			&store.Orders_count,
+			&store.Gross_sales,
+			&store.Refunds,
+		)
+		if err != nil {
+			return nil, err
+		}
+		store.Net_sales = store.Gross_sales - store.Refunds
+
+		resp.Gross_sales += store.Gross_sales
+		resp.Refunds += store.Refunds
+		resp.Net_sales += store.Net_sales
+		resp.Stores = append(resp.Stores, &store)
+	}
+
+	return resp, nil
+}
+
+// StaffPerformance returns the order statistics of every staff member over the order_date range.
+// Revenue is the net total of the orders that were not rejected, fulfilment time is counted
+// from order_date to shipped_date and a shipment is late when shipped_date is after required_date.
+// With Store_id only the orders of that store are counted.
+func (r *ReportRepo) StaffPerformance(ctx context.Context, req *models.StaffPerformanceRequest) (resp *models.StaffPerformanceResponse, err error) {
+
+	resp = &models.StaffPerformanceResponse{
+		From:     req.From,
+		To:       req.To,
+		Store_id: req.Store_id,
+	}
+
+	var (
+		query       string
+		args        = []interface{}{req.From, req.To}
+		orderFilter = " WHERE o.order_date >= $1::date AND o.order_date <= $2::date "
+		filter      = " WHERE TRUE "
+	)
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		orderFilter += fmt.Sprintf(" AND o.store_id = $%d ", len(args))
+		filter += fmt.Sprintf(" AND (sta.store_id = $%d OR perf.staff_id IS NOT NULL) ", len(args))
+	}
+
+	if req.Staff_id > 0 {
+		args = append(args, req.Staff_id)
+		filter += fmt.Sprintf(" AND sta.staff_id = $%d ", len(args))
+	}
+
+	query = `
+		SELECT
+			sta.staff_id,
+			COALESCE(sta.first_name, ''),
+			COALESCE(sta.last_name, ''),
+			COALESCE(sta.store_id, 0),
+			COALESCE(perf.orders_count, 0),
+			COALESCE(perf.revenue, 0),
+			COALESCE(perf.shipped_count, 0),
+			COALESCE(perf.fulfilment_days, 0),
+			COALESCE(perf.late_shipments, 0),
+			COALESCE(perf.rejected_count, 0)
+		FROM staffs AS sta
+		LEFT JOIN (
+			SELECT
+				o.staff_id,
+				COUNT(*) AS orders_count,
+				SUM(totals.net_total) FILTER (WHERE o.order_status <> 3) AS revenue,
+				COUNT(o.shipped_date) AS shipped_count,
+				SUM(o.shipped_date - o.order_date) AS fulfilment_days,
+				COUNT(*) FILTER (WHERE o.shipped_date > o.required_date) AS late_shipments,
+				COUNT(*) FILTER (WHERE o.order_status = 3) AS rejected_count
+			FROM orders AS o
+			LEFT JOIN LATERAL (
+				SELECT
+					COALESCE((SELECT SUM(oi.quantity * oi.list_price * (1 - oi.discount)) FROM order_items AS oi WHERE oi.order_id = o.order_id), 0) -
+					COALESCE((SELECT SUM(ret.refund_amount) FROM order_returns AS ret WHERE ret.order_id = o.order_id), 0) AS net_total
+			) AS totals ON TRUE
+		` + orderFilter + `
+			GROUP BY o.staff_id
+		) AS perf ON perf.staff_id = sta.staff_id
+	` + filter + `
+		ORDER BY sta.staff_id
+	`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fulfilmentDays int
+
+	for rows.Next() {
+
+		var (
+			staff models.StaffPerformance
+			days  int
+		)
+		err = rows.Scan(
+			&staff.Staff_id,
+			&staff.First_name,
+			&staff.Last_name,
+			&staff.Store_id,
+			&staff.Orders_count,
+			&staff.Revenue,
+			&staff.Shipped_count,
+			&days,
+			&staff.Late_shipments,
+			&staff.Rejected_count,
+		)
+		if err != nil {
+			return nil, err
+		}
+		staff.Avg_fulfilment_days = ratio(days, staff.Shipped_count)
+		staff.Rejection_rate = ratio(staff.Rejected_count, staff.Orders_count)
+
+		fulfilmentDays += days
+		resp.Orders_count += staff.Orders_count
+		resp.Revenue += staff.Revenue
+		resp.Shipped_count += staff.Shipped_count
+		resp.Late_shipments += staff.Late_shipments
+		resp.Rejected_count += staff.Rejected_count
+		resp.Staffs = append(resp.Staffs, &staff)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp.Avg_fulfilment_days = ratio(fulfilmentDays, resp.Shipped_count)
+	resp.Rejection_rate = ratio(resp.Rejected_count, resp.Orders_count)
+
+	return resp, nil
+}
+
+// Overdue counts the open orders past their required_date and the ones due within the at risk window for every store.
+func (r *ReportRepo) Overdue(ctx context.Context, req *models.OverdueReportRequest) (resp *models.OverdueReportResponse, err error) {
+
+	resp = &models.OverdueReportResponse{
+		At_risk_days: req.At_risk_days,
+	}
+
+	var (
+		query  string
+		args   = []interface{}{req.At_risk_days}
+		filter = " WHERE TRUE "
+	)
+
+	query = `
+		SELECT
+			st.store_id,
+			st.store_name,
+			COUNT(o.order_id) FILTER (WHERE o.required_date < CURRENT_DATE),
+			COUNT(o.order_id) FILTER (WHERE o.required_date >= CURRENT_DATE),
+			COALESCE(SUM(totals.total) FILTER (WHERE o.required_date < CURRENT_DATE), 0),
+			MIN(o.required_date) FILTER (WHERE o.required_date < CURRENT_DATE)
+		FROM stores AS st
+		LEFT JOIN orders AS o ON o.store_id = st.store_id
+			AND ` + openOrderCondition + `
+			AND o.required_date <= CURRENT_DATE + $1::int
+		LEFT JOIN LATERAL (
+			SELECT
+				COALESCE((SELECT SUM(oi.quantity * oi.list_price * (1 - oi.discount)) FROM order_items AS oi WHERE oi.order_id = o.order_id), 0) AS total
+		) AS totals ON TRUE
+	`
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		filter += fmt.Sprintf(" AND st.store_id = $%d ", len(args))
+	}
+
+	query += filter + " GROUP BY st.store_id, st.store_name ORDER BY st.store_id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var store models.StoreOverdue
+		err = rows.Scan(
+			&store.Store_id,
+			&store.Store_name,
+			&store.Overdue_count,
+			&store.At_risk_count,
+			&store.Overdue_total,
+			&store.Oldest_due,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Overdue_count += store.Overdue_count
+		resp.At_risk_count += store.At_risk_count
+		resp.Overdue_total += store.Overdue_total
+		resp.Stores = append(resp.Stores, &store)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ratio returns part/total rounded to two decimals, 0 when there is nothing to divide.
+func ratio(part, total int) float64 {
+
+	if total == 0 {
+		return 0
+	}
+
+	return math.Round(float64(part)/float64(total)*100) / 100
+}
@@ -12,12 +12,12 @@ import (
 )
 
 type StaffRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewStaffRepo(db *pgxpool.Pool) *StaffRepo {
 	return &StaffRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
@@ -41,7 +41,7 @@ func (r *StaffRepo) Create(ctx context.Context, req *models.CreateStaff) (string
 		)
 		VALUES (
 			(select max(staff_id)+1 as id from staffs), 
-			$1, $2, $3, $4, $5, $6, $7) returning staff_id
+			$1, $2, $3, $4, $5, $6, NULLIF($7, 0)) returning staff_id
 	`
 
 	err := r.db.QueryRow(ctx, query,
@@ -120,6 +120,7 @@ func (r *StaffRepo) GetList(ctx context.Context, req *models.GetListStaffRequest
 
 	var (
 		query  string
+		args   []interface{}
 		filter = " WHERE TRUE "
 		offset = " OFFSET 0"
 		limit  = " LIMIT 10"
@@ -128,19 +129,36 @@ func (r *StaffRepo) GetList(ctx context.Context, req *models.GetListStaffRequest
 	query = `
 		SELECT
 			COUNT(*) OVER(),
-			COALESCE(staff_id, 0), 
-			COALESCE(first_name, ''),
-			COALESCE(last_name, ''),
-			COALESCE(email, ''),
-			COALESCE(phone, ''),
-			COALESCE(active, 0),
-			COALESCE(store_id, 0),
-			COALESCE(manager_id, 0)
-		FROM staffs
+			COALESCE(sta.staff_id, 0), 
+			COALESCE(sta.first_name, ''),
+			COALESCE(sta.last_name, ''),
+			COALESCE(sta.email, ''),
+			COALESCE(sta.phone, ''),
+			COALESCE(sta.active, 0),
+			COALESCE(sta.store_id, 0),
+
+			COALESCE(sto.store_id, 0), 
+			COALESCE(sto.store_name, ''),
+			COALESCE(sto.phone, ''),
+			COALESCE(sto.email, ''),
+			COALESCE(sto.street, ''),
+			COALESCE(sto.city, ''),
+			COALESCE(sto.state, ''),
+			COALESCE(sto.zip_code, ''),
+
+			COALESCE(sta.manager_id, 0)
+		FROM staffs as sta join stores as sto 
+		ON sta.store_id = sto.store_id
 	`
 
 	if len(req.Search) > 0 {
-		filter += " AND staff_name ILIKE '%' || '" + req.Search + "' || '%' "
+		args = append(args, req.Search)
+		filter += fmt.Sprintf(" AND (sta.first_name || ' ' || sta.last_name) ILIKE '%%' || $%d || '%%' ", len(args))
+	}
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		filter += fmt.Sprintf(" AND sta.store_id = $%d ", len(args))
 	}
 
 	if req.Offset > 0 {
@@ -151,9 +169,9 @@ func (r *StaffRepo) GetList(ctx context.Context, req *models.GetListStaffRequest
 		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
 	}
 
-	query += filter + offset + limit
+	query += filter + " ORDER BY sta.staff_id " + offset + limit
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +180,7 @@ func (r *StaffRepo) GetList(ctx context.Context, req *models.GetListStaffRequest
 	for rows.Next() {
 
 		var staff models.Staff
+		staff.StoreData = &models.Store{}
 		err = rows.Scan(
 			&resp.Count,
 			&staff.Staff_id,
@@ -171,6 +190,14 @@ func (r *StaffRepo) GetList(ctx context.Context, req *models.GetListStaffRequest
 			&staff.Phone,
 			&staff.Active,
 			&staff.Store_id,
+			&staff.StoreData.Store_id,
+			&staff.StoreData.Store_name,
+			&staff.StoreData.Phone,
+			&staff.StoreData.Email,
+			&staff.StoreData.Street,
+			&staff.StoreData.City,
+			&staff.StoreData.State,
+			&staff.StoreData.Zip_code,
 			&staff.Manager_id,
 		)
 		if err != nil {
@@ -199,7 +226,7 @@ func (r *StaffRepo) Update(ctx context.Context, req *models.UpdateStaff) (int64,
 			phone = :phone,
 			active = :active,
 			store_id = :store_id,
-			manager_id = :manager_id
+			manager_id = NULLIF(:manager_id, 0)
 		WHERE staff_id = :staff_id
 	`
 
@@ -235,6 +262,11 @@ func (r *StaffRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64,
 	}
 
 	for key := range req.Fields {
+		// a 0 manager_id makes the staff member a top level manager, like in Update
+		if key == "manager_id" {
+			set += " manager_id = NULLIF(:manager_id, 0), "
+			continue
+		}
 		set += fmt.Sprintf(" %s = :%s, ", key, key)
 	}
 
@@ -248,8 +280,6 @@ func (r *StaffRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64,
 
 	req.Fields["id"] = req.ID
 
-	fmt.Println(req.Fields)
-
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
 
 	result, err := r.db.Exec(ctx, query, args...)
@@ -272,3 +302,184 @@ func (r *StaffRepo) Delete(ctx context.Context, req *models.StaffPrimaryKey) (in
 
 	return rows.RowsAffected(), nil
 }
+
+// GetManagers returns the manager chain of the staff member, from the direct manager up to the top.
+func (r *StaffRepo) GetManagers(ctx context.Context, req *models.StaffPrimaryKey) (*models.GetStaffHierarchyResponse, error) {
+
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT
+				m.staff_id, m.first_name, m.last_name, m.email, m.active, m.store_id, m.manager_id,
+				1 AS level,
+				ARRAY[s.staff_id, m.staff_id] AS path
+			FROM staffs AS s
+			JOIN staffs AS m ON m.staff_id = s.manager_id
+			WHERE s.staff_id = $1
+			UNION ALL
+			SELECT
+				m.staff_id, m.first_name, m.last_name, m.email, m.active, m.store_id, m.manager_id,
+				chain.level + 1,
+				chain.path || m.staff_id
+			FROM staffs AS m
+			JOIN chain ON m.staff_id = chain.manager_id
+			WHERE NOT m.staff_id = ANY(chain.path)
+		)
+		SELECT
+			staff_id,
+			COALESCE(first_name, ''),
+			COALESCE(last_name, ''),
+			COALESCE(email, ''),
+			COALESCE(active, 0),
+			COALESCE(store_id, 0),
+			COALESCE(manager_id, 0),
+			level
+		FROM chain
+		ORDER BY level
+	`
+
+	return r.getHierarchy(ctx, query, req.Staff_id)
+}
+
+// GetReports returns the direct reports of the staff member or, unless Direct is set, all the indirect reports too.
+func (r *StaffRepo) GetReports(ctx context.Context, req *models.GetStaffHierarchyRequest) (*models.GetStaffHierarchyResponse, error) {
+
+	var filter string
+
+	if req.Direct {
+		filter = " WHERE level = 1 "
+	}
+
+	query := `
+		WITH RECURSIVE reports AS (
+			SELECT
+				s.staff_id, s.first_name, s.last_name, s.email, s.active, s.store_id, s.manager_id,
+				1 AS level,
+				ARRAY[s.manager_id, s.staff_id] AS path
+			FROM staffs AS s
+			WHERE s.manager_id = $1
+			UNION ALL
+			SELECT
+				s.staff_id, s.first_name, s.last_name, s.email, s.active, s.store_id, s.manager_id,
+				reports.level + 1,
+				reports.path || s.staff_id
+			FROM staffs AS s
+			JOIN reports ON s.manager_id = reports.staff_id
+			WHERE NOT s.staff_id = ANY(reports.path)
+		)
+		SELECT
+			staff_id,
+			COALESCE(first_name, ''),
+			COALESCE(last_name, ''),
+			COALESCE(email, ''),
+			COALESCE(active, 0),
+			COALESCE(store_id, 0),
+			COALESCE(manager_id, 0),
+			level
+		FROM reports
+	` + filter + `
+		ORDER BY level, staff_id
+	`
+
+	return r.getHierarchy(ctx, query, req.Staff_id)
+}
+
+func (r *StaffRepo) getHierarchy(ctx context.Context, query string, staffId int) (resp *models.GetStaffHierarchyResponse, err error) {
+
+	resp = &models.GetStaffHierarchyResponse{}
+
+	rows, err := r.db.Query(ctx, query, staffId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var staff models.StaffHierarchy
+		err = rows.Scan(
+			&staff.Staff_id,
+			&staff.First_name,
+			&staff.Last_name,
+			&staff.Email,
+			&staff.Active,
+			&staff.Store_id,
+			&staff.Manager_id,
+			&staff.Level,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.Staffs = append(resp.Staffs, &staff)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp.Count = len(resp.Staffs)
+
+	return resp, nil
+}
+
+// HasManagerCycle reports whether making managerId the manager of staffId would create a cycle in the manager tree.
+func (r *StaffRepo) HasManagerCycle(ctx context.Context, staffId int, managerId int) (bool, error) {
+
+	if managerId <= 0 {
+		return false, nil
+	}
+
+	if staffId == managerId {
+		return true, nil
+	}
+
+	var found bool
+
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT staff_id, manager_id, ARRAY[staff_id] AS path
+			FROM staffs WHERE staff_id = $2
+			UNION ALL
+			SELECT s.staff_id, s.manager_id, chain.path || s.staff_id
+			FROM staffs AS s
+			JOIN chain ON s.staff_id = chain.manager_id
+			WHERE NOT s.staff_id = ANY(chain.path)
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE staff_id = $1)
+	`
+
+	err := r.db.QueryRow(ctx, query, staffId, managerId).Scan(&found)
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+func (r *StaffRepo) Reassign(ctx context.Context, req *models.ReassignStaff) (int64, error) {
+
+	var (
+		query string
+		args  = []interface{}{req.Staff_id, req.Store_id}
+		set   = " store_id = $2 "
+	)
+
+	if req.Manager_id != nil {
+		args = append(args, *req.Manager_id)
+		set += ", manager_id = NULLIF($3, 0) "
+	}
+
+	query = `
+		UPDATE
+			staffs
+		SET
+		` + set + `
+		WHERE staff_id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -12,22 +12,33 @@ import (
 )
 
 type StockRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewStockRepo(db *pgxpool.Pool) *StockRepo {
 	return &StockRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
-func (r *StockRepo) Create(ctx context.Context, req *models.CreateStock) (string, error) {
+func (r *StockRepo) Create(ctx context.Context, req *models.CreateStock) (*models.StockPrimaryKey, error) {
 
 	var (
 		query string
-		id    int
+		resp  models.StockPrimaryKey
 	)
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = setStockMovementContext(ctx, tx, models.StockMovementReceiving, "", req.Actor)
+	if err != nil {
+		return nil, err
+	}
+
 	query = `
 		INSERT INTO stocks(
 			store_id, 
@@ -35,81 +46,25 @@ func (r *StockRepo) Create(ctx context.Context, req *models.CreateStock) (string
 			quantity
 		)
 		VALUES ( 
-			$1, $2, $3) returning store_id
+			$1, $2, $3) returning store_id, product_id
 	`
 
-	err := r.db.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		req.Store_id,
 		req.Product_id,
 		req.Quantity,
-	).Scan(&id)
+	).Scan(&resp.Store_id, &resp.Product_id)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return fmt.Sprintf("%d", id), nil
-}
-
-func (r *StockRepo) GetByIdProductStock(ctx context.Context, storeId int, productId int) (resp *models.Stock, err error) {
-	resp = &models.Stock{}
-	resp.StoreData = &models.Store{}
-	resp.ProductData = &models.Product{}
-
-	query := `
-		SELECT 
-			s.store_id,
-
-			st.store_id, 
-			st.store_name,
-			COALESCE(st.phone, ''),
-			COALESCE(st.email, ''),
-			COALESCE(st.street, ''),
-			COALESCE(st.city, ''),
-			COALESCE(st.state, ''),
-			COALESCE(st.zip_code, ''),
-
-			s.product_id,
-
-			p.product_id, 
-			p.product_name, 
-			p.brand_id,
-			p.category_id,
-			p.model_year,
-			p.list_price,
-			
-			s.quantity
-		FROM stocks AS s
-		JOIN stores AS st ON st.store_id = s.store_id
-		JOIN products AS p ON p.product_id = s.product_id
-		WHERE s.store_id = $1 AND s.product_id = $2
-	`
-
-	err = r.db.QueryRow(ctx, query, storeId, productId).Scan(
-		&resp.Store_id,
-		&resp.StoreData.Store_id,
-		&resp.StoreData.Store_name,
-		&resp.StoreData.Phone,
-		&resp.StoreData.Email,
-		&resp.StoreData.Street,
-		&resp.StoreData.City,
-		&resp.StoreData.State,
-		&resp.StoreData.Zip_code,
-		&resp.Product_id,
-		&resp.ProductData.Product_id,
-		&resp.ProductData.Product_name,
-		&resp.ProductData.Brand_id,
-		&resp.ProductData.Category_id,
-		&resp.ProductData.Model_year,
-		&resp.ProductData.List_price,
-		&resp.Quantity,
-	)
+	err = tx.Commit(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, nil
-
+	return &resp, nil
 }
 
 func (r *StockRepo) GetByID(ctx context.Context, req *models.StockPrimaryKey) (*models.Stock, error) {
@@ -135,18 +90,20 @@ func (r *StockRepo) GetByID(ctx context.Context, req *models.StockPrimaryKey) (*
 
 			COALESCE(p.product_id, 0),
 			COALESCE(p.product_name, ''),
-			COALESCE(p.brand_id, 0),UpdateStock
+			COALESCE(p.brand_id, 0),
 			COALESCE(p.category_id, 0),
 			COALESCE(p.model_year, 0),
-			COALESCE(p.list_price, 0)
+			COALESCE(p.list_price, 0),
+
+			COALESCE(s.quantity, 0)
 		FROM stocks as s join stores as st 
 		ON s.store_id = st.store_id join products as p
 		ON s.product_id = p.product_id
-		WHERE s.store_id = $1
+		WHERE s.store_id = $1 AND s.product_id = $2
 	`
 	resp.StoreData = &models.Store{}
 	resp.ProductData = &models.Product{}
-	err := r.db.QueryRow(ctx, query, req.Store_id).Scan(
+	err := r.db.QueryRow(ctx, query, req.Store_id, req.Product_id).Scan(
 		&resp.Store_id,
 		&resp.StoreData.Store_id,
 		&resp.StoreData.Store_name,
@@ -163,6 +120,7 @@ func (r *StockRepo) GetByID(ctx context.Context, req *models.StockPrimaryKey) (*
 		&resp.ProductData.Category_id,
 		&resp.ProductData.Model_year,
 		&resp.ProductData.List_price,
+		&resp.Quantity,
 	)
 
 	if err != nil {
@@ -178,6 +136,7 @@ func (r *StockRepo) GetList(ctx context.Context, req *models.GetListStockRequest
 
 	var (
 		query  string
+		args   []interface{}
 		filter = " WHERE TRUE "
 		offset = " OFFSET 0"
 		limit  = " LIMIT 10"
@@ -213,7 +172,18 @@ func (r *StockRepo) GetList(ctx context.Context, req *models.GetListStockRequest
 	`
 
 	if len(req.Search) > 0 {
-		filter += " AND quantity ILIKE '%' || '" + req.Search + "' || '%' "
+		args = append(args, req.Search)
+		filter += fmt.Sprintf(" AND (p.product_name ILIKE '%%' || $%d || '%%' OR st.store_name ILIKE '%%' || $%d || '%%') ", len(args), len(args))
+	}
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		filter += fmt.Sprintf(" AND s.store_id = $%d ", len(args))
+	}
+
+	if req.Product_id > 0 {
+		args = append(args, req.Product_id)
+		filter += fmt.Sprintf(" AND s.product_id = $%d ", len(args))
 	}
 
 	if req.Offset > 0 {
@@ -224,9 +194,9 @@ func (r *StockRepo) GetList(ctx context.Context, req *models.GetListStockRequest
 		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
 	}
 
-	query += filter + offset + limit
+	query += filter + " ORDER BY s.store_id, s.product_id " + offset + limit
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -263,6 +233,62 @@ func (r *StockRepo) GetList(ctx context.Context, req *models.GetListStockRequest
 		resp.Stocks = append(resp.Stocks, &stock)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetAvailability returns the quantity of the product in every store that has a stock row for it.
+func (r *StockRepo) GetAvailability(ctx context.Context, req *models.ProductPrimaryKey) (*models.ProductAvailability, error) {
+
+	resp := &models.ProductAvailability{}
+
+	err := r.db.QueryRow(ctx, "SELECT product_id, product_name FROM products WHERE product_id = $1", req.Product_id).Scan(
+		&resp.Product_id,
+		&resp.Product_name,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			st.store_id,
+			st.store_name,
+			COALESCE(s.quantity, 0)
+		FROM stocks AS s
+		JOIN stores AS st ON st.store_id = s.store_id
+		WHERE s.product_id = $1
+		ORDER BY st.store_id
+	`
+
+	rows, err := r.db.Query(ctx, query, req.Product_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var store models.StoreAvailability
+		err = rows.Scan(
+			&store.Store_id,
+			&store.Store_name,
+			&store.Quantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.Quantity += store.Quantity
+		resp.Stores = append(resp.Stores, &store)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
@@ -278,7 +304,7 @@ func (r *StockRepo) Update(ctx context.Context, req *models.UpdateStock) (int64,
 			stocks
 		SET
 			quantity = :quantity
-		WHERE stock_id = :store_id AND product_id = :product_id
+		WHERE store_id = :store_id AND product_id = :product_id
 	`
 
 	params = map[string]interface{}{
@@ -288,7 +314,23 @@ func (r *StockRepo) Update(ctx context.Context, req *models.UpdateStock) (int64,
 	}
 	query, args := helper.ReplaceQueryParams(query, params)
 
-	result, err := r.db.Exec(ctx, query, args...)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = setStockMovementContext(ctx, tx, models.StockMovementAdjustment, "", req.Actor)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -296,7 +338,7 @@ func (r *StockRepo) Update(ctx context.Context, req *models.UpdateStock) (int64,
 	return result.RowsAffected(), nil
 }
 
-func (r *StockRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64, error) {
+func (r *StockRepo) Patch(ctx context.Context, req *models.PatchStock) (int64, error) {
 
 	var (
 		query string
@@ -308,7 +350,11 @@ func (r *StockRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64,
 	}
 
 	for key := range req.Fields {
-		set += fmt.Sprintf(" %s = :%s, ", key, key)
+		// store_id and product_id are the key of the row, only quantity can be patched
+		if key != "quantity" {
+			return 0, fmt.Errorf("field %s can not be patched", key)
+		}
+		set += fmt.Sprintf(" %s = :%s ", key, key)
 	}
 
 	query = `
@@ -316,16 +362,31 @@ func (r *StockRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64,
 			stocks
 		SET
 		` + set + `
-		WHERE store_id = :id
+		WHERE store_id = :store_id AND product_id = :product_id
 	`
 
-	req.Fields["id"] = req.ID
-
-	fmt.Println(req.Fields)
+	req.Fields["store_id"] = req.Store_id
+	req.Fields["product_id"] = req.Product_id
 
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
 
-	result, err := r.db.Exec(ctx, query, args...)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = setStockMovementContext(ctx, tx, models.StockMovementAdjustment, "", req.Actor)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -333,15 +394,79 @@ func (r *StockRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64,
 	return result.RowsAffected(), nil
 }
 
-func (r *StockRepo) Delete(ctx context.Context, req *models.StockPrimaryKey) (int64, error) {
+func (r *StockRepo) Delete(ctx context.Context, req *models.DeleteStock) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = setStockMovementContext(ctx, tx, models.StockMovementAdjustment, "", req.Actor)
+	if err != nil {
+		return 0, err
+	}
 
-	rows, err := r.db.Exec(ctx,
-		"DELETE FROM stocks WHERE store_id = $1", req.Store_id,
+	rows, err := tx.Exec(ctx,
+		"DELETE FROM stocks WHERE store_id = $1 AND product_id = $2", req.Store_id, req.Product_id,
 	)
 
 	if err != nil {
 		return rows.RowsAffected(), err
 	}
 
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
 	return rows.RowsAffected(), nil
 }
+
+func (r *StockRepo) Transfer(ctx context.Context, req *models.TransferStock) error {
+
+	if len(req.Reference_id) <= 0 {
+		req.Reference_id = fmt.Sprintf("transfer:%d:%d", req.From_store_id, req.To_store_id)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = setStockMovementContext(ctx, tx, models.StockMovementTransfer, req.Reference_id, req.Actor)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE
+			stocks
+		SET
+			quantity = quantity - $3
+		WHERE store_id = $1 AND product_id = $2 AND quantity >= $3
+	`, req.From_store_id, req.Product_id, req.Quantity)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() <= 0 {
+		return errors.New("not enough stock in source store")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO stocks(
+			store_id,
+			product_id,
+			quantity
+		)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (store_id, product_id) DO UPDATE SET quantity = COALESCE(stocks.quantity, 0) + EXCLUDED.quantity
+	`, req.To_store_id, req.Product_id, req.Quantity)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
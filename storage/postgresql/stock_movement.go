@@ -0,0 +1,192 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type StockMovementRepo struct {
+	db *DB
+}
+
+func NewStockMovementRepo(db *pgxpool.Pool) *StockMovementRepo {
+	return &StockMovementRepo{
+		db: NewDB(db),
+	}
+}
+
+// setStockMovementContext sets the transaction local settings read by the
+// record_stock_movement trigger when it writes a row into stock_movements.
+func setStockMovementContext(ctx context.Context, tx pgx.Tx, reason, referenceId, actor string) error {
+
+	_, err := tx.Exec(ctx, `
+		SELECT
+			set_config('app.stock_reason', $1, true),
+			set_config('app.stock_reference', $2, true),
+			set_config('app.stock_actor', $3, true)
+	`, reason, referenceId, actor)
+
+	return err
+}
+
+func (r *StockMovementRepo) GetList(ctx context.Context, req *models.GetListStockMovementRequest) (resp *models.GetListStockMovementResponse, err error) {
+
+	resp = &models.GetListStockMovementResponse{}
+
+	var (
+		query  string
+		args   []interface{}
+		filter = " WHERE TRUE "
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			movement_id,
+			store_id,
+			product_id,
+			quantity,
+			reason,
+			COALESCE(reference_id, ''),
+			COALESCE(actor, ''),
+			CAST(created_at::timestamp AS VARCHAR)
+		FROM stock_movements
+	`
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		filter += fmt.Sprintf(" AND store_id = $%d ", len(args))
+	}
+
+	if req.Product_id > 0 {
+		args = append(args, req.Product_id)
+		filter += fmt.Sprintf(" AND product_id = $%d ", len(args))
+	}
+
+	if len(req.Reason) > 0 {
+		args = append(args, req.Reason)
+		filter += fmt.Sprintf(" AND reason = $%d ", len(args))
+	}
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	query += filter + " ORDER BY movement_id DESC " + offset + limit
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var movement models.StockMovement
+		err = rows.Scan(
+			&resp.Count,
+			&movement.Movement_id,
+			&movement.Store_id,
+			&movement.Product_id,
+			&movement.Quantity,
+			&movement.Reason,
+			&movement.Reference_id,
+			&movement.Actor,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.StockMovements = append(resp.StockMovements, &movement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Reconcile recomputes the stock of every (store, product) pair from the ledger
+// and returns the pairs whose stocks.quantity differs from the ledger sum.
+func (r *StockMovementRepo) Reconcile(ctx context.Context, req *models.StockReconciliationRequest) (resp *models.StockReconciliationResponse, err error) {
+
+	resp = &models.StockReconciliationResponse{}
+
+	var (
+		query  string
+		args   []interface{}
+		filter = " WHERE COALESCE(s.quantity, 0) <> COALESCE(l.quantity, 0) "
+	)
+
+	query = `
+		SELECT
+			COALESCE(s.store_id, l.store_id),
+			COALESCE(s.product_id, l.product_id),
+			COALESCE(s.quantity, 0),
+			COALESCE(l.quantity, 0)
+		FROM stocks AS s
+		FULL OUTER JOIN (
+			SELECT
+				store_id,
+				product_id,
+				SUM(quantity) AS quantity
+			FROM stock_movements
+			GROUP BY store_id, product_id
+		) AS l ON l.store_id = s.store_id AND l.product_id = s.product_id
+	`
+
+	if req.Store_id > 0 {
+		args = append(args, req.Store_id)
+		filter += fmt.Sprintf(" AND COALESCE(s.store_id, l.store_id) = $%d ", len(args))
+	}
+
+	if req.Product_id > 0 {
+		args = append(args, req.Product_id)
+		filter += fmt.Sprintf(" AND COALESCE(s.product_id, l.product_id) = $%d ", len(args))
+	}
+
+	query += filter + " ORDER BY 1, 2"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var mismatch models.StockReconciliation
+		err = rows.Scan(
+			&mismatch.Store_id,
+			&mismatch.Product_id,
+			&mismatch.Quantity,
+			&mismatch.Ledger_quantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		mismatch.Difference = mismatch.Quantity - mismatch.Ledger_quantity
+		resp.Mismatches = append(resp.Mismatches, &mismatch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp.Count = len(resp.Mismatches)
+	resp.Consistent = resp.Count == 0
+
+	return resp, nil
+}
@@ -12,12 +12,12 @@ import (
 )
 
 type StoreRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewStoreRepo(db *pgxpool.Pool) *StoreRepo {
 	return &StoreRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
@@ -220,8 +220,6 @@ func (r *StoreRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64,
 
 	req.Fields["id"] = req.ID
 
-	fmt.Println(req.Fields)
-
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
 
 	result, err := r.db.Exec(ctx, query, args...)
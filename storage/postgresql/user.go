@@ -12,12 +12,12 @@ import (
 )
 
 type userRepo struct {
-	db *pgxpool.Pool
+	db *DB
 }
 
 func NewUserRepo(db *pgxpool.Pool) *userRepo {
 	return &userRepo{
-		db: db,
+		db: NewDB(db),
 	}
 }
 
@@ -29,9 +29,10 @@ func (r *userRepo) Create(ctx context.Context, req *models.CreateUser) (string,
 			name,
 			login,
 			password,
+			role,
 			updated_at
 		)
-		VALUES ($1,$2,$3,$4,now()) returning id
+		VALUES ($1,$2,$3,$4,COALESCE(NULLIF($5, ''), 'user'),now()) returning id
 	`
 
 	_, err := r.db.Exec(ctx, query,
@@ -39,6 +40,7 @@ func (r *userRepo) Create(ctx context.Context, req *models.CreateUser) (string,
 		req.Name,
 		req.Login,
 		req.Password,
+		req.Role,
 	)
 
 	if err != nil {
@@ -68,6 +70,7 @@ func (r *userRepo) GetByID(ctx context.Context, req *models.UserPrimaryKey) (*mo
 			name,
 			login,
 			password,
+			role,
 			CAST(created_at::timestamp AS VARCHAR),
 			CAST(updated_at::timestamp AS VARCHAR)
 		FROM users
@@ -79,6 +82,7 @@ func (r *userRepo) GetByID(ctx context.Context, req *models.UserPrimaryKey) (*mo
 		&user.Name,
 		&user.Login,
 		&user.Password,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -107,6 +111,7 @@ func (r *userRepo) GetList(ctx context.Context, req *models.GetListUserRequest)
 			COALESCE(name,''),
 			COALESCE(login,''),
 			COALESCE(password,''),
+			role,
 			CAST(created_at::timestamp AS VARCHAR),
 			CAST(updated_at::timestamp AS VARCHAR)
 		FROM users
@@ -141,6 +146,7 @@ func (r *userRepo) GetList(ctx context.Context, req *models.GetListUserRequest)
 			&user.Name,
 			&user.Login,
 			&user.Password,
+			&user.Role,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -187,6 +193,18 @@ func (r *userRepo) Update(ctx context.Context, req *models.UpdateUser) (int64, e
 	return result.RowsAffected(), nil
 }
 
+func (r *userRepo) SetPassword(ctx context.Context, req *models.UserPrimaryKey, password string) (int64, error) {
+
+	result, err := r.db.Exec(ctx,
+		"UPDATE users SET password = $2, updated_at = now() WHERE login = $1", req.Login, password,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
 func (r *userRepo) Delete(ctx context.Context, req *models.UserPrimaryKey) (int64, error) {
 
 	rows, err := r.db.Exec(ctx,
@@ -0,0 +1,364 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type WebhookRepo struct {
+	db *DB
+}
+
+func NewWebhookRepo(db *pgxpool.Pool) *WebhookRepo {
+	return &WebhookRepo{
+		db: NewDB(db),
+	}
+}
+
+func (r *WebhookRepo) Create(ctx context.Context, req *models.CreateWebhookSubscription) (string, error) {
+
+	var id int
+
+	query := `
+		INSERT INTO webhook_subscriptions(
+			url,
+			secret,
+			event_types
+		)
+		VALUES ($1, $2, $3) RETURNING subscription_id
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		req.Url,
+		req.Secret,
+		req.Event_types,
+	).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d", id), nil
+}
+
+func (r *WebhookRepo) GetByID(ctx context.Context, req *models.WebhookSubscriptionPrimaryKey) (resp *models.WebhookSubscription, err error) {
+
+	resp = &models.WebhookSubscription{}
+
+	query := `
+		SELECT
+			subscription_id,
+			url,
+			secret,
+			event_types,
+			active,
+			CAST(created_at::timestamp AS VARCHAR)
+		FROM webhook_subscriptions
+		WHERE subscription_id = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, req.Subscription_id).Scan(
+		&resp.Subscription_id,
+		&resp.Url,
+		&resp.Secret,
+		&resp.Event_types,
+		&resp.Active,
+		&resp.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *WebhookRepo) GetList(ctx context.Context, req *models.GetListWebhookSubscriptionRequest) (resp *models.GetListWebhookSubscriptionResponse, err error) {
+
+	resp = &models.GetListWebhookSubscriptionResponse{}
+
+	var (
+		query  string
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			subscription_id,
+			url,
+			event_types,
+			active,
+			CAST(created_at::timestamp AS VARCHAR)
+		FROM webhook_subscriptions
+	`
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	query += " ORDER BY subscription_id " + offset + limit
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var subscription models.WebhookSubscription
+		err = rows.Scan(
+			&resp.Count,
+			&subscription.Subscription_id,
+			&subscription.Url,
+			&subscription.Event_types,
+			&subscription.Active,
+			&subscription.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.Subscriptions = append(resp.Subscriptions, &subscription)
+	}
+
+	return resp, rows.Err()
+}
+
+func (r *WebhookRepo) Update(ctx context.Context, req *models.UpdateWebhookSubscription) (int64, error) {
+
+	query := `
+		UPDATE
+			webhook_subscriptions
+		SET
+			url = $2,
+			secret = COALESCE(NULLIF($3, ''), secret),
+			event_types = $4,
+			active = $5
+		WHERE subscription_id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		req.Subscription_id,
+		req.Url,
+		req.Secret,
+		req.Event_types,
+		req.Active,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *WebhookRepo) Delete(ctx context.Context, req *models.WebhookSubscriptionPrimaryKey) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE subscription_id = $1", req.Subscription_id)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetListDelivery returns the delivery log, the latest deliveries first.
+func (r *WebhookRepo) GetListDelivery(ctx context.Context, req *models.GetListWebhookDeliveryRequest) (resp *models.GetListWebhookDeliveryResponse, err error) {
+
+	resp = &models.GetListWebhookDeliveryResponse{}
+
+	var (
+		query  string
+		args   []interface{}
+		filter = " WHERE TRUE "
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			delivery_id,
+			subscription_id,
+			event_type,
+			payload,
+			status,
+			attempts,
+			CAST(next_attempt_at::timestamp AS VARCHAR),
+			COALESCE(response_code, 0),
+			COALESCE(last_error, ''),
+			COALESCE(CAST(delivered_at::timestamp AS VARCHAR), ''),
+			CAST(created_at::timestamp AS VARCHAR)
+		FROM webhook_deliveries
+	`
+
+	if req.Subscription_id > 0 {
+		args = append(args, req.Subscription_id)
+		filter += fmt.Sprintf(" AND subscription_id = $%d ", len(args))
+	}
+
+	if len(req.Status) > 0 {
+		args = append(args, req.Status)
+		filter += fmt.Sprintf(" AND status = $%d ", len(args))
+	}
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	query += filter + " ORDER BY delivery_id DESC " + offset + limit
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var (
+			delivery models.WebhookDelivery
+			payload  []byte
+		)
+		err = rows.Scan(
+			&resp.Count,
+			&delivery.Delivery_id,
+			&delivery.Subscription_id,
+			&delivery.Event_type,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.Next_attempt_at,
+			&delivery.Response_code,
+			&delivery.Last_error,
+			&delivery.Delivered_at,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = json.RawMessage(payload)
+		resp.Deliveries = append(resp.Deliveries, &delivery)
+	}
+
+	return resp, rows.Err()
+}
+
+func (r *WebhookRepo) Enqueue(ctx context.Context, req *models.WebhookEvent) (int64, error) {
+
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT subscription_id, $1, $2, $3
+		FROM webhook_subscriptions
+		WHERE active AND $2 = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	result, err := r.db.Exec(ctx, query, req.Event_id, req.Event_type, req.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// ClaimDeliveries counts the attempt and moves next_attempt_at past the lease in the claim,
+// so two webhook jobs never send the same delivery at once.
+func (r *WebhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.DueWebhookDelivery, error) {
+
+	query := `
+		WITH due AS (
+			UPDATE webhook_deliveries
+			SET
+				attempts = attempts + 1,
+				next_attempt_at = CURRENT_TIMESTAMP + $2::interval
+			WHERE delivery_id IN (
+				SELECT delivery_id
+				FROM webhook_deliveries
+				WHERE status = 'pending'
+					AND next_attempt_at <= CURRENT_TIMESTAMP
+					AND subscription_id IN (SELECT subscription_id FROM webhook_subscriptions WHERE active)
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING delivery_id, subscription_id, event_type, payload, attempts, created_at
+		)
+		SELECT
+			due.delivery_id,
+			due.event_type,
+			due.payload,
+			due.attempts,
+			CAST(due.created_at::timestamp AS VARCHAR),
+			s.url,
+			s.secret
+		FROM due
+		JOIN webhook_subscriptions AS s ON s.subscription_id = due.subscription_id
+		ORDER BY due.delivery_id
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, lease)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.DueWebhookDelivery
+
+	for rows.Next() {
+
+		var delivery models.DueWebhookDelivery
+		err = rows.Scan(
+			&delivery.Delivery_id,
+			&delivery.Event_type,
+			&delivery.Payload,
+			&delivery.Attempts,
+			&delivery.CreatedAt,
+			&delivery.Url,
+			&delivery.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *WebhookRepo) SaveAttempt(ctx context.Context, req *models.WebhookDeliveryAttempt) error {
+
+	query := `
+		UPDATE webhook_deliveries
+		SET
+			status = $2,
+			response_code = NULLIF($3, 0),
+			last_error = NULLIF($4, ''),
+			next_attempt_at = CURRENT_TIMESTAMP + $5::interval,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN CURRENT_TIMESTAMP END
+		WHERE delivery_id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		req.Delivery_id,
+		req.Status,
+		req.Response_code,
+		req.Error,
+		req.Retry_after,
+	)
+
+	return err
+}
@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"sync"
+	"time"
+)
+
+// Breaker states, also reported by CacheStore.Status
+const (
+	BreakerClosed   = "up"
+	BreakerOpen     = "down"
+	BreakerHalfOpen = "recovering"
+)
+
+// breaker stops calling redis after threshold consecutive failures. Once cooldown
+// has passed one trial call is let through, its success closes the breaker again.
+type breaker struct {
+	mu        sync.Mutex
+	state     string
+	failures  int
+	trial     bool
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	return &breaker{
+		state:     BreakerClosed,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow reports whether a call may be made now.
+func (b *breaker) allow() bool {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.trial = true
+		return true
+	case BreakerHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	}
+
+	return true
+}
+
+// done records the result of a call let through by allow.
+func (b *breaker) done(err error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// probe records the result of a ping. Unlike a failed call, which may be one slow command,
+// a failed ping means redis is not reachable and opens the breaker right away.
+func (b *breaker) probe(err error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures = b.threshold
+	if b.state != BreakerOpen {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *breaker) status() string {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errRedis = errors.New("redis is down")
+
+func TestBreakerStates(t *testing.T) {
+
+	b := newBreaker(2, time.Minute)
+
+	// cooled moves the opening of the breaker back past its cooldown
+	cooled := func() {
+		b.openedAt = time.Now().Add(-2 * time.Minute)
+	}
+
+	steps := []struct {
+		Name  string
+		Do    func() bool
+		Allow bool
+		State string
+	}{
+		{Name: "closed", Do: b.allow, Allow: true, State: BreakerClosed},
+		{Name: "failure under the threshold", Do: func() bool { b.done(errRedis); return b.allow() }, Allow: true, State: BreakerClosed},
+		{Name: "success resets the failures", Do: func() bool { b.done(nil); b.done(errRedis); return b.allow() }, Allow: true, State: BreakerClosed},
+		{Name: "failures at the threshold open it", Do: func() bool { b.done(errRedis); return b.allow() }, Allow: false, State: BreakerOpen},
+		{Name: "cooldown lets one trial through", Do: func() bool { cooled(); return b.allow() }, Allow: true, State: BreakerHalfOpen},
+		{Name: "no second trial", Do: b.allow, Allow: false, State: BreakerHalfOpen},
+		{Name: "failed trial opens it again", Do: func() bool { b.done(errRedis); return b.allow() }, Allow: false, State: BreakerOpen},
+		{Name: "successful trial closes it", Do: func() bool { cooled(); b.allow(); b.done(nil); return b.allow() }, Allow: true, State: BreakerClosed},
+		{Name: "failed ping opens it right away", Do: func() bool { b.probe(errRedis); return b.allow() }, Allow: false, State: BreakerOpen},
+		{Name: "successful ping closes it", Do: func() bool { b.probe(nil); return b.allow() }, Allow: true, State: BreakerClosed},
+	}
+
+	for _, step := range steps {
+
+		if allow := step.Do(); allow != step.Allow {
+			t.Errorf("%s: allow = %v, want %v", step.Name, allow, step.Allow)
+		}
+
+		if state := b.status(); state != step.State {
+			t.Errorf("%s: state = %s, want %s", step.Name, state, step.State)
+		}
+	}
+}
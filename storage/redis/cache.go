@@ -0,0 +1,187 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+
+	"app/pkg/tracing"
+	"app/storage"
+)
+
+// CacheRepo calls redis through the breaker, while it is open every call
+// fails fast with storage.ErrCacheUnavailable.
+type CacheRepo struct {
+	db      *redis.Client
+	breaker *breaker
+
+	// pending holds the entities whose version bump was lost while redis was
+	// unavailable, they are bumped before the next version is read.
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+func NewCacheRepo(db *redis.Client, breaker *breaker) *CacheRepo {
+	return &CacheRepo{
+		db:      db,
+		breaker: breaker,
+		pending: map[string]bool{},
+	}
+}
+
+func (r *CacheRepo) do(ctx context.Context, command string, fn func() error) error {
+	return do(ctx, r.breaker, command, fn)
+}
+
+// do runs fn in a span named after the redis command when the breaker allows it,
+// redis.Nil is a successful call.
+func do(ctx context.Context, breaker *breaker, command string, fn func() error) (err error) {
+
+	_, span := tracing.StartClient(ctx, "redis "+command, semconv.DBSystemRedis, semconv.DBOperation(command))
+	defer func() {
+		tracing.Fail(span, err)
+		span.End()
+	}()
+
+	if !breaker.allow() {
+		return storage.ErrCacheUnavailable
+	}
+
+	err = fn()
+	if err == redis.Nil {
+		err = nil
+	}
+
+	breaker.done(err)
+
+	return err
+}
+
+func (r *CacheRepo) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+
+	var body []byte
+
+	err := r.do(ctx, "GET", func() (err error) {
+		body, err = r.db.Get(key).Bytes()
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		return false, nil
+	}
+
+	err = json.Unmarshal(body, dest)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *CacheRepo) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return r.do(ctx, "SET", func() error {
+		return r.db.Set(key, body, ttl).Err()
+	})
+}
+
+func (r *CacheRepo) Delete(ctx context.Context, keys ...string) error {
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return r.do(ctx, "DEL", func() error {
+		return r.db.Del(keys...).Err()
+	})
+}
+
+func (r *CacheRepo) Version(ctx context.Context, entity string) (int64, error) {
+
+	err := r.bumpPending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+
+	err = r.do(ctx, "GET", func() (err error) {
+		version, err = r.db.Get(versionKey(entity)).Int64()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (r *CacheRepo) BumpVersion(ctx context.Context, entities ...string) error {
+
+	err := r.bump(ctx, entities)
+	if err != nil {
+		r.mu.Lock()
+		for _, entity := range entities {
+			r.pending[entity] = true
+		}
+		r.mu.Unlock()
+	}
+
+	return err
+}
+
+// bumpPending retries the version bumps lost while redis was unavailable, so the
+// entries cached before an outage are not served once redis is back.
+func (r *CacheRepo) bumpPending(ctx context.Context) error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil
+	}
+
+	entities := make([]string, 0, len(r.pending))
+	for entity := range r.pending {
+		entities = append(entities, entity)
+	}
+
+	err := r.bump(ctx, entities)
+	if err != nil {
+		return err
+	}
+
+	r.pending = map[string]bool{}
+
+	return nil
+}
+
+func (r *CacheRepo) bump(ctx context.Context, entities []string) error {
+
+	return r.do(ctx, "INCR", func() error {
+		pipe := r.db.TxPipeline()
+		for _, entity := range entities {
+			pipe.Incr(versionKey(entity))
+		}
+
+		_, err := pipe.Exec()
+
+		return err
+	})
+}
+
+func versionKey(entity string) string {
+	return "version:" + entity
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type LimiterRepo struct {
+	db      *redis.Client
+	breaker *breaker
+}
+
+func NewLimiterRepo(db *redis.Client, breaker *breaker) *LimiterRepo {
+	return &LimiterRepo{
+		db:      db,
+		breaker: breaker,
+	}
+}
+
+// Hit counts key in a counter of the current window, the counter expires with the window.
+func (r *LimiterRepo) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+
+	now := time.Now()
+	start := now.Truncate(window)
+	reset := start.Add(window).Sub(now)
+	key = fmt.Sprintf("ratelimit:%s:%d", key, start.Unix())
+
+	var incr *redis.IntCmd
+
+	err := do(ctx, r.breaker, "INCR", func() error {
+		pipe := r.db.TxPipeline()
+		incr = pipe.Incr(key)
+		pipe.Expire(key, window)
+
+		_, err := pipe.Exec()
+
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return incr.Val(), reset, nil
+}
+
+func (r *LimiterRepo) AddLoginFailure(ctx context.Context, login string, ttl time.Duration) (int64, error) {
+
+	var incr *redis.IntCmd
+
+	err := do(ctx, r.breaker, "INCR", func() error {
+		pipe := r.db.TxPipeline()
+		incr = pipe.Incr(loginFailuresKey(login))
+		pipe.Expire(loginFailuresKey(login), ttl)
+
+		_, err := pipe.Exec()
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (r *LimiterRepo) LockLogin(ctx context.Context, login string, duration time.Duration) error {
+
+	return do(ctx, r.breaker, "SET", func() error {
+		return r.db.Set(loginLockKey(login), 1, duration).Err()
+	})
+}
+
+func (r *LimiterRepo) LoginLock(ctx context.Context, login string) (time.Duration, error) {
+
+	var ttl time.Duration
+
+	err := do(ctx, r.breaker, "PTTL", func() (err error) {
+		ttl, err = r.db.PTTL(loginLockKey(login)).Result()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// PTTL is negative when the key does not exist or has no expiry
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (r *LimiterRepo) ResetLogin(ctx context.Context, login string) error {
+
+	return do(ctx, r.breaker, "DEL", func() error {
+		return r.db.Del(loginFailuresKey(login), loginLockKey(login)).Err()
+	})
+}
+
+func loginFailuresKey(login string) string {
+	return "login:failures:" + login
+}
+
+func loginLockKey(login string) string {
+	return "login:lock:" + login
+}
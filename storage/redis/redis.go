@@ -9,36 +9,64 @@ import (
 
 type CacheStore struct {
 	db      *redis.Client
-	product *ProductRepo
+	breaker *breaker
+	cache   *CacheRepo
+	limiter *LimiterRepo
 }
 
-func NewRedisCacheStorage(cfg config.Config) (storage.CacheStorageI, error) {
+// NewRedisCacheStorage does not connect eagerly, the service starts and serves
+// reads from postgresql while redis is unreachable.
+func NewRedisCacheStorage(cfg config.Config) storage.CacheStorageI {
 
 	client := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisAddr,
-		Password: cfg.RedisPassword,
-		DB:       cfg.RedisDB,
+		Addr:         cfg.RedisAddr,
+		Password:     cfg.RedisPassword,
+		DB:           cfg.RedisDB,
+		DialTimeout:  cfg.RedisTimeout,
+		ReadTimeout:  cfg.RedisTimeout,
+		WriteTimeout: cfg.RedisTimeout,
 	})
 
-	_, err := client.Ping().Result()
-	if err != nil {
-		return nil, err
-	}
+	breaker := newBreaker(cfg.RedisBreakerThreshold, cfg.RedisBreakerCooldown)
 
 	return &CacheStore{
 		db:      client,
-		product: NewProductRepo(client),
-	}, nil
+		breaker: breaker,
+		cache:   NewCacheRepo(client, breaker),
+		limiter: NewLimiterRepo(client, breaker),
+	}
 }
 
 func (c *CacheStore) CloseDB() {
 	c.db.Close()
 }
 
-func (c *CacheStore) ProductCache() storage.ProductCachaRepoI {
-	if c.product == nil {
-		c.product = NewProductRepo(c.db)
+// Ping checks redis whatever the state of the breaker and records the result in it, so a
+// failed ping at startup skips the cache until the cooldown has passed.
+func (c *CacheStore) Ping() error {
+
+	err := c.db.Ping().Err()
+	c.breaker.probe(err)
+
+	return err
+}
+
+func (c *CacheStore) Status() string {
+	return c.breaker.status()
+}
+
+func (c *CacheStore) Cache() storage.CacheRepoI {
+	if c.cache == nil {
+		c.cache = NewCacheRepo(c.db, c.breaker)
+	}
+
+	return c.cache
+}
+
+func (c *CacheStore) Limiter() storage.LimiterRepoI {
+	if c.limiter == nil {
+		c.limiter = NewLimiterRepo(c.db, c.breaker)
 	}
 
-	return c.product
+	return c.limiter
 }
@@ -3,10 +3,21 @@ package storage
 import (
 	"app/api/models"
 	"context"
+	"errors"
+	"time"
 )
 
+// ErrTimeout is returned by the repos when a query is stopped by the deadline of its context
+// or by POSTGRES_STATEMENT_TIMEOUT.
+var ErrTimeout = errors.New("storage: query timed out")
+
+// ErrHasReturns is returned when an order item, or its order, is removed while it has returns.
+var ErrHasReturns = errors.New("storage: the order item has returns")
+
 type StorageI interface {
 	CloseDB()
+	Ping(ctx context.Context) error
+	Stats() PoolStats
 	Category() CategoryRepoI
 	Brand() BrandRepoI
 	Product() ProductRepoI
@@ -15,7 +26,26 @@ type StorageI interface {
 	Staff() StaffRepoI
 	Order() OrderRepoI
 	Stock() StockRepoI
+	StockMovement() StockMovementRepoI
 	User() UserRepoI
+	Report() ReportRepoI
+	Idempotency() IdempotencyRepoI
+	Webhook() WebhookRepoI
+	Outbox() OutboxRepoI
+	Admin() AdminRepoI
+	APIKey() APIKeyRepoI
+}
+
+// PoolStats is a snapshot of the database connection pool.
+type PoolStats struct {
+	AcquiredConns        int32
+	IdleConns            int32
+	TotalConns           int32
+	MaxConns             int32
+	AcquireCount         int64
+	AcquireDuration      time.Duration
+	CanceledAcquireCount int64
+	EmptyAcquireCount    int64
 }
 
 type CategoryRepoI interface {
@@ -52,6 +82,7 @@ type CustomerRepoI interface {
 	Update(context.Context, *models.UpdateCustomer) (int64, error)
 	Patch(ctx context.Context, req *models.PatchRequest) (int64, error)
 	Delete(context.Context, *models.CustomerPrimaryKey) (int64, error)
+	GetSummary(context.Context, *models.CustomerPrimaryKey) (*models.CustomerSummary, error)
 }
 
 type StoreRepoI interface {
@@ -70,6 +101,10 @@ type StaffRepoI interface {
 	Update(context.Context, *models.UpdateStaff) (int64, error)
 	Patch(ctx context.Context, req *models.PatchRequest) (int64, error)
 	Delete(context.Context, *models.StaffPrimaryKey) (int64, error)
+	GetManagers(context.Context, *models.StaffPrimaryKey) (*models.GetStaffHierarchyResponse, error)
+	GetReports(context.Context, *models.GetStaffHierarchyRequest) (*models.GetStaffHierarchyResponse, error)
+	HasManagerCycle(ctx context.Context, staffId int, managerId int) (bool, error)
+	Reassign(context.Context, *models.ReassignStaff) (int64, error)
 }
 
 type OrderRepoI interface {
@@ -81,16 +116,29 @@ type OrderRepoI interface {
 	Delete(context.Context, *models.OrderPrimaryKey) (int64, error)
 	AddOrderItem(ctx context.Context, req *models.OrderItem) (string, error)
 	RemoveOrderItem(ctx context.Context, req *models.OrderItemPrimaryKey) (int64, error)
+	AddReturn(ctx context.Context, req *models.CreateOrderReturn) (string, error)
+	GetReturnByID(ctx context.Context, req *models.OrderReturnPrimaryKey) (*models.OrderReturn, error)
+	GetListReturn(ctx context.Context, req *models.GetListOrderReturnRequest) (*models.GetListOrderReturnResponse, error)
+	FlagOverdue(ctx context.Context) ([]*models.OverdueOrder, error)
+	// UnflagOverdue clears the overdue flag of an order whose notification was not sent, the
+	// next FlagOverdue returns it again.
+	UnflagOverdue(ctx context.Context, req *models.OrderPrimaryKey) error
 }
 
 type StockRepoI interface {
-	Create(context.Context, *models.CreateStock) (string, error)
+	Create(context.Context, *models.CreateStock) (*models.StockPrimaryKey, error)
 	GetByID(context.Context, *models.StockPrimaryKey) (*models.Stock, error)
 	GetList(context.Context, *models.GetListStockRequest) (*models.GetListStockResponse, error)
-	GetByIdProductStock(ctx context.Context, storeId int, productId int) (resp *models.Stock, err error)
+	GetAvailability(context.Context, *models.ProductPrimaryKey) (*models.ProductAvailability, error)
 	Update(context.Context, *models.UpdateStock) (int64, error)
-	Patch(ctx context.Context, req *models.PatchRequest) (int64, error)
-	Delete(context.Context, *models.StockPrimaryKey) (int64, error)
+	Patch(ctx context.Context, req *models.PatchStock) (int64, error)
+	Delete(context.Context, *models.DeleteStock) (int64, error)
+	Transfer(ctx context.Context, req *models.TransferStock) error
+}
+
+type StockMovementRepoI interface {
+	GetList(context.Context, *models.GetListStockMovementRequest) (*models.GetListStockMovementResponse, error)
+	Reconcile(context.Context, *models.StockReconciliationRequest) (*models.StockReconciliationResponse, error)
 }
 
 type UserRepoI interface {
@@ -98,5 +146,68 @@ type UserRepoI interface {
 	GetByID(context.Context, *models.UserPrimaryKey) (*models.User, error)
 	GetList(context.Context, *models.GetListUserRequest) (*models.GetListUserResponse, error)
 	Update(context.Context, *models.UpdateUser) (int64, error)
+	// SetPassword replaces the password hash of the user with the login of req.
+	SetPassword(ctx context.Context, req *models.UserPrimaryKey, password string) (int64, error)
 	Delete(context.Context, *models.UserPrimaryKey) (int64, error)
 }
+
+type ReportRepoI interface {
+	Sales(ctx context.Context, req *models.SalesReportRequest) (*models.SalesReportResponse, error)
+	StaffPerformance(ctx context.Context, req *models.StaffPerformanceRequest) (*models.StaffPerformanceResponse, error)
+	Overdue(ctx context.Context, req *models.OverdueReportRequest) (*models.OverdueReportResponse, error)
+}
+
+type IdempotencyRepoI interface {
+	// Reserve stores key as running unless a key of the last ttl exists, which is returned with
+	// reserved false. A key still running after lease is taken over, its request is gone.
+	Reserve(ctx context.Context, key *models.IdempotencyKey, ttl, lease time.Duration) (existing *models.IdempotencyKey, reserved bool, err error)
+	Complete(ctx context.Context, key *models.IdempotencyKey) error
+	Release(ctx context.Context, key string) error
+	// Purge deletes the keys reserved more than ttl ago.
+	Purge(ctx context.Context, ttl time.Duration) (int64, error)
+}
+
+type WebhookRepoI interface {
+	Create(context.Context, *models.CreateWebhookSubscription) (string, error)
+	GetByID(context.Context, *models.WebhookSubscriptionPrimaryKey) (*models.WebhookSubscription, error)
+	GetList(context.Context, *models.GetListWebhookSubscriptionRequest) (*models.GetListWebhookSubscriptionResponse, error)
+	Update(context.Context, *models.UpdateWebhookSubscription) (int64, error)
+	Delete(context.Context, *models.WebhookSubscriptionPrimaryKey) (int64, error)
+	GetListDelivery(context.Context, *models.GetListWebhookDeliveryRequest) (*models.GetListWebhookDeliveryResponse, error)
+	// Enqueue queues event for every active subscription to its type and returns how many
+	// deliveries it queued, an event queued before is skipped.
+	Enqueue(context.Context, *models.WebhookEvent) (int64, error)
+	// ClaimDeliveries returns up to limit pending deliveries that are due and hides them from
+	// the other claims for lease, an attempt not saved by then is sent again.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.DueWebhookDelivery, error)
+	SaveAttempt(context.Context, *models.WebhookDeliveryAttempt) error
+}
+
+type APIKeyRepoI interface {
+	Create(context.Context, *models.CreateAPIKey) (string, error)
+	GetByID(context.Context, *models.APIKeyPrimaryKey) (*models.APIKey, error)
+	GetList(context.Context, *models.GetListAPIKeyRequest) (*models.GetListAPIKeyResponse, error)
+	Update(context.Context, *models.UpdateAPIKey) (int64, error)
+	Revoke(context.Context, *models.APIKeyPrimaryKey) (int64, error)
+	// Authenticate returns the key of keyHash unless it is revoked or expired, and records it
+	// was used.
+	Authenticate(ctx context.Context, keyHash string) (*models.APIKey, error)
+}
+
+type OutboxRepoI interface {
+	// Relay calls publish for up to limit unpublished events in order and marks the published
+	// ones, it stops at the first publish error. It returns how many events were published.
+	Relay(ctx context.Context, limit int, publish func(context.Context, *models.OutboxEvent) error) (int, error)
+	// Purge deletes the events published more than retention ago.
+	Purge(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// AdminRepoI holds the maintenance operations of the admin CLI.
+type AdminRepoI interface {
+	// Seed runs the demo data script on a database without catalog data.
+	Seed(ctx context.Context, script string) error
+	// RecomputeStock fixes the stocks that drifted from the source of the request.
+	RecomputeStock(context.Context, *models.StockRecomputeRequest) (*models.StockRecomputeResponse, error)
+	// CheckIntegrity looks for the data the constraints and triggers let drift.
+	CheckIntegrity(context.Context, *models.IntegrityCheckRequest) (*models.IntegrityReport, error)
+}
@@ -0,0 +1,145 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"context"
+	"testing"
+)
+
+func TestRecomputeStockFromLedger(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Input   *models.StockRecomputeRequest
+		WantErr bool
+	}{
+		{
+			Name:  "Case 1",
+			Input: &models.StockRecomputeRequest{Source: models.StockRecomputeLedger},
+		},
+		{
+			Name:  "Case 2",
+			Input: &models.StockRecomputeRequest{Source: models.StockRecomputeLedger, Apply: true, Actor: "test"},
+		},
+		{
+			Name:    "Case 3",
+			Input:   &models.StockRecomputeRequest{Source: "invoices"},
+			WantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			resp, err := adminTestRepo.RecomputeStock(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			reconciliation, err := movementTestRepo.Reconcile(context.Background(), &models.StockReconciliationRequest{})
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			// a dry run changes nothing, an applied one leaves no stock off its ledger
+			if test.Input.Apply && !reconciliation.Consistent {
+				t.Errorf("%s: got: %d mismatches after applying %d changes", test.Name, reconciliation.Count, resp.Count)
+			}
+
+			if !test.Input.Apply && reconciliation.Count != resp.Count {
+				t.Errorf("%s: got: %v, expected: %v", test.Name, resp.Count, reconciliation.Count)
+			}
+		})
+	}
+}
+
+func TestRecomputeStockFromOrders(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input *models.StockRecomputeRequest
+	}{
+		{
+			Name:  "Case 1",
+			Input: &models.StockRecomputeRequest{Source: models.StockRecomputeOrders},
+		},
+		{
+			Name:  "Case 2",
+			Input: &models.StockRecomputeRequest{Source: models.StockRecomputeOrders, Store_id: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			resp, err := adminTestRepo.RecomputeStock(context.Background(), test.Input)
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			// the seeded items are older than the ledger, their sales are in its opening balance
+			for _, change := range resp.Changes {
+				if change.New_quantity >= change.Quantity {
+					t.Errorf("%s: got: %+v, expected a missing sale", test.Name, change)
+				}
+
+				if test.Input.Store_id > 0 && change.Store_id != test.Input.Store_id {
+					t.Errorf("%s: got: store %d, expected: %d", test.Name, change.Store_id, test.Input.Store_id)
+				}
+			}
+
+			if resp.Applied {
+				t.Errorf("%s: a dry run was applied", test.Name)
+			}
+		})
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input *models.IntegrityCheckRequest
+	}{
+		{
+			Name:  "Case 1",
+			Input: &models.IntegrityCheckRequest{},
+		},
+		{
+			Name:  "Case 2",
+			Input: &models.IntegrityCheckRequest{Fix_sql: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			resp, err := adminTestRepo.CheckIntegrity(context.Background(), test.Input)
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			count := 0
+			for _, issues := range resp.Checks {
+				count += issues
+			}
+
+			if count != resp.Count || resp.Consistent != (resp.Count == 0) {
+				t.Errorf("%s: got: %d issues in checks, %d in total", test.Name, count, resp.Count)
+			}
+
+			if !test.Input.Fix_sql && len(resp.Fix_sql) > 0 {
+				t.Errorf("%s: got fix sql without asking for it", test.Name)
+			}
+		})
+	}
+}
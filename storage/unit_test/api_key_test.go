@@ -0,0 +1,72 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"app/pkg/helper"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+
+	ctx := context.Background()
+
+	create := func(expiresAt string) (int, string) {
+
+		key, prefix, hash, err := helper.GenerateAPIKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := apiKeyTestRepo.Create(ctx, &models.CreateAPIKey{
+			Name:       "pos terminal",
+			Scopes:     []string{models.ScopeOrdersWrite},
+			Expires_at: expiresAt,
+			Prefix:     prefix,
+			Key_hash:   hash,
+		})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		ID, _ := strconv.Atoi(id)
+		return ID, key
+	}
+
+	id, key := create(time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	apiKey, err := apiKeyTestRepo.Authenticate(ctx, helper.HashAPIKey(key))
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if apiKey.Api_key_id != id || len(apiKey.Scopes) != 1 || apiKey.Scopes[0] != models.ScopeOrdersWrite {
+		t.Errorf("authenticate: got %+v", apiKey)
+	}
+
+	used, err := apiKeyTestRepo.GetByID(ctx, &models.APIKeyPrimaryKey{Api_key_id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used.Last_used_at == "" {
+		t.Error("authenticate: last_used_at is not set")
+	}
+
+	rowsAffected, err := apiKeyTestRepo.Revoke(ctx, &models.APIKeyPrimaryKey{Api_key_id: id})
+	if err != nil || rowsAffected != 1 {
+		t.Fatalf("revoke: rows %d, err %v", rowsAffected, err)
+	}
+
+	_, err = apiKeyTestRepo.Authenticate(ctx, helper.HashAPIKey(key))
+	if err == nil {
+		t.Error("authenticate: a revoked key is accepted")
+	}
+
+	_, expired := create(time.Now().Add(-time.Hour).Format(time.RFC3339))
+
+	_, err = apiKeyTestRepo.Authenticate(ctx, helper.HashAPIKey(expired))
+	if err == nil {
+		t.Error("authenticate: an expired key is accepted")
+	}
+}
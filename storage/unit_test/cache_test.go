@@ -0,0 +1,224 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"app/pkg/logger"
+	"app/storage/cachesync"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCacheRepo(t *testing.T) {
+
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test:%d:", time.Now().UnixNano())
+
+	err := cacheTestRepo.Set(ctx, prefix+"kept", &models.Brand{Brand_id: 1, Brand_name: "kept"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cacheTestRepo.Set(ctx, prefix+"expired", &models.Brand{Brand_id: 2, Brand_name: "expired"}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cacheTestRepo.Set(ctx, prefix+"deleted", &models.Brand{Brand_id: 3, Brand_name: "deleted"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cacheTestRepo.Delete(ctx, prefix+"deleted")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	tests := []struct {
+		Name    string
+		Input   string
+		Output  *models.Brand
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  prefix + "kept",
+			Output: &models.Brand{Brand_id: 1, Brand_name: "kept"},
+		},
+		{
+			Name:  "Case 2",
+			Input: prefix + "expired",
+		},
+		{
+			Name:  "Case 3",
+			Input: prefix + "deleted",
+		},
+		{
+			Name:  "Case 4",
+			Input: prefix + "missing",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			var brand models.Brand
+
+			ok, err := cacheTestRepo.Get(ctx, test.Input, &brand)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if ok != (test.Output != nil) {
+				t.Errorf("%s: got: found %v, expected: %+v", test.Name, ok, test.Output)
+				return
+			}
+
+			if ok && brand != *test.Output {
+				t.Errorf("%s: got: %+v, expected: %+v", test.Name, brand, test.Output)
+			}
+		})
+	}
+}
+
+func TestCacheRepoVersion(t *testing.T) {
+
+	ctx := context.Background()
+	entity := fmt.Sprintf("test:%d", time.Now().UnixNano())
+
+	tests := []struct {
+		Name    string
+		Input   int
+		Output  int64
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  0,
+			Output: 0,
+		},
+		{
+			Name:   "Case 2",
+			Input:  1,
+			Output: 1,
+		},
+		{
+			Name:   "Case 3",
+			Input:  2,
+			Output: 3,
+		},
+	}
+
+	// the cases run in order, each bump adds up with the ones before it
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			for i := 0; i < test.Input; i++ {
+				err := cacheTestRepo.BumpVersion(ctx, entity)
+				if err != nil {
+					t.Errorf("%s: got: %v", test.Name, err)
+					return
+				}
+			}
+
+			version, err := cacheTestRepo.Version(ctx, entity)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil || version != test.Output {
+				t.Errorf("%s: got: %d, %v, expected: %d", test.Name, version, err, test.Output)
+			}
+		})
+	}
+}
+
+func TestReadThrough(t *testing.T) {
+
+	ctx := context.Background()
+	log := logger.NewLogger("test", logger.LevelError)
+	key := fmt.Sprint(time.Now().UnixNano())
+
+	var loads int
+
+	load := func(dest **models.Brand) func() error {
+		return func() (err error) {
+			loads++
+			*dest, err = brandTestRepo.GetByID(ctx, &models.BrandPrimaryKey{Brand_id: 1})
+			return err
+		}
+	}
+
+	tests := []struct {
+		Name    string
+		Bump    bool
+		Output  int
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Output: 1,
+		},
+		{
+			Name:   "Case 2",
+			Output: 0,
+		},
+		{
+			Name:   "Case 3",
+			Bump:   true,
+			Output: 1,
+		},
+	}
+
+	// the cases run in order: a miss loads and caches the brand, the next read is served from
+	// the cache until the version of the brands is bumped
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			if test.Bump {
+				err := cacheTestRepo.BumpVersion(ctx, cachesync.Brand)
+				if err != nil {
+					t.Errorf("%s: got: %v", test.Name, err)
+					return
+				}
+			}
+
+			var brand *models.Brand
+			loads = 0
+
+			err := cachesync.ReadThrough(ctx, cacheTestRepo, log, cachesync.Brand, key, time.Minute, &brand, load(&brand))
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if loads != test.Output || brand == nil || brand.Brand_id != 1 {
+				t.Errorf("%s: got: %d loads of %+v, expected: %d loads", test.Name, loads, brand, test.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// createTestCustomer adds a customer without orders. The customer is inserted here, Create
+// does not return the id.
+func createTestCustomer(t *testing.T) int {
+
+	var id int
+
+	err := testPool.QueryRow(context.Background(),
+		"INSERT INTO customers(first_name, last_name, email) VALUES ('Test', 'Customer', 'customer@test.com') RETURNING customer_id",
+	).Scan(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+// createCustomerTestOrders adds a customer with a completed order of 200 and a pending order
+// of 50 in store 2, and a rejected order of 1000 in store 1.
+func createCustomerTestOrders(t *testing.T) int {
+
+	ctx := context.Background()
+	today := time.Now().Format("2006-01-02")
+
+	customerId := createTestCustomer(t)
+
+	orders := []struct {
+		status   int
+		store    int
+		quantity int
+		price    float64
+	}{
+		{status: models.OrderStatusCompleted, store: 2, quantity: 2, price: 100},
+		{status: models.OrderStatusPending, store: 2, quantity: 1, price: 50},
+		{status: models.OrderStatusRejected, store: 1, quantity: 1, price: 1000},
+	}
+
+	for _, order := range orders {
+
+		orderId, err := orderTestRepo.Create(ctx, &models.CreateOrder{
+			Customer_id:   customerId,
+			Order_status:  order.status,
+			Order_date:    today,
+			Required_date: today,
+			Store_id:      order.store,
+			Staff_id:      2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		orderID, _ := strconv.Atoi(orderId)
+
+		_, err = orderTestRepo.AddOrderItem(ctx, &models.OrderItem{
+			Order_id:   orderID,
+			Product_id: 1,
+			Quantity:   order.quantity,
+			List_price: order.price,
+			Actor:      "test",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return customerId
+}
+
+func TestGetSummaryCustomer(t *testing.T) {
+
+	customerId := createCustomerTestOrders(t)
+
+	noOrders := createTestCustomer(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.CustomerPrimaryKey
+		Output  *models.CustomerSummary
+		WantErr bool
+	}{
+		{
+			Name:  "Case 1",
+			Input: &models.CustomerPrimaryKey{Customer_id: customerId},
+			Output: &models.CustomerSummary{
+				Customer_id:        customerId,
+				Orders_count:       3,
+				Lifetime_spend:     250,
+				Favourite_store_id: 2,
+			},
+		},
+		{
+			Name:  "Case 2",
+			Input: &models.CustomerPrimaryKey{Customer_id: noOrders},
+			Output: &models.CustomerSummary{
+				Customer_id: noOrders,
+			},
+		},
+		{
+			Name:    "Case 3",
+			Input:   &models.CustomerPrimaryKey{Customer_id: -1},
+			WantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			summary, err := customerTestRepo.GetSummary(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if summary.Customer_id != test.Output.Customer_id ||
+				summary.Orders_count != test.Output.Orders_count ||
+				summary.Lifetime_spend != test.Output.Lifetime_spend ||
+				summary.Favourite_store_id != test.Output.Favourite_store_id {
+				t.Errorf("%s: got: %+v, expected: %+v", test.Name, summary, test.Output)
+			}
+
+			if (summary.Last_order_date == nil) != (test.Output.Orders_count == 0) {
+				t.Errorf("%s: got last order date: %v", test.Name, summary.Last_order_date)
+			}
+		})
+	}
+}
+
+func TestGetListOrderFilters(t *testing.T) {
+
+	customerId := createCustomerTestOrders(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.GetListOrderRequest
+		Output  int
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.GetListOrderRequest{Customer_id: customerId},
+			Output: 3,
+		},
+		{
+			Name:   "Case 2",
+			Input:  &models.GetListOrderRequest{Customer_id: customerId, Statuses: []int{models.OrderStatusRejected}},
+			Output: 1,
+		},
+		{
+			Name:   "Case 3",
+			Input:  &models.GetListOrderRequest{Customer_id: customerId, Statuses: []int{models.OrderStatusPending, models.OrderStatusCompleted}},
+			Output: 2,
+		},
+		{
+			Name:   "Case 4",
+			Input:  &models.GetListOrderRequest{Customer_id: customerId, Store_id: 1},
+			Output: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			test.Input.Limit = 10
+
+			resp, err := orderTestRepo.GetList(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if resp.Count != test.Output || len(resp.Orders) != test.Output {
+				t.Errorf("%s: got: %d orders, expected: %d", test.Name, resp.Count, test.Output)
+				return
+			}
+
+			for _, order := range resp.Orders {
+				if order.Customer_id != customerId {
+					t.Errorf("%s: got: order %d of customer %d", test.Name, order.Order_id, order.Customer_id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+
+	ctx := context.Background()
+
+	key := &models.IdempotencyKey{
+		Key:          uuid.NewString(),
+		Request_hash: "hash",
+	}
+
+	_, reserved, err := idemTestRepo.Reserve(ctx, key, time.Hour, time.Hour)
+	if err != nil || !reserved {
+		t.Fatalf("reserve: reserved %v, err %v", reserved, err)
+	}
+
+	existing, reserved, err := idemTestRepo.Reserve(ctx, key, time.Hour, time.Hour)
+	if err != nil || reserved {
+		t.Fatalf("retry while running: reserved %v, err %v", reserved, err)
+	}
+	if existing.Status != 0 {
+		t.Errorf("retry while running: got status %d", existing.Status)
+	}
+
+	key.Status = 201
+	key.Content_type = "application/json"
+	key.Body = []byte(`{"Status":201}`)
+
+	err = idemTestRepo.Complete(ctx, key)
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	existing, reserved, err = idemTestRepo.Reserve(ctx, key, time.Hour, time.Hour)
+	if err != nil || reserved {
+		t.Fatalf("retry: reserved %v, err %v", reserved, err)
+	}
+	if existing.Status != key.Status || string(existing.Body) != string(key.Body) {
+		t.Errorf("retry: got %d %s", existing.Status, existing.Body)
+	}
+
+	// a completed key is kept, Release only drops a running one
+	err = idemTestRepo.Release(ctx, key.Key)
+	if err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	_, reserved, err = idemTestRepo.Reserve(ctx, key, 0, time.Hour)
+	if err != nil || !reserved {
+		t.Errorf("expired key: reserved %v, err %v", reserved, err)
+	}
+
+	// the request reserving it crashed, its key is free once the lease is over
+	_, reserved, err = idemTestRepo.Reserve(ctx, key, time.Hour, 0)
+	if err != nil || !reserved {
+		t.Errorf("running key after its lease: reserved %v, err %v", reserved, err)
+	}
+
+	deleted, err := idemTestRepo.Purge(ctx, 0)
+	if err != nil || deleted < 1 {
+		t.Errorf("purge: deleted %d, err %v", deleted, err)
+	}
+
+	_, reserved, err = idemTestRepo.Reserve(ctx, key, time.Hour, time.Hour)
+	if err != nil || !reserved {
+		t.Errorf("purged key: reserved %v, err %v", reserved, err)
+	}
+}
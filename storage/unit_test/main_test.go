@@ -2,7 +2,9 @@ package unit_test
 
 import (
 	"app/config"
+	"app/storage"
 	"app/storage/postgresql"
+	"app/storage/redis"
 	"context"
 	"fmt"
 	"os"
@@ -12,18 +14,28 @@ import (
 )
 
 var (
+	testPool         *pgxpool.Pool
 	categoryTestRepo *postgresql.CategoryRepo
 	brandTestRepo    *postgresql.BrandRepo
 	productTestRepo  *postgresql.ProductRepo
 	stockTestRepo    *postgresql.StockRepo
+	movementTestRepo *postgresql.StockMovementRepo
 	customerTestRepo *postgresql.CustomerRepo
 	storeTestRepo    *postgresql.StoreRepo
 	staffTestRepo    *postgresql.StaffRepo
 	orderTestRepo    *postgresql.OrderRepo
+	idemTestRepo     *postgresql.IdempotencyRepo
+	adminTestRepo    *postgresql.AdminRepo
+	apiKeyTestRepo   *postgresql.APIKeyRepo
+	reportTestRepo   *postgresql.ReportRepo
+	cacheTestRepo    storage.CacheRepoI
 )
 
 func TestMain(m *testing.M) {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		panic(err)
+	}
 
 	config, err := pgxpool.ParseConfig(fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
@@ -44,14 +56,21 @@ func TestMain(m *testing.M) {
 		panic(pool)
 	}
 
+	testPool = pool
 	categoryTestRepo = postgresql.NewCategoryRepo(pool)
 	brandTestRepo = postgresql.NewBrandRepo(pool)
 	productTestRepo = postgresql.NewProductRepo(pool)
 	stockTestRepo = postgresql.NewStockRepo(pool)
+	movementTestRepo = postgresql.NewStockMovementRepo(pool)
 	customerTestRepo = postgresql.NewCustomerRepo(pool)
 	storeTestRepo = postgresql.NewStoreRepo(pool)
 	staffTestRepo = postgresql.NewStaffRepo(pool)
 	orderTestRepo = postgresql.NewOrderRepo(pool)
+	idemTestRepo = postgresql.NewIdempotencyRepo(pool)
+	adminTestRepo = postgresql.NewAdminRepo(pool)
+	apiKeyTestRepo = postgresql.NewAPIKeyRepo(pool)
+	reportTestRepo = postgresql.NewReportRepo(pool)
+	cacheTestRepo = redis.NewRedisCacheStorage(cfg).Cache()
 
 	os.Exit(m.Run())
 }
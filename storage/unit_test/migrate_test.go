@@ -0,0 +1,102 @@
+package unit_test
+
+import (
+	"app/config"
+	"app/pkg/logger"
+	"app/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const migrateTestDatabase = "store_migrate_test"
+
+// TestMigrateUpDown applies every migration on an empty database, reverts them all and
+// checks nothing they created is left behind.
+func TestMigrateUpDown(t *testing.T) {
+
+	ctx := context.Background()
+
+	_, err := testPool.Exec(ctx, "DROP DATABASE IF EXISTS "+migrateTestDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = testPool.Exec(ctx, "CREATE DATABASE "+migrateTestDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testPool.Exec(ctx, "DROP DATABASE IF EXISTS "+migrateTestDatabase)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.PostgresDatabase = migrateTestDatabase
+
+	migrator, err := postgresql.NewMigrator(&cfg, logger.NewLogger("test", logger.LevelError))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer migrator.Close()
+
+	err = migrator.Up()
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.Dirty || status.Version != status.Latest {
+		t.Fatalf("after up: got version %d dirty %t, want %d", status.Version, status.Dirty, status.Latest)
+	}
+
+	err = migrator.Down(0)
+	if err != nil {
+		t.Fatalf("down: %v", err)
+	}
+
+	connConfig := testPool.Config().ConnConfig.Copy()
+	connConfig.Database = migrateTestDatabase
+
+	db, err := pgx.ConnectConfig(ctx, connConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+
+	var leftovers []string
+
+	rows, err := db.Query(ctx, `
+		SELECT 'table ' || table_name FROM information_schema.tables
+		WHERE table_schema = 'public' AND table_name <> 'schema_migrations'
+		UNION ALL
+		SELECT 'function ' || routine_name FROM information_schema.routines
+		WHERE routine_schema = 'public'
+		UNION ALL
+		SELECT 'type ' || typname FROM pg_type
+		JOIN pg_namespace ON pg_namespace.oid = typnamespace
+		WHERE nspname = 'public' AND typtype IN ('e', 'd')
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leftovers = append(leftovers, name)
+	}
+
+	if len(leftovers) > 0 {
+		t.Errorf("left after down: %v", leftovers)
+	}
+}
@@ -0,0 +1,151 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"app/storage"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// createReturnTestItem adds an order of store 1 with one item of 2 products sold 10% off 100,
+// 180 is paid for it.
+func createReturnTestItem(t *testing.T) *models.OrderItemPrimaryKey {
+
+	ctx := context.Background()
+	today := time.Now().Format("2006-01-02")
+
+	orderId, err := orderTestRepo.Create(ctx, &models.CreateOrder{
+		Customer_id:   1,
+		Order_status:  1,
+		Order_date:    today,
+		Required_date: today,
+		Store_id:      1,
+		Staff_id:      2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order, _ := strconv.Atoi(orderId)
+
+	itemId, err := orderTestRepo.AddOrderItem(ctx, &models.OrderItem{
+		Order_id:   order,
+		Product_id: 1,
+		Quantity:   2,
+		List_price: 100,
+		Discount:   0.1,
+		Actor:      "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, _ := strconv.Atoi(itemId)
+
+	return &models.OrderItemPrimaryKey{Order_id: order, Item_id: item}
+}
+
+func TestAddReturn(t *testing.T) {
+
+	item := createReturnTestItem(t)
+
+	refund := func(amount float64) *float64 {
+		return &amount
+	}
+
+	// the cases run in order, each return adds up with the ones before it
+	tests := []struct {
+		Name    string
+		Input   *models.CreateOrderReturn
+		Output  float64
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.CreateOrderReturn{Quantity: 1, Reason: "damaged"},
+			Output: 90,
+		},
+		{
+			Name:    "Case 2",
+			Input:   &models.CreateOrderReturn{Quantity: 2},
+			WantErr: true,
+		},
+		{
+			Name:    "Case 3",
+			Input:   &models.CreateOrderReturn{Quantity: 1, Refund_amount: refund(100)},
+			WantErr: true,
+		},
+		{
+			Name:   "Case 4",
+			Input:  &models.CreateOrderReturn{Quantity: 1, Refund_amount: refund(90)},
+			Output: 90,
+		},
+		{
+			Name:    "Case 5",
+			Input:   &models.CreateOrderReturn{Quantity: 1, Refund_amount: refund(0)},
+			WantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			test.Input.Order_id = item.Order_id
+			test.Input.Item_id = item.Item_id
+
+			id, err := orderTestRepo.AddReturn(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			returnId, _ := strconv.Atoi(id)
+
+			orderReturn, err := orderTestRepo.GetReturnByID(context.Background(), &models.OrderReturnPrimaryKey{Return_id: returnId})
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if orderReturn.Refund_amount != test.Output || orderReturn.Quantity != test.Input.Quantity {
+				t.Errorf("%s: got: %+v, expected: refund %v", test.Name, orderReturn, test.Output)
+			}
+		})
+	}
+
+	returns, err := orderTestRepo.GetListReturn(context.Background(), &models.GetListOrderReturnRequest{Order_id: item.Order_id, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var refunded float64
+	for _, orderReturn := range returns.OrderReturns {
+		refunded += orderReturn.Refund_amount
+	}
+
+	if returns.Count != 2 || refunded != 180 {
+		t.Errorf("got: %d returns refunding %v, expected: 2 refunding 180", returns.Count, refunded)
+	}
+
+	// the returns keep their item and order
+	_, err = orderTestRepo.RemoveOrderItem(context.Background(), item)
+	if !errors.Is(err, storage.ErrHasReturns) {
+		t.Errorf("RemoveOrderItem: got: %v, expected: %v", err, storage.ErrHasReturns)
+	}
+
+	_, err = orderTestRepo.Delete(context.Background(), &models.OrderPrimaryKey{Order_id: item.Order_id})
+	if !errors.Is(err, storage.ErrHasReturns) {
+		t.Errorf("Delete: got: %v, expected: %v", err, storage.ErrHasReturns)
+	}
+}
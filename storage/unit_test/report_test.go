@@ -0,0 +1,239 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// createPerformanceTestOrders adds a staff member of store 1 with, in January 2090, two
+// completed orders of store 1 shipped in 2 and 4 days, the second one late, and a rejected
+// order of store 2. The orders are far in the future, away from the orders of the seed data.
+func createPerformanceTestOrders(t *testing.T) int {
+
+	ctx := context.Background()
+
+	id, err := staffTestRepo.Create(ctx, &models.CreateStaff{
+		First_name: "Test",
+		Last_name:  "Performance",
+		Email:      fmt.Sprintf("performance.%d@test.com", time.Now().UnixNano()),
+		Active:     "1",
+		Store_id:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	staffId, _ := strconv.Atoi(id)
+
+	orders := []struct {
+		order    *models.CreateOrder
+		quantity int
+		price    float64
+	}{
+		{
+			order:    &models.CreateOrder{Order_status: models.OrderStatusCompleted, Order_date: "2090-01-01", Required_date: "2090-01-05", Shipped_date: "2090-01-03", Store_id: 1},
+			quantity: 2,
+			price:    100,
+		},
+		{
+			order:    &models.CreateOrder{Order_status: models.OrderStatusCompleted, Order_date: "2090-01-01", Required_date: "2090-01-02", Shipped_date: "2090-01-05", Store_id: 1},
+			quantity: 1,
+			price:    50,
+		},
+		{
+			order:    &models.CreateOrder{Order_status: models.OrderStatusRejected, Order_date: "2090-01-10", Required_date: "2090-01-15", Store_id: 2},
+			quantity: 1,
+			price:    1000,
+		},
+	}
+
+	for _, order := range orders {
+
+		order.order.Customer_id = 1
+		order.order.Staff_id = staffId
+
+		orderId, err := orderTestRepo.Create(ctx, order.order)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		orderID, _ := strconv.Atoi(orderId)
+
+		_, err = orderTestRepo.AddOrderItem(ctx, &models.OrderItem{
+			Order_id:   orderID,
+			Product_id: 1,
+			Quantity:   order.quantity,
+			List_price: order.price,
+			Actor:      "test",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return staffId
+}
+
+func TestStaffPerformance(t *testing.T) {
+
+	staffId := createPerformanceTestOrders(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.StaffPerformanceRequest
+		Output  *models.StaffPerformance
+		WantErr bool
+	}{
+		{
+			Name:  "Case 1",
+			Input: &models.StaffPerformanceRequest{From: "2090-01-01", To: "2090-01-31", Staff_id: staffId},
+			Output: &models.StaffPerformance{
+				Staff_id:            staffId,
+				Store_id:            1,
+				Orders_count:        3,
+				Revenue:             250,
+				Shipped_count:       2,
+				Avg_fulfilment_days: 3,
+				Late_shipments:      1,
+				Rejected_count:      1,
+				Rejection_rate:      0.33,
+			},
+		},
+		{
+			Name:  "Case 2",
+			Input: &models.StaffPerformanceRequest{From: "2090-01-01", To: "2090-01-05", Staff_id: staffId},
+			Output: &models.StaffPerformance{
+				Staff_id:            staffId,
+				Store_id:            1,
+				Orders_count:        2,
+				Revenue:             250,
+				Shipped_count:       2,
+				Avg_fulfilment_days: 3,
+				Late_shipments:      1,
+			},
+		},
+		{
+			Name:  "Case 3",
+			Input: &models.StaffPerformanceRequest{From: "2091-01-01", To: "2091-01-31", Staff_id: staffId},
+			Output: &models.StaffPerformance{
+				Staff_id: staffId,
+				Store_id: 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			resp, err := reportTestRepo.StaffPerformance(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if len(resp.Staffs) != 1 {
+				t.Errorf("%s: got: %d staffs, expected: 1", test.Name, len(resp.Staffs))
+				return
+			}
+
+			staff := resp.Staffs[0]
+			staff.First_name, staff.Last_name = "", ""
+
+			if *staff != *test.Output {
+				t.Errorf("%s: got: %+v, expected: %+v", test.Name, staff, test.Output)
+			}
+		})
+	}
+}
+
+func TestStorePerformance(t *testing.T) {
+
+	staffId := createPerformanceTestOrders(t)
+
+	// the other tests add orders in the range too, only the row of the staff member is checked
+	tests := []struct {
+		Name    string
+		Input   *models.StaffPerformanceRequest
+		Output  *models.StaffPerformance
+		WantErr bool
+	}{
+		{
+			Name:  "Case 1",
+			Input: &models.StaffPerformanceRequest{From: "2090-01-01", To: "2090-01-31", Store_id: 1},
+			Output: &models.StaffPerformance{
+				Staff_id:            staffId,
+				Store_id:            1,
+				Orders_count:        2,
+				Revenue:             250,
+				Shipped_count:       2,
+				Avg_fulfilment_days: 3,
+				Late_shipments:      1,
+			},
+		},
+		{
+			Name:  "Case 2",
+			Input: &models.StaffPerformanceRequest{From: "2090-01-01", To: "2090-01-31", Store_id: 2},
+			Output: &models.StaffPerformance{
+				Staff_id:       staffId,
+				Store_id:       1,
+				Orders_count:   1,
+				Rejected_count: 1,
+				Rejection_rate: 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			resp, err := reportTestRepo.StaffPerformance(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			// the staff member is listed for the orders they took in another store too
+			var staff *models.StaffPerformance
+			for _, s := range resp.Staffs {
+				if s.Staff_id == staffId {
+					staff = s
+				}
+			}
+
+			if staff == nil {
+				t.Errorf("%s: staff %d is not listed", test.Name, staffId)
+				return
+			}
+
+			staff.First_name, staff.Last_name = "", ""
+
+			if *staff != *test.Output {
+				t.Errorf("%s: got: %+v, expected: %+v", test.Name, staff, test.Output)
+			}
+
+			if resp.Orders_count < staff.Orders_count || resp.Store_id != test.Input.Store_id {
+				t.Errorf("%s: got totals: %+v", test.Name, resp)
+			}
+		})
+	}
+}
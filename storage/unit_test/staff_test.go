@@ -0,0 +1,277 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// createStaffTestChain adds three staff members of store 1, each managing the next one,
+// and returns their ids from the top.
+func createStaffTestChain(t *testing.T) []int {
+
+	var (
+		ids     []int
+		manager int
+		suffix  = time.Now().UnixNano()
+	)
+
+	for i := 0; i < 3; i++ {
+
+		id, err := staffTestRepo.Create(context.Background(), &models.CreateStaff{
+			First_name: "Test",
+			Last_name:  "Staff",
+			Email:      fmt.Sprintf("staff%d.%d@test.com", i, suffix),
+			Active:     "1",
+			Store_id:   1,
+			Manager_id: manager,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		manager, _ = strconv.Atoi(id)
+		ids = append(ids, manager)
+	}
+
+	return ids
+}
+
+func TestGetManagersStaff(t *testing.T) {
+
+	chain := createStaffTestChain(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.StaffPrimaryKey
+		Output  []int
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.StaffPrimaryKey{Staff_id: chain[2]},
+			Output: []int{chain[1], chain[0]},
+		},
+		{
+			Name:   "Case 2",
+			Input:  &models.StaffPrimaryKey{Staff_id: chain[0]},
+			Output: []int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			resp, err := staffTestRepo.GetManagers(context.Background(), test.Input)
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if got := hierarchyIds(resp); fmt.Sprint(got) != fmt.Sprint(test.Output) {
+				t.Errorf("%s: got: %v, expected: %v", test.Name, got, test.Output)
+			}
+		})
+	}
+}
+
+func TestGetReportsStaff(t *testing.T) {
+
+	chain := createStaffTestChain(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.GetStaffHierarchyRequest
+		Output  []int
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.GetStaffHierarchyRequest{Staff_id: chain[0]},
+			Output: []int{chain[1], chain[2]},
+		},
+		{
+			Name:   "Case 2",
+			Input:  &models.GetStaffHierarchyRequest{Staff_id: chain[0], Direct: true},
+			Output: []int{chain[1]},
+		},
+		{
+			Name:   "Case 3",
+			Input:  &models.GetStaffHierarchyRequest{Staff_id: chain[2]},
+			Output: []int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			resp, err := staffTestRepo.GetReports(context.Background(), test.Input)
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if got := hierarchyIds(resp); fmt.Sprint(got) != fmt.Sprint(test.Output) {
+				t.Errorf("%s: got: %v, expected: %v", test.Name, got, test.Output)
+			}
+		})
+	}
+}
+
+func TestManagerCycleStaff(t *testing.T) {
+
+	chain := createStaffTestChain(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.PatchRequest
+		Output  bool
+		WantErr bool
+	}{
+		{
+			Name:    "Case 1",
+			Input:   &models.PatchRequest{ID: chain[0], Fields: map[string]interface{}{"manager_id": chain[2]}},
+			Output:  true,
+			WantErr: true,
+		},
+		{
+			Name:    "Case 2",
+			Input:   &models.PatchRequest{ID: chain[1], Fields: map[string]interface{}{"manager_id": chain[1]}},
+			Output:  true,
+			WantErr: true,
+		},
+		{
+			Name:   "Case 3",
+			Input:  &models.PatchRequest{ID: chain[2], Fields: map[string]interface{}{"manager_id": chain[0]}},
+			Output: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			managerId := test.Input.Fields["manager_id"].(int)
+
+			cycle, err := staffTestRepo.HasManagerCycle(context.Background(), test.Input.ID, managerId)
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if cycle != test.Output {
+				t.Errorf("%s: got: %v, expected: %v", test.Name, cycle, test.Output)
+			}
+
+			// the trigger refuses the cycles the handlers did not check
+			_, err = staffTestRepo.Patch(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+			}
+		})
+	}
+}
+
+func TestPatchManagerStaff(t *testing.T) {
+
+	chain := createStaffTestChain(t)
+
+	// a 0 manager_id makes the staff member a top level manager
+	_, err := staffTestRepo.Patch(context.Background(), &models.PatchRequest{
+		ID:     chain[1],
+		Fields: map[string]interface{}{"manager_id": 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := staffTestRepo.GetManagers(context.Background(), &models.StaffPrimaryKey{Staff_id: chain[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Count != 0 {
+		t.Errorf("got: %v managers, expected: none", hierarchyIds(resp))
+	}
+}
+
+func TestReassignStaff(t *testing.T) {
+
+	chain := createStaffTestChain(t)
+
+	manager := func(id int) *int {
+		return &id
+	}
+
+	tests := []struct {
+		Name    string
+		Input   *models.ReassignStaff
+		Output  *models.Staff
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.ReassignStaff{Staff_id: chain[2], Store_id: 2},
+			Output: &models.Staff{Staff_id: chain[2], Store_id: 2, Manager_id: chain[1]},
+		},
+		{
+			Name:   "Case 2",
+			Input:  &models.ReassignStaff{Staff_id: chain[2], Store_id: 2, Manager_id: manager(0)},
+			Output: &models.Staff{Staff_id: chain[2], Store_id: 2, Manager_id: 0},
+		},
+		{
+			Name:   "Case 3",
+			Input:  &models.ReassignStaff{Staff_id: chain[2], Store_id: 1, Manager_id: manager(chain[0])},
+			Output: &models.Staff{Staff_id: chain[2], Store_id: 1, Manager_id: chain[0]},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			_, err := staffTestRepo.Reassign(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			staff, err := staffTestRepo.GetByID(context.Background(), &models.StaffPrimaryKey{Staff_id: test.Input.Staff_id})
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if staff.Store_id != test.Output.Store_id || staff.Manager_id != test.Output.Manager_id {
+				t.Errorf("%s: got: %+v, expected: %+v", test.Name, staff, test.Output)
+			}
+		})
+	}
+}
+
+func hierarchyIds(resp *models.GetStaffHierarchyResponse) []int {
+
+	ids := []int{}
+	for _, staff := range resp.Staffs {
+		ids = append(ids, staff.Staff_id)
+	}
+
+	return ids
+}
@@ -0,0 +1,100 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"context"
+	"testing"
+)
+
+func TestTransferStockMovement(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Input   *models.TransferStock
+		Output  int
+		WantErr bool
+	}{
+		{
+			Name: "Case 1",
+			Input: &models.TransferStock{
+				From_store_id: 1,
+				To_store_id:   2,
+				Product_id:    1,
+				Quantity:      1,
+			},
+			Output:  2,
+			WantErr: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			err := stockTestRepo.Transfer(context.Background(), test.Input)
+
+			if test.WantErr {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			movements, err := movementTestRepo.GetList(context.Background(), &models.GetListStockMovementRequest{
+				Product_id: test.Input.Product_id,
+				Reason:     models.StockMovementTransfer,
+				Limit:      test.Output,
+			})
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if len(movements.StockMovements) != test.Output {
+				t.Errorf("%s: got: %v, expected: %v", test.Name, len(movements.StockMovements), test.Output)
+				return
+			}
+
+		})
+	}
+}
+
+func TestReconcileStockMovement(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Input   *models.StockReconciliationRequest
+		Output  bool
+		WantErr bool
+	}{
+		{
+			Name:    "Case 1",
+			Input:   &models.StockReconciliationRequest{},
+			Output:  true,
+			WantErr: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			resp, err := movementTestRepo.Reconcile(context.Background(), test.Input)
+
+			if test.WantErr {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if resp.Consistent != test.Output {
+				t.Errorf("%s: got: %v, expected: %v", test.Name, resp.Mismatches, test.Output)
+				return
+			}
+
+		})
+	}
+}
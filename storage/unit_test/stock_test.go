@@ -0,0 +1,231 @@
+package unit_test
+
+import (
+	"app/api/models"
+	"context"
+	"strconv"
+	"testing"
+)
+
+// createStockTestProduct adds a product stocked with 10 in store 1 and 20 in store 2.
+func createStockTestProduct(t *testing.T) int {
+
+	ctx := context.Background()
+
+	id, err := productTestRepo.Create(ctx, &models.CreateProduct{
+		Product_name: "Test Stock Product",
+		Brand_id:     1,
+		Category_id:  1,
+		Model_year:   2024,
+		List_price:   100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	productId, _ := strconv.Atoi(id)
+
+	for storeId, quantity := range map[int]int{1: 10, 2: 20} {
+		_, err = stockTestRepo.Create(ctx, &models.CreateStock{Store_id: storeId, Product_id: productId, Quantity: quantity, Actor: "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return productId
+}
+
+func TestGetByIdStock(t *testing.T) {
+
+	productId := createStockTestProduct(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.StockPrimaryKey
+		Output  int
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.StockPrimaryKey{Store_id: 1, Product_id: productId},
+			Output: 10,
+		},
+		{
+			Name:   "Case 2",
+			Input:  &models.StockPrimaryKey{Store_id: 2, Product_id: productId},
+			Output: 20,
+		},
+		{
+			Name:    "Case 3",
+			Input:   &models.StockPrimaryKey{Store_id: 3, Product_id: productId},
+			WantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			stock, err := stockTestRepo.GetByID(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("%s: got: %v", test.Name, err)
+				return
+			}
+
+			if stock.Store_id != test.Input.Store_id || stock.Product_id != test.Input.Product_id || stock.Quantity != test.Output {
+				t.Errorf("%s: got: %+v, expected: quantity %d", test.Name, stock, test.Output)
+			}
+		})
+	}
+}
+
+func TestUpdateStock(t *testing.T) {
+
+	productId := createStockTestProduct(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.UpdateStock
+		Output  int64
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.UpdateStock{Store_id: 1, Product_id: productId, Quantity: 15, Actor: "test"},
+			Output: 1,
+		},
+		{
+			Name:   "Case 2",
+			Input:  &models.UpdateStock{Store_id: 3, Product_id: productId, Quantity: 15, Actor: "test"},
+			Output: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			rowsAffected, err := stockTestRepo.Update(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil || rowsAffected != test.Output {
+				t.Errorf("%s: got: %d, %v, expected: %d", test.Name, rowsAffected, err, test.Output)
+			}
+		})
+	}
+
+	// only the row of the key changed
+	stock, err := stockTestRepo.GetByID(context.Background(), &models.StockPrimaryKey{Store_id: 2, Product_id: productId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stock.Quantity != 20 {
+		t.Errorf("store 2: got: %d, expected: 20", stock.Quantity)
+	}
+}
+
+func TestPatchStock(t *testing.T) {
+
+	productId := createStockTestProduct(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.PatchStock
+		Output  int64
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.PatchStock{Store_id: 2, Product_id: productId, Fields: map[string]interface{}{"quantity": 5}, Actor: "test"},
+			Output: 1,
+		},
+		{
+			Name:    "Case 2",
+			Input:   &models.PatchStock{Store_id: 2, Product_id: productId, Fields: map[string]interface{}{"store_id": 1}, Actor: "test"},
+			WantErr: true,
+		},
+		{
+			Name:    "Case 3",
+			Input:   &models.PatchStock{Store_id: 2, Product_id: productId, Fields: map[string]interface{}{}, Actor: "test"},
+			WantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			rowsAffected, err := stockTestRepo.Patch(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil || rowsAffected != test.Output {
+				t.Errorf("%s: got: %d, %v, expected: %d", test.Name, rowsAffected, err, test.Output)
+			}
+		})
+	}
+}
+
+func TestDeleteStock(t *testing.T) {
+
+	productId := createStockTestProduct(t)
+
+	tests := []struct {
+		Name    string
+		Input   *models.DeleteStock
+		Output  int64
+		WantErr bool
+	}{
+		{
+			Name:   "Case 1",
+			Input:  &models.DeleteStock{Store_id: 1, Product_id: productId, Actor: "test"},
+			Output: 1,
+		},
+		{
+			Name:   "Case 2",
+			Input:  &models.DeleteStock{Store_id: 1, Product_id: productId, Actor: "test"},
+			Output: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			rowsAffected, err := stockTestRepo.Delete(context.Background(), test.Input)
+
+			if test.WantErr {
+				if err == nil {
+					t.Errorf("%s: expected an error", test.Name)
+				}
+				return
+			}
+
+			if err != nil || rowsAffected != test.Output {
+				t.Errorf("%s: got: %d, %v, expected: %d", test.Name, rowsAffected, err, test.Output)
+			}
+		})
+	}
+
+	// the stock of the product in the other store stays
+	_, err := stockTestRepo.GetByID(context.Background(), &models.StockPrimaryKey{Store_id: 2, Product_id: productId})
+	if err != nil {
+		t.Errorf("store 2: got: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs the background jobs until their context is canceled.
+type Group struct {
+	wg sync.WaitGroup
+}
+
+// Go runs job in its own goroutine.
+func (g *Group) Go(ctx context.Context, job func(ctx context.Context)) {
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+		job(ctx)
+	}()
+}
+
+// Wait blocks until every job returned or ctx is done.
+func (g *Group) Wait(ctx context.Context) error {
+
+	done := make(chan struct{})
+
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
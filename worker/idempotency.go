@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"app/pkg/logger"
+	"app/storage"
+)
+
+// idempotencyPurgeInterval is how often the expired idempotency keys are deleted.
+const idempotencyPurgeInterval = time.Hour
+
+// IdempotencyPurgeJob deletes the idempotency keys older than their ttl, Reserve takes over
+// an expired key but the keys never retried would be kept forever.
+type IdempotencyPurgeJob struct {
+	storages storage.StorageI
+	log      logger.LoggerI
+	ttl      time.Duration
+}
+
+func NewIdempotencyPurgeJob(store storage.StorageI, log logger.LoggerI, ttl time.Duration) *IdempotencyPurgeJob {
+	return &IdempotencyPurgeJob{
+		storages: store,
+		log:      log,
+		ttl:      ttl,
+	}
+}
+
+// Run purges the keys right away and then every idempotencyPurgeInterval until ctx is done.
+func (j *IdempotencyPurgeJob) Run(ctx context.Context) {
+
+	if j.ttl <= 0 {
+		j.log.Info("idempotency purge job is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		j.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *IdempotencyPurgeJob) purge(ctx context.Context) {
+
+	deleted, err := j.storages.Idempotency().Purge(ctx, j.ttl)
+	if err != nil {
+		j.log.Error("worker.idempotency.Purge", logger.Error(err))
+		return
+	}
+
+	if deleted > 0 {
+		j.log.Info("worker.idempotency.Purge", logger.Any("deleted", deleted))
+	}
+}
@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"app/api/models"
+	"app/pkg/events"
+	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/storage"
+)
+
+// outboxPurgeInterval is how often the relay deletes the events published before the retention.
+const outboxPurgeInterval = time.Hour
+
+// OutboxRelay publishes the outbox events in order through the publisher, an event that
+// fails to publish stops the batch and is retried on the next run.
+type OutboxRelay struct {
+	storages  storage.StorageI
+	publisher events.EventPublisherI
+	log       logger.LoggerI
+	interval  time.Duration
+	batch     int
+	retention time.Duration
+	lastPurge time.Time
+}
+
+func NewOutboxRelay(store storage.StorageI, publisher events.EventPublisherI, log logger.LoggerI, interval time.Duration, batch int, retention time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		storages:  store,
+		publisher: publisher,
+		log:       log,
+		interval:  interval,
+		batch:     batch,
+		retention: retention,
+	}
+}
+
+// Run relays the events right away and then on every interval until ctx is done.
+func (j *OutboxRelay) Run(ctx context.Context) {
+
+	if j.interval <= 0 {
+		j.log.Info("outbox relay is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		published := j.relay(ctx)
+
+		// a full batch means more events are waiting, the next one is relayed right away
+		if published > 0 && published == j.batch && ctx.Err() == nil {
+			continue
+		}
+
+		j.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay publishes one batch of events and returns how many were published.
+func (j *OutboxRelay) relay(ctx context.Context) int {
+
+	published, err := j.storages.Outbox().Relay(ctx, j.batch, func(ctx context.Context, event *models.OutboxEvent) error {
+		return j.publisher.Publish(ctx, &events.Event{
+			ID:            event.Event_id,
+			Type:          event.Event_type,
+			AggregateType: event.Aggregate_type,
+			AggregateID:   event.Aggregate_id,
+			Payload:       event.Payload,
+			CreatedAt:     event.CreatedAt,
+		})
+	})
+
+	metrics.OutboxPublished.Add(float64(published))
+
+	if err != nil {
+		j.log.Error("worker.outbox.Relay", logger.Int("published", published), logger.Error(err))
+	}
+
+	return published
+}
+
+func (j *OutboxRelay) purge(ctx context.Context) {
+
+	if j.retention <= 0 || time.Since(j.lastPurge) < outboxPurgeInterval {
+		return
+	}
+
+	j.lastPurge = time.Now()
+
+	deleted, err := j.storages.Outbox().Purge(ctx, j.retention)
+	if err != nil {
+		j.log.Error("worker.outbox.Purge", logger.Error(err))
+		return
+	}
+
+	if deleted > 0 {
+		j.log.Info("worker.outbox.Purge", logger.Any("deleted", deleted))
+	}
+}
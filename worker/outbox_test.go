@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"app/api/models"
+	"app/pkg/events"
+	"app/pkg/logger"
+	"app/storage"
+)
+
+// fakeOutboxRepo publishes its unpublished events like the postgres relay, in order up to the first error.
+type fakeOutboxRepo struct {
+	storage.OutboxRepoI
+	events    []*models.OutboxEvent
+	published map[int64]bool
+}
+
+func (r *fakeOutboxRepo) Relay(ctx context.Context, limit int, publish func(context.Context, *models.OutboxEvent) error) (int, error) {
+
+	count := 0
+	for _, event := range r.events {
+		if r.published[event.Event_id] {
+			continue
+		}
+		if count == limit {
+			break
+		}
+
+		err := publish(ctx, event)
+		if err != nil {
+			return count, err
+		}
+
+		r.published[event.Event_id] = true
+		count++
+	}
+
+	return count, nil
+}
+
+type fakeOutboxStorage struct {
+	storage.StorageI
+	outbox *fakeOutboxRepo
+}
+
+func (s *fakeOutboxStorage) Outbox() storage.OutboxRepoI {
+	return s.outbox
+}
+
+func TestOutboxRelayPublishesInOrderAndRetries(t *testing.T) {
+
+	repo := &fakeOutboxRepo{
+		events: []*models.OutboxEvent{
+			{Event_id: 1, Event_type: events.OrderCreated, Aggregate_type: "order", Aggregate_id: "1", Payload: []byte(`{"order_id":1}`)},
+			{Event_id: 2, Event_type: events.OrderItemAdded, Aggregate_type: "order", Aggregate_id: "1", Payload: []byte(`{"order_id":1}`)},
+			{Event_id: 3, Event_type: events.StockAdjusted, Aggregate_type: "stock", Aggregate_id: "1:1", Payload: []byte(`{"quantity":-1}`)},
+		},
+		published: map[int64]bool{},
+	}
+
+	var (
+		received []int64
+		failing  = true
+	)
+
+	publisher := events.NewInProcessPublisher()
+	for _, eventType := range []string{events.OrderCreated, events.OrderItemAdded, events.StockAdjusted} {
+		publisher.Subscribe(eventType, func(ctx context.Context, event *events.Event) error {
+			if event.Type == events.StockAdjusted && failing {
+				return errors.New("consumer is down")
+			}
+			received = append(received, event.ID)
+			return nil
+		})
+	}
+
+	relay := NewOutboxRelay(&fakeOutboxStorage{outbox: repo}, publisher, logger.NewLogger("test", logger.LevelError), time.Second, 10, 0)
+
+	if published := relay.relay(context.Background()); published != 2 {
+		t.Fatalf("first run: got %d published, want 2", published)
+	}
+
+	failing = false
+
+	if published := relay.relay(context.Background()); published != 1 {
+		t.Fatalf("second run: got %d published, want 1", published)
+	}
+
+	if len(received) != 3 || received[0] != 1 || received[1] != 2 || received[2] != 3 {
+		t.Errorf("received: got %v, want [1 2 3]", received)
+	}
+}
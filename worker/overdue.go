@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/api/models"
+	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/pkg/notify"
+	"app/storage"
+)
+
+// unflagTimeout bounds the clearing of the flag of an order whose notification failed.
+const unflagTimeout = 5 * time.Second
+
+// OverdueJob periodically flags the open orders past their required_date
+// and sends one notification for every newly flagged order. An order whose
+// notification fails is unflagged and notified by a later check.
+type OverdueJob struct {
+	storages storage.StorageI
+	sink     notify.SinkI
+	log      logger.LoggerI
+	interval time.Duration
+}
+
+func NewOverdueJob(store storage.StorageI, sink notify.SinkI, log logger.LoggerI, interval time.Duration) *OverdueJob {
+	return &OverdueJob{
+		storages: store,
+		sink:     sink,
+		log:      log,
+		interval: interval,
+	}
+}
+
+// Run checks the orders once right away and then on every interval until ctx is done.
+func (j *OverdueJob) Run(ctx context.Context) {
+
+	if j.interval <= 0 {
+		j.log.Info("overdue job is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *OverdueJob) check(ctx context.Context) {
+
+	orders, err := j.storages.Order().FlagOverdue(ctx)
+	if err != nil {
+		j.log.Error("worker.overdue.FlagOverdue", logger.Error(err))
+		return
+	}
+
+	notified := 0
+
+	for _, order := range orders {
+		err = j.sink.Notify(ctx, &notify.Notification{
+			Kind:      notify.KindOrderOverdue,
+			Subject:   fmt.Sprintf("order %d is %d days overdue", order.Order_id, order.Days_overdue),
+			Payload:   order,
+			CreatedAt: time.Now(),
+		})
+		if err == nil {
+			notified++
+			continue
+		}
+
+		j.log.Error("worker.overdue.Notify", logger.Int("order_id", order.Order_id), logger.Error(err))
+
+		// the order is flagged again by the next check, so its notification is not lost, even
+		// when the notification failed because the job is stopping
+		unflagCtx, cancel := context.WithTimeout(context.Background(), unflagTimeout)
+		err = j.storages.Order().UnflagOverdue(unflagCtx, &models.OrderPrimaryKey{Order_id: order.Order_id})
+		cancel()
+		if err != nil {
+			j.log.Error("worker.overdue.UnflagOverdue", logger.Int("order_id", order.Order_id), logger.Error(err))
+		}
+	}
+
+	metrics.OrdersOverdue.Add(float64(notified))
+
+	if len(orders) > 0 {
+		j.log.Info("worker.overdue", logger.Int("flagged", len(orders)), logger.Int("notified", notified))
+	}
+}
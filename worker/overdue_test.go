@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"app/api/models"
+	"app/pkg/logger"
+	"app/pkg/notify"
+	"app/storage"
+)
+
+// fakeOrderRepo flags its overdue orders like the postgres repo, an order is returned once
+// until it is unflagged.
+type fakeOrderRepo struct {
+	storage.OrderRepoI
+	overdue []int
+	flagged map[int]bool
+}
+
+func (r *fakeOrderRepo) FlagOverdue(ctx context.Context) ([]*models.OverdueOrder, error) {
+
+	var orders []*models.OverdueOrder
+
+	for _, id := range r.overdue {
+		if !r.flagged[id] {
+			r.flagged[id] = true
+			orders = append(orders, &models.OverdueOrder{Order_id: id, Days_overdue: 1})
+		}
+	}
+
+	return orders, nil
+}
+
+func (r *fakeOrderRepo) UnflagOverdue(ctx context.Context, req *models.OrderPrimaryKey) error {
+	delete(r.flagged, req.Order_id)
+	return nil
+}
+
+func (s *fakeStorage) Order() storage.OrderRepoI {
+	return s.order
+}
+
+// fakeSink records the notified orders and fails the orders of failing.
+type fakeSink struct {
+	failing  map[int]bool
+	notified []int
+}
+
+func (s *fakeSink) Notify(ctx context.Context, notification *notify.Notification) error {
+
+	order := notification.Payload.(*models.OverdueOrder)
+	if s.failing[order.Order_id] {
+		return errors.New("sink is down")
+	}
+
+	s.notified = append(s.notified, order.Order_id)
+
+	return nil
+}
+
+func TestOverdueJobRetriesFailedNotifications(t *testing.T) {
+
+	repo := &fakeOrderRepo{overdue: []int{1, 2, 3}, flagged: map[int]bool{}}
+	sink := &fakeSink{failing: map[int]bool{2: true}}
+
+	job := NewOverdueJob(&fakeStorage{order: repo}, sink, logger.NewLogger("test", logger.LevelError), time.Minute)
+
+	job.check(context.Background())
+
+	if len(sink.notified) != 2 || sink.notified[0] != 1 || sink.notified[1] != 3 {
+		t.Fatalf("notified = %v, want [1 3]", sink.notified)
+	}
+
+	if repo.flagged[2] {
+		t.Fatal("the order whose notification failed is still flagged")
+	}
+
+	// the sink is back, only the order missed before is notified
+	sink.failing = nil
+	job.check(context.Background())
+
+	if len(sink.notified) != 3 || sink.notified[2] != 2 {
+		t.Fatalf("notified = %v, want [1 3 2]", sink.notified)
+	}
+
+	job.check(context.Background())
+
+	if len(sink.notified) != 3 {
+		t.Errorf("notified = %v, an order was notified twice", sink.notified)
+	}
+}
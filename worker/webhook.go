@@ -0,0 +1,192 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"app/api/models"
+	"app/pkg/events"
+	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/pkg/webhook"
+	"app/storage"
+)
+
+// webhookSaveTimeout bounds saving an attempt, it runs after the job context may be done.
+const webhookSaveTimeout = 5 * time.Second
+
+// webhookEventTypes maps the outbox events that have webhooks to the webhook event types.
+var webhookEventTypes = map[string]string{
+	events.OrderCreated:       models.WebhookOrderCreated,
+	events.OrderStatusChanged: models.WebhookOrderStatusChanged,
+	events.StockAdjusted:      models.WebhookStockChanged,
+}
+
+// WebhookPublisher queues the deliveries of the outbox events for the webhook job, it runs
+// in the outbox relay next to the event publisher, so the relay has to be on for webhooks.
+type WebhookPublisher struct {
+	storages storage.StorageI
+}
+
+func NewWebhookPublisher(store storage.StorageI) *WebhookPublisher {
+	return &WebhookPublisher{
+		storages: store,
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event *events.Event) error {
+
+	eventType, ok := webhookEventTypes[event.Type]
+	if !ok {
+		return nil
+	}
+
+	_, err := p.storages.Webhook().Enqueue(ctx, &models.WebhookEvent{
+		Event_id:   event.ID,
+		Event_type: eventType,
+		Payload:    event.Payload,
+	})
+
+	return err
+}
+
+func (p *WebhookPublisher) Close() error {
+	return nil
+}
+
+// WebhookBackoff is the retry policy of the failed deliveries, the n-th retry waits Base
+// doubled n-1 times, at most Max, and a delivery is failed after MaxAttempts.
+type WebhookBackoff struct {
+	Base        time.Duration
+	Max         time.Duration
+	MaxAttempts int
+}
+
+// Delay returns how long a delivery waits after its attempts-th failed attempt.
+func (b WebhookBackoff) Delay(attempts int) time.Duration {
+
+	delay := b.Base
+	for i := 1; i < attempts && delay < b.Max; i++ {
+		delay *= 2
+	}
+
+	if delay > b.Max {
+		delay = b.Max
+	}
+
+	return delay
+}
+
+// WebhookJob sends the queued webhook deliveries that are due and schedules the retries.
+// A claimed delivery is hidden from the other jobs for lease, which has to outlast sending
+// the whole batch.
+type WebhookJob struct {
+	storages storage.StorageI
+	sender   *webhook.Sender
+	log      logger.LoggerI
+	interval time.Duration
+	batch    int
+	lease    time.Duration
+	backoff  WebhookBackoff
+}
+
+func NewWebhookJob(store storage.StorageI, sender *webhook.Sender, log logger.LoggerI, interval time.Duration, batch int, lease time.Duration, backoff WebhookBackoff) *WebhookJob {
+	return &WebhookJob{
+		storages: store,
+		sender:   sender,
+		log:      log,
+		interval: interval,
+		batch:    batch,
+		lease:    lease,
+		backoff:  backoff,
+	}
+}
+
+// Run sends the due deliveries right away and then on every interval until ctx is done.
+func (j *WebhookJob) Run(ctx context.Context) {
+
+	if j.interval <= 0 {
+		j.log.Info("webhook job is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		sent := j.send(ctx)
+
+		// a full batch means more deliveries are due, the next one is sent right away
+		if sent > 0 && sent == j.batch && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// send sends one batch of due deliveries and returns its size.
+func (j *WebhookJob) send(ctx context.Context) int {
+
+	deliveries, err := j.storages.Webhook().ClaimDeliveries(ctx, j.batch, j.lease)
+	if err != nil {
+		j.log.Error("worker.webhook.ClaimDeliveries", logger.Error(err))
+		return 0
+	}
+
+	for _, delivery := range deliveries {
+
+		code, err := j.sender.Send(ctx, delivery.Url, delivery.Secret, &webhook.Event{
+			Delivery_id: delivery.Delivery_id,
+			Event_type:  delivery.Event_type,
+			CreatedAt:   delivery.CreatedAt,
+			Data:        delivery.Payload,
+		})
+
+		attempt := j.attempt(delivery, code, err)
+
+		metrics.WebhookDeliveries.WithLabelValues(attempt.Status).Inc()
+
+		// the attempt is saved even when ctx is done, else it is sent again after the lease
+		saveCtx, cancel := context.WithTimeout(context.Background(), webhookSaveTimeout)
+		err = j.storages.Webhook().SaveAttempt(saveCtx, attempt)
+		cancel()
+		if err != nil {
+			j.log.Error("worker.webhook.SaveAttempt", logger.Int("delivery_id", delivery.Delivery_id), logger.Error(err))
+		}
+	}
+
+	return len(deliveries)
+}
+
+// attempt is the outcome of sending delivery, a failed send is retried with backoff
+// until the delivery runs out of attempts.
+func (j *WebhookJob) attempt(delivery *models.DueWebhookDelivery, code int, err error) *models.WebhookDeliveryAttempt {
+
+	attempt := &models.WebhookDeliveryAttempt{
+		Delivery_id:   delivery.Delivery_id,
+		Status:        models.WebhookDeliveryDelivered,
+		Response_code: code,
+	}
+
+	if err == nil {
+		return attempt
+	}
+
+	attempt.Error = err.Error()
+
+	if delivery.Attempts >= j.backoff.MaxAttempts {
+		attempt.Status = models.WebhookDeliveryFailed
+		j.log.Warn("worker.webhook: delivery failed", logger.Int("delivery_id", delivery.Delivery_id), logger.Error(err))
+		return attempt
+	}
+
+	attempt.Status = models.WebhookDeliveryPending
+	attempt.Retry_after = j.backoff.Delay(delivery.Attempts)
+
+	return attempt
+}
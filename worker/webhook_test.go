@@ -0,0 +1,218 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/api/models"
+	"app/pkg/events"
+	"app/pkg/logger"
+	"app/pkg/webhook"
+	"app/storage"
+)
+
+const testSecret = "whsec_test"
+
+// fakeWebhookRepo hands out the queued deliveries once and records the queued events and the saved attempts.
+type fakeWebhookRepo struct {
+	storage.WebhookRepoI
+	queued   []*models.WebhookEvent
+	due      []*models.DueWebhookDelivery
+	attempts []*models.WebhookDeliveryAttempt
+}
+
+func (r *fakeWebhookRepo) Enqueue(ctx context.Context, event *models.WebhookEvent) (int64, error) {
+	r.queued = append(r.queued, event)
+	return 1, nil
+}
+
+func (r *fakeWebhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.DueWebhookDelivery, error) {
+
+	if limit > len(r.due) {
+		limit = len(r.due)
+	}
+
+	claimed := r.due[:limit]
+	r.due = r.due[limit:]
+
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepo) SaveAttempt(ctx context.Context, attempt *models.WebhookDeliveryAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+type fakeStorage struct {
+	storage.StorageI
+	webhook *fakeWebhookRepo
+	order   *fakeOrderRepo
+}
+
+func (s *fakeStorage) Webhook() storage.WebhookRepoI {
+	return s.webhook
+}
+
+func newTestWebhookJob(repo *fakeWebhookRepo) *WebhookJob {
+	return NewWebhookJob(
+		&fakeStorage{webhook: repo},
+		webhook.NewSender(time.Second, true),
+		logger.NewLogger("test", logger.LevelError),
+		time.Minute,
+		10,
+		time.Minute,
+		WebhookBackoff{Base: time.Second, Max: 10 * time.Second, MaxAttempts: 3},
+	)
+}
+
+func TestWebhookJobDeliversSignedEvents(t *testing.T) {
+
+	var received []string
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		body, _ := io.ReadAll(r.Body)
+
+		err := webhook.Verify(testSecret, r.Header.Get(webhook.SignatureHeader), body, time.Minute)
+		if err != nil {
+			t.Errorf("signature: %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		received = append(received, r.Header.Get(webhook.EventHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := &fakeWebhookRepo{
+		due: []*models.DueWebhookDelivery{
+			{Delivery_id: 1, Event_type: models.WebhookOrderCreated, Payload: []byte(`{"order_id":1}`), Attempts: 1, Url: receiver.URL, Secret: testSecret},
+			{Delivery_id: 2, Event_type: models.WebhookStockChanged, Payload: []byte(`{"movement_id":7}`), Attempts: 1, Url: receiver.URL, Secret: testSecret},
+		},
+	}
+
+	sent := newTestWebhookJob(repo).send(context.Background())
+	if sent != 2 {
+		t.Fatalf("sent: got %d, want 2", sent)
+	}
+
+	if len(received) != 2 || received[0] != models.WebhookOrderCreated || received[1] != models.WebhookStockChanged {
+		t.Errorf("received: got %v", received)
+	}
+
+	for _, attempt := range repo.attempts {
+		if attempt.Status != models.WebhookDeliveryDelivered || attempt.Response_code != http.StatusNoContent {
+			t.Errorf("delivery %d: got %s %d", attempt.Delivery_id, attempt.Status, attempt.Response_code)
+		}
+	}
+}
+
+func TestWebhookJobRetriesWithBackoff(t *testing.T) {
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	tests := []struct {
+		Name       string
+		Attempts   int
+		Status     string
+		RetryAfter time.Duration
+	}{
+		{Name: "first failure", Attempts: 1, Status: models.WebhookDeliveryPending, RetryAfter: time.Second},
+		{Name: "second failure", Attempts: 2, Status: models.WebhookDeliveryPending, RetryAfter: 2 * time.Second},
+		{Name: "out of attempts", Attempts: 3, Status: models.WebhookDeliveryFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			repo := &fakeWebhookRepo{
+				due: []*models.DueWebhookDelivery{
+					{Delivery_id: 1, Event_type: models.WebhookOrderCreated, Payload: []byte(`{}`), Attempts: test.Attempts, Url: receiver.URL, Secret: testSecret},
+				},
+			}
+
+			newTestWebhookJob(repo).send(context.Background())
+
+			if len(repo.attempts) != 1 {
+				t.Fatalf("attempts: got %d, want 1", len(repo.attempts))
+			}
+
+			attempt := repo.attempts[0]
+			if attempt.Status != test.Status || attempt.Retry_after != test.RetryAfter || attempt.Response_code != http.StatusServiceUnavailable {
+				t.Errorf("got %s after %s with %d, want %s after %s", attempt.Status, attempt.Retry_after, attempt.Response_code, test.Status, test.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestWebhookPublisherQueuesTheWebhookEvents(t *testing.T) {
+
+	repo := &fakeWebhookRepo{}
+	publisher := NewWebhookPublisher(&fakeStorage{webhook: repo})
+
+	tests := []struct {
+		Name   string
+		Event  *events.Event
+		Output string
+	}{
+		{Name: "order created", Event: &events.Event{ID: 1, Type: events.OrderCreated}, Output: models.WebhookOrderCreated},
+		{Name: "order item added", Event: &events.Event{ID: 2, Type: events.OrderItemAdded}},
+		{Name: "order status changed", Event: &events.Event{ID: 3, Type: events.OrderStatusChanged}, Output: models.WebhookOrderStatusChanged},
+		{Name: "stock adjusted", Event: &events.Event{ID: 4, Type: events.StockAdjusted}, Output: models.WebhookStockChanged},
+		{Name: "product price changed", Event: &events.Event{ID: 5, Type: events.ProductPriceChanged}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			repo.queued = nil
+			test.Event.Payload = []byte(`{"id":1}`)
+
+			err := publisher.Publish(context.Background(), test.Event)
+			if err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+
+			if test.Output == "" {
+				if len(repo.queued) != 0 {
+					t.Errorf("got %d queued events, want none", len(repo.queued))
+				}
+				return
+			}
+
+			if len(repo.queued) != 1 {
+				t.Fatalf("got %d queued events, want 1", len(repo.queued))
+			}
+
+			queued := repo.queued[0]
+			if queued.Event_id != test.Event.ID || queued.Event_type != test.Output || string(queued.Payload) != `{"id":1}` {
+				t.Errorf("got %+v, want event %d as %s", queued, test.Event.ID, test.Output)
+			}
+		})
+	}
+}
+
+func TestWebhookBackoffDelay(t *testing.T) {
+
+	backoff := WebhookBackoff{Base: 30 * time.Second, Max: 5 * time.Minute}
+
+	for attempts, want := range map[int]time.Duration{
+		1: 30 * time.Second,
+		2: time.Minute,
+		4: 4 * time.Minute,
+		5: 5 * time.Minute,
+		9: 5 * time.Minute,
+	} {
+		if got := backoff.Delay(attempts); got != want {
+			t.Errorf("attempt %d: got %s, want %s", attempts, got, want)
+		}
+	}
+}